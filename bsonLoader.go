@@ -0,0 +1,387 @@
+package gojsonschema
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/xeipuuv/gojsonreference"
+)
+
+// BSON loader
+// Decodes a BSON document ( http://bsonspec.org/spec.html ) into a tree of typed values:
+// documents become map[string]interface{}, arrays []interface{}, int32, int64 and
+// float64 are kept as such and the other BSON types use the types of bsonTypes.go
+
+type bsonLoader struct {
+	source []byte
+}
+
+func (l *bsonLoader) JsonSource() interface{} {
+	return l.source
+}
+
+func (l *bsonLoader) JsonReference() (gojsonreference.JsonReference, error) {
+	return gojsonreference.NewJsonReference("#")
+}
+
+func (l *bsonLoader) LoaderFactory() JSONLoaderFactory {
+	return &DefaultJSONLoaderFactory{}
+}
+
+// NewBSONLoader returns a loader for a raw BSON document
+func NewBSONLoader(source []byte) *bsonLoader {
+	return &bsonLoader{source: source}
+}
+
+func (l *bsonLoader) LoadJSON() (interface{}, error) {
+	return decodeBSON(l.JsonSource().([]byte))
+}
+
+// Nesting limit, protects the decoder against stack exhaustion
+const bsonMaxDepth = 1000
+
+type bsonDecoder struct {
+	data []byte
+	pos  int
+}
+
+func decodeBSON(data []byte) (interface{}, error) {
+
+	d := &bsonDecoder{data: data}
+
+	document, err := d.readDocument(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.pos != len(d.data) {
+		return nil, d.error("trailing bytes after the document")
+	}
+
+	return document, nil
+}
+
+func (d *bsonDecoder) error(reason string) error {
+	return errors.New(formatErrorDescription(
+		Locale.BSONParseError(),
+		ErrorDetails{"offset": d.pos, "reason": reason},
+	))
+}
+
+func (d *bsonDecoder) need(n int) error {
+	if n < 0 || len(d.data)-d.pos < n {
+		return d.error("unexpected end of document")
+	}
+	return nil
+}
+
+func (d *bsonDecoder) readBytes(n int) ([]byte, error) {
+	if err := d.need(n); err != nil {
+		return nil, err
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+func (d *bsonDecoder) readInt32() (int32, error) {
+	b, err := d.readBytes(4)
+	if err != nil {
+		return 0, err
+	}
+	return int32(binary.LittleEndian.Uint32(b)), nil
+}
+
+func (d *bsonDecoder) readUint64() (uint64, error) {
+	b, err := d.readBytes(8)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(b), nil
+}
+
+func (d *bsonDecoder) readCString() (string, error) {
+	for i := d.pos; i < len(d.data); i++ {
+		if d.data[i] == 0 {
+			s := string(d.data[d.pos:i])
+			if !utf8.ValidString(s) {
+				return "", d.error("invalid UTF-8 string")
+			}
+			d.pos = i + 1
+			return s, nil
+		}
+	}
+	return "", d.error("unterminated cstring")
+}
+
+func (d *bsonDecoder) readString() (string, error) {
+	length, err := d.readInt32()
+	if err != nil {
+		return "", err
+	}
+	if length < 1 {
+		return "", d.error("invalid string length " + strconv.Itoa(int(length)))
+	}
+	b, err := d.readBytes(int(length))
+	if err != nil {
+		return "", err
+	}
+	if b[length-1] != 0 {
+		return "", d.error("string is not null terminated")
+	}
+	s := string(b[:length-1])
+	if !utf8.ValidString(s) {
+		return "", d.error("invalid UTF-8 string")
+	}
+	return s, nil
+}
+
+// readElements reads the elements of a document or an array, calling add for each of them
+func (d *bsonDecoder) readElements(depth int, add func(key string, value interface{})) error {
+
+	if depth > bsonMaxDepth {
+		return d.error("document is nested too deeply")
+	}
+
+	start := d.pos
+	length, err := d.readInt32()
+	if err != nil {
+		return err
+	}
+	if length < 5 {
+		return d.error("invalid document length " + strconv.Itoa(int(length)))
+	}
+	d.pos = start
+	if err := d.need(int(length)); err != nil {
+		return err
+	}
+	end := start + int(length)
+
+	// restrict the decoder to the document while reading its elements
+	data := d.data
+	d.data = data[:end]
+	defer func() { d.data = data }()
+	d.pos += 4
+
+	for {
+		elementType, err := d.readBytes(1)
+		if err != nil {
+			return err
+		}
+		if elementType[0] == 0x00 {
+			break
+		}
+		key, err := d.readCString()
+		if err != nil {
+			return err
+		}
+		value, err := d.readValue(elementType[0], depth)
+		if err != nil {
+			return err
+		}
+		add(key, value)
+	}
+
+	if d.pos != end {
+		return d.error("document length does not match its content")
+	}
+
+	return nil
+}
+
+func (d *bsonDecoder) readDocument(depth int) (map[string]interface{}, error) {
+	document := map[string]interface{}{}
+	err := d.readElements(depth, func(key string, value interface{}) {
+		document[key] = value
+	})
+	if err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+func (d *bsonDecoder) readArray(depth int) ([]interface{}, error) {
+	array := []interface{}{}
+	err := d.readElements(depth, func(key string, value interface{}) {
+		array = append(array, value)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return array, nil
+}
+
+func (d *bsonDecoder) readValue(elementType byte, depth int) (interface{}, error) {
+
+	switch elementType {
+
+	case 0x01: // double
+		bits, err := d.readUint64()
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(bits), nil
+
+	case 0x02: // string
+		return d.readString()
+
+	case 0x03: // embedded document
+		return d.readDocument(depth + 1)
+
+	case 0x04: // array
+		return d.readArray(depth + 1)
+
+	case 0x05: // binary
+		length, err := d.readInt32()
+		if err != nil {
+			return nil, err
+		}
+		subtype, err := d.readBytes(1)
+		if err != nil {
+			return nil, err
+		}
+		data, err := d.readBytes(int(length))
+		if err != nil {
+			return nil, err
+		}
+		if subtype[0] == 0x02 {
+			// old binary subtype, the data is prefixed with its own length
+			if len(data) < 4 || int(binary.LittleEndian.Uint32(data)) != len(data)-4 {
+				return nil, d.error("invalid binary subtype 2 length")
+			}
+			data = data[4:]
+		}
+		return Binary{Subtype: subtype[0], Data: append([]byte{}, data...)}, nil
+
+	case 0x06: // undefined
+		return Undefined{}, nil
+
+	case 0x07: // ObjectId
+		b, err := d.readBytes(12)
+		if err != nil {
+			return nil, err
+		}
+		var id ObjectID
+		copy(id[:], b)
+		return id, nil
+
+	case 0x08: // boolean
+		b, err := d.readBytes(1)
+		if err != nil {
+			return nil, err
+		}
+		switch b[0] {
+		case 0x00:
+			return false, nil
+		case 0x01:
+			return true, nil
+		}
+		return nil, d.error("invalid boolean value")
+
+	case 0x09: // UTC datetime
+		ms, err := d.readUint64()
+		if err != nil {
+			return nil, err
+		}
+		return DateTime(int64(ms)), nil
+
+	case 0x0A: // null
+		return nil, nil
+
+	case 0x0B: // regular expression
+		pattern, err := d.readCString()
+		if err != nil {
+			return nil, err
+		}
+		options, err := d.readCString()
+		if err != nil {
+			return nil, err
+		}
+		return Regex{Pattern: pattern, Options: options}, nil
+
+	case 0x0C: // DBPointer
+		db, err := d.readString()
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.readBytes(12)
+		if err != nil {
+			return nil, err
+		}
+		pointer := DBPointer{DB: db}
+		copy(pointer.Pointer[:], b)
+		return pointer, nil
+
+	case 0x0D: // JavaScript code
+		code, err := d.readString()
+		if err != nil {
+			return nil, err
+		}
+		return JavaScript(code), nil
+
+	case 0x0E: // symbol
+		symbol, err := d.readString()
+		if err != nil {
+			return nil, err
+		}
+		return Symbol(symbol), nil
+
+	case 0x0F: // JavaScript code with scope
+		start := d.pos
+		length, err := d.readInt32()
+		if err != nil {
+			return nil, err
+		}
+		code, err := d.readString()
+		if err != nil {
+			return nil, err
+		}
+		scope, err := d.readDocument(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if d.pos-start != int(length) {
+			return nil, d.error("code with scope length does not match its content")
+		}
+		return CodeWithScope{Code: JavaScript(code), Scope: scope}, nil
+
+	case 0x10: // int32
+		return d.readInt32()
+
+	case 0x11: // timestamp
+		v, err := d.readUint64()
+		if err != nil {
+			return nil, err
+		}
+		return Timestamp{T: uint32(v >> 32), I: uint32(v)}, nil
+
+	case 0x12: // int64
+		v, err := d.readUint64()
+		if err != nil {
+			return nil, err
+		}
+		return int64(v), nil
+
+	case 0x13: // decimal128
+		l, err := d.readUint64()
+		if err != nil {
+			return nil, err
+		}
+		h, err := d.readUint64()
+		if err != nil {
+			return nil, err
+		}
+		return NewDecimal128(h, l), nil
+
+	case 0xFF: // min key
+		return MinKey{}, nil
+
+	case 0x7F: // max key
+		return MaxKey{}, nil
+	}
+
+	return nil, d.error("unknown element type 0x" + strconv.FormatUint(uint64(elementType), 16))
+}
@@ -0,0 +1,184 @@
+package gojsonschema
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// bsonElement and bsonDocument build raw BSON for the tests
+type bsonElement struct {
+	kind  byte
+	key   string
+	value []byte
+}
+
+func bsonDocument(elements ...bsonElement) []byte {
+	var body bytes.Buffer
+	for _, e := range elements {
+		body.WriteByte(e.kind)
+		body.WriteString(e.key)
+		body.WriteByte(0)
+		body.Write(e.value)
+	}
+	body.WriteByte(0)
+	return append(bsonInt32(int32(body.Len()+4)), body.Bytes()...)
+}
+
+func bsonInt32(i int32) []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, uint32(i))
+	return b
+}
+
+func bsonUint64(i uint64) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, i)
+	return b
+}
+
+func bsonString(s string) []byte {
+	return append(append(bsonInt32(int32(len(s)+1)), s...), 0)
+}
+
+func TestBSONLoader(t *testing.T) {
+	id := []byte{0x5a, 0x93, 0x4e, 0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x00, 0x00, 0x00}
+
+	doc := bsonDocument(
+		bsonElement{0x07, "_id", id},
+		bsonElement{0x02, "name", bsonString("widget")},
+		bsonElement{0x10, "count", bsonInt32(3)},
+		bsonElement{0x12, "big", bsonUint64(1 << 40)},
+		bsonElement{0x01, "ratio", bsonUint64(math.Float64bits(0.5))},
+		bsonElement{0x09, "created", bsonUint64(1519603200000)},
+		bsonElement{0x13, "price", append(bsonUint64(1999), bsonUint64(uint64(6176-2)<<49)...)},
+		bsonElement{0x08, "active", []byte{1}},
+		bsonElement{0x0A, "nothing", nil},
+		bsonElement{0x04, "tags", bsonDocument(
+			bsonElement{0x02, "0", bsonString("a")},
+			bsonElement{0x02, "1", bsonString("b")},
+		)},
+		bsonElement{0x03, "nested", bsonDocument(
+			bsonElement{0x11, "ts", bsonUint64(5<<32 | 1)},
+			bsonElement{0x05, "bin", append(append(bsonInt32(2), 0x00), 0xCA, 0xFE)},
+			bsonElement{0x0B, "re", []byte("^a\x00i\x00")},
+		)},
+	)
+
+	value, err := NewBSONLoader(doc).LoadJSON()
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	var objectID ObjectID
+	copy(objectID[:], id)
+
+	assert.Equal(t, map[string]interface{}{
+		"_id":     objectID,
+		"name":    "widget",
+		"count":   int32(3),
+		"big":     int64(1 << 40),
+		"ratio":   0.5,
+		"created": DateTime(1519603200000),
+		"price":   NewDecimal128(uint64(6176-2)<<49, 1999),
+		"active":  true,
+		"nothing": nil,
+		"tags":    []interface{}{"a", "b"},
+		"nested": map[string]interface{}{
+			"ts":  Timestamp{T: 5, I: 1},
+			"bin": Binary{Subtype: 0, Data: []byte{0xCA, 0xFE}},
+			"re":  Regex{Pattern: "^a", Options: "i"},
+		},
+	}, value)
+}
+
+func TestBSONLoaderErrors(t *testing.T) {
+	valid := bsonDocument(bsonElement{0x10, "a", bsonInt32(1)})
+
+	invalid := [][]byte{
+		{},
+		valid[:len(valid)-1],
+		append(append([]byte{}, valid...), 0),
+		bsonDocument(bsonElement{0x08, "a", []byte{2}}),
+		bsonDocument(bsonElement{0x02, "a", append(bsonInt32(2), 'a', 'b')}),
+		bsonDocument(bsonElement{0x02, "a", bsonString("\xff")}),
+		bsonDocument(bsonElement{0x42, "a", nil}),
+	}
+
+	for _, doc := range invalid {
+		_, err := NewBSONLoader(doc).LoadJSON()
+		assert.NotNil(t, err, "document: %v", doc)
+	}
+}
+
+func TestBSONNumericValidation(t *testing.T) {
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {
+			"count": {"type": "integer", "minimum": 5},
+			"ratio": {"type": "number", "multipleOf": 0.25},
+			"whole": {"type": "integer"},
+			"price": {"type": "number", "maximum": 20}
+		}
+	}`))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	valid := bsonDocument(
+		bsonElement{0x10, "count", bsonInt32(5)},
+		bsonElement{0x01, "ratio", bsonUint64(math.Float64bits(0.75))},
+		bsonElement{0x01, "whole", bsonUint64(math.Float64bits(2))},
+		bsonElement{0x13, "price", append(bsonUint64(1999), bsonUint64(uint64(6176-2)<<49)...)},
+	)
+	result, err := schema.Validate(NewBSONLoader(valid))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid(), "%v", result.Errors())
+
+	invalid := bsonDocument(
+		bsonElement{0x12, "count", bsonUint64(4)},
+		bsonElement{0x01, "ratio", bsonUint64(math.Float64bits(0.1))},
+		bsonElement{0x01, "whole", bsonUint64(math.Float64bits(2.5))},
+		bsonElement{0x13, "price", append(bsonUint64(2001), bsonUint64(uint64(6176-2)<<49)...)},
+	)
+	result, err = schema.Validate(NewBSONLoader(invalid))
+	assert.Nil(t, err)
+
+	types := map[string]string{}
+	for _, e := range result.Errors() {
+		types[e.Field()] = e.Type()
+	}
+	assert.Equal(t, map[string]string{
+		"count": "number_gte",
+		"ratio": "multiple_of",
+		"whole": "invalid_type",
+		"price": "number_lte",
+	}, types)
+}
+
+func TestBSONSchema(t *testing.T) {
+	sl := NewSchemaLoader()
+	sl.Dialect = DialectMongoDB
+
+	// a collection validator as returned by listCollections
+	schema, err := sl.Compile(NewBSONLoader(bsonDocument(
+		bsonElement{0x03, "$jsonSchema", bsonDocument(
+			bsonElement{0x02, "bsonType", bsonString("object")},
+			bsonElement{0x03, "properties", bsonDocument(
+				bsonElement{0x03, "count", bsonDocument(
+					bsonElement{0x02, "bsonType", bsonString("int")},
+					bsonElement{0x10, "maximum", bsonInt32(10)},
+				)},
+			)},
+		)},
+	)))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	result, err := schema.Validate(NewBSONLoader(bsonDocument(bsonElement{0x10, "count", bsonInt32(11)})))
+	assert.Nil(t, err)
+	assert.Len(t, result.Errors(), 1)
+}
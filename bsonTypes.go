@@ -0,0 +1,544 @@
+package gojsonschema
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BSON types names as used by the bsonType keyword of MongoDB
+const (
+	BSON_TYPE_DOUBLE                = "double"
+	BSON_TYPE_STRING                = "string"
+	BSON_TYPE_OBJECT                = "object"
+	BSON_TYPE_ARRAY                 = "array"
+	BSON_TYPE_BINARY                = "binData"
+	BSON_TYPE_UNDEFINED             = "undefined"
+	BSON_TYPE_OBJECT_ID             = "objectId"
+	BSON_TYPE_BOOL                  = "bool"
+	BSON_TYPE_DATE                  = "date"
+	BSON_TYPE_NULL                  = "null"
+	BSON_TYPE_REGEX                 = "regex"
+	BSON_TYPE_DB_POINTER            = "dbPointer"
+	BSON_TYPE_JAVASCRIPT            = "javascript"
+	BSON_TYPE_SYMBOL                = "symbol"
+	BSON_TYPE_JAVASCRIPT_WITH_SCOPE = "javascriptWithScope"
+	BSON_TYPE_INT                   = "int"
+	BSON_TYPE_TIMESTAMP             = "timestamp"
+	BSON_TYPE_LONG                  = "long"
+	BSON_TYPE_DECIMAL               = "decimal"
+	BSON_TYPE_MIN_KEY               = "minKey"
+	BSON_TYPE_MAX_KEY               = "maxKey"
+
+	// BSON_TYPE_NUMBER is an alias matching int, long, double and decimal
+	BSON_TYPE_NUMBER = "number"
+)
+
+var BSON_TYPES = []string{
+	BSON_TYPE_DOUBLE,
+	BSON_TYPE_STRING,
+	BSON_TYPE_OBJECT,
+	BSON_TYPE_ARRAY,
+	BSON_TYPE_BINARY,
+	BSON_TYPE_UNDEFINED,
+	BSON_TYPE_OBJECT_ID,
+	BSON_TYPE_BOOL,
+	BSON_TYPE_DATE,
+	BSON_TYPE_NULL,
+	BSON_TYPE_REGEX,
+	BSON_TYPE_DB_POINTER,
+	BSON_TYPE_JAVASCRIPT,
+	BSON_TYPE_SYMBOL,
+	BSON_TYPE_JAVASCRIPT_WITH_SCOPE,
+	BSON_TYPE_INT,
+	BSON_TYPE_TIMESTAMP,
+	BSON_TYPE_LONG,
+	BSON_TYPE_DECIMAL,
+	BSON_TYPE_MIN_KEY,
+	BSON_TYPE_MAX_KEY,
+	BSON_TYPE_NUMBER,
+}
+
+// Go representations of the BSON values that have no JSON equivalent.
+// Their JSON encoding is canonical MongoDB Extended JSON, so they can be compared
+// by enum, const and uniqueItems and are displayed in errors.
+type (
+	// ObjectID is a BSON ObjectId
+	ObjectID [12]byte
+
+	// DateTime is a BSON UTC datetime, in milliseconds since the Unix epoch
+	DateTime int64
+
+	// Timestamp is a BSON (internal) timestamp
+	Timestamp struct {
+		T uint32
+		I uint32
+	}
+
+	// Binary is BSON binary data
+	Binary struct {
+		Subtype byte
+		Data    []byte
+	}
+
+	// Regex is a BSON regular expression
+	Regex struct {
+		Pattern string
+		Options string
+	}
+
+	// Decimal128 is a BSON 128-bit IEEE 754-2008 decimal floating point
+	Decimal128 struct {
+		h, l uint64
+	}
+
+	// JavaScript is BSON JavaScript code
+	JavaScript string
+
+	// CodeWithScope is BSON JavaScript code with scope
+	CodeWithScope struct {
+		Code  JavaScript
+		Scope interface{}
+	}
+
+	// Symbol is a BSON symbol (deprecated)
+	Symbol string
+
+	// DBPointer is a BSON DBPointer (deprecated)
+	DBPointer struct {
+		DB      string
+		Pointer ObjectID
+	}
+
+	// Undefined is the BSON undefined value (deprecated)
+	Undefined struct{}
+
+	// MinKey is the BSON value comparing lower than all other values
+	MinKey struct{}
+
+	// MaxKey is the BSON value comparing higher than all other values
+	MaxKey struct{}
+)
+
+// ObjectIDFromHex parses the 24 hex characters representation of an ObjectId
+func ObjectIDFromHex(s string) (ObjectID, error) {
+	var id ObjectID
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return id, err
+	}
+	if len(b) != len(id) {
+		return id, errors.New("an ObjectId must be 12 bytes long")
+	}
+	copy(id[:], b)
+	return id, nil
+}
+
+func (id ObjectID) Hex() string {
+	return hex.EncodeToString(id[:])
+}
+
+func (id ObjectID) String() string {
+	return fmt.Sprintf("ObjectID(%q)", id.Hex())
+}
+
+func (id ObjectID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"$oid": id.Hex()})
+}
+
+// NewDateTimeFromTime converts a time.Time to a DateTime, truncated to the millisecond
+func NewDateTimeFromTime(t time.Time) DateTime {
+	return DateTime(t.Unix()*1000 + int64(t.Nanosecond()/1e6))
+}
+
+func (d DateTime) Time() time.Time {
+	return time.Unix(int64(d)/1000, int64(d)%1000*1e6).UTC()
+}
+
+func (d DateTime) String() string {
+	return d.Time().Format(time.RFC3339Nano)
+}
+
+func (d DateTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{"$date": map[string]string{"$numberLong": strconv.FormatInt(int64(d), 10)}})
+}
+
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{"$timestamp": map[string]uint32{"t": t.T, "i": t.I}})
+}
+
+func (b Binary) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{"$binary": map[string]string{
+		"base64":  base64.StdEncoding.EncodeToString(b.Data),
+		"subType": fmt.Sprintf("%02x", b.Subtype),
+	}})
+}
+
+func (r Regex) String() string {
+	return "/" + r.Pattern + "/" + r.Options
+}
+
+func (r Regex) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{"$regularExpression": map[string]string{
+		"pattern": r.Pattern,
+		"options": r.Options,
+	}})
+}
+
+func (c JavaScript) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"$code": string(c)})
+}
+
+func (c CodeWithScope) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{"$code": string(c.Code), "$scope": c.Scope})
+}
+
+func (s Symbol) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"$symbol": string(s)})
+}
+
+func (p DBPointer) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{"$dbPointer": map[string]interface{}{"$ref": p.DB, "$id": p.Pointer}})
+}
+
+func (Undefined) MarshalJSON() ([]byte, error) {
+	return []byte(`{"$undefined":true}`), nil
+}
+
+func (MinKey) MarshalJSON() ([]byte, error) {
+	return []byte(`{"$minKey":1}`), nil
+}
+
+func (MaxKey) MarshalJSON() ([]byte, error) {
+	return []byte(`{"$maxKey":1}`), nil
+}
+
+// NewDecimal128 creates a Decimal128 from its high and low 64 bits
+func NewDecimal128(h, l uint64) Decimal128 {
+	return Decimal128{h: h, l: l}
+}
+
+// GetBytes returns the high and low 64 bits of the Decimal128
+func (d Decimal128) GetBytes() (uint64, uint64) {
+	return d.h, d.l
+}
+
+// IsNaN reports whether d is NaN
+func (d Decimal128) IsNaN() bool {
+	return d.h>>58&0x1F == 0x1F
+}
+
+// IsInf reports whether d is an infinity
+func (d Decimal128) IsInf() bool {
+	return d.h>>58&0x1F == 0x1E
+}
+
+// decompose returns the sign, coefficient and exponent of a finite Decimal128
+func (d Decimal128) decompose() (negative bool, coefficient *big.Int, exponent int) {
+	negative = d.h>>63 == 1
+
+	var high uint64
+	if d.h>>61&3 == 3 {
+		// the coefficient of this form is always larger than 10^34 - 1, hence non canonical and zero
+		exponent = int(d.h >> 47 & (1<<14 - 1))
+		coefficient = new(big.Int)
+	} else {
+		exponent = int(d.h >> 49 & (1<<14 - 1))
+		high = d.h & (1<<49 - 1)
+		coefficient = new(big.Int).Lsh(new(big.Int).SetUint64(high), 64)
+		coefficient.Or(coefficient, new(big.Int).SetUint64(d.l))
+		if coefficient.Cmp(maxDecimal128Coefficient) > 0 {
+			coefficient.SetInt64(0)
+		}
+	}
+
+	return negative, coefficient, exponent - decimal128ExponentBias
+}
+
+const (
+	decimal128ExponentBias = 6176
+	decimal128MaxExponent  = 6111
+	decimal128MinExponent  = -6176
+)
+
+var maxDecimal128Coefficient, _ = new(big.Int).SetString("9999999999999999999999999999999999", 10)
+
+// String formats the Decimal128 according to the BSON decimal128 specification
+func (d Decimal128) String() string {
+
+	if d.IsNaN() {
+		return "NaN"
+	}
+
+	negative, coefficient, exponent := d.decompose()
+
+	sign := ""
+	if negative {
+		sign = "-"
+	}
+
+	if d.IsInf() {
+		return sign + "Infinity"
+	}
+
+	digits := coefficient.String()
+	adjusted := exponent + len(digits) - 1
+
+	if exponent > 0 || adjusted < -6 {
+		// scientific notation
+		s := digits[:1]
+		if len(digits) > 1 {
+			s += "." + digits[1:]
+		}
+		return fmt.Sprintf("%s%sE%+d", sign, s, adjusted)
+	}
+
+	if exponent == 0 {
+		return sign + digits
+	}
+
+	// plain notation with a decimal point
+	point := len(digits) + exponent
+	if point > 0 {
+		return sign + digits[:point] + "." + digits[point:]
+	}
+	return sign + "0." + strings.Repeat("0", -point) + digits
+}
+
+// ParseDecimal128 parses the string representation of a decimal128, as produced by String.
+// Values that cannot be represented exactly are rejected.
+func ParseDecimal128(s string) (Decimal128, error) {
+
+	invalid := errors.New("cannot parse " + strconv.Quote(s) + " as a decimal128")
+
+	negative := false
+	str := s
+	if strings.HasPrefix(str, "-") || strings.HasPrefix(str, "+") {
+		negative = str[0] == '-'
+		str = str[1:]
+	}
+
+	var h uint64
+	if negative {
+		h = 1 << 63
+	}
+
+	switch strings.ToLower(str) {
+	case "nan":
+		return Decimal128{h: 0x1F << 58}, nil
+	case "inf", "infinity":
+		return Decimal128{h: h | 0x1E<<58}, nil
+	}
+
+	exponent := 0
+	if i := strings.IndexAny(str, "eE"); i >= 0 {
+		e, err := strconv.Atoi(str[i+1:])
+		if err != nil {
+			return Decimal128{}, invalid
+		}
+		exponent = e
+		str = str[:i]
+	}
+
+	if i := strings.Index(str, "."); i >= 0 {
+		exponent -= len(str) - i - 1
+		str = str[:i] + str[i+1:]
+	}
+
+	if str == "" || strings.IndexFunc(str, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+		return Decimal128{}, invalid
+	}
+
+	coefficient, _ := new(big.Int).SetString(str, 10)
+
+	// clamp the exponent without losing digits
+	ten := big.NewInt(10)
+	for exponent > decimal128MaxExponent {
+		if coefficient.Sign() == 0 {
+			exponent = decimal128MaxExponent
+			break
+		}
+		next := new(big.Int).Mul(coefficient, ten)
+		if next.Cmp(maxDecimal128Coefficient) > 0 {
+			return Decimal128{}, invalid
+		}
+		coefficient = next
+		exponent--
+	}
+	for exponent < decimal128MinExponent || coefficient.Cmp(maxDecimal128Coefficient) > 0 {
+		if coefficient.Sign() == 0 {
+			exponent = decimal128MinExponent
+			break
+		}
+		quotient, remainder := new(big.Int).QuoRem(coefficient, ten, new(big.Int))
+		if remainder.Sign() != 0 || exponent == decimal128MaxExponent {
+			return Decimal128{}, invalid
+		}
+		coefficient = quotient
+		exponent++
+	}
+
+	biased := uint64(exponent + decimal128ExponentBias)
+	mask := new(big.Int).SetUint64(math.MaxUint64)
+	l := new(big.Int).And(coefficient, mask).Uint64()
+	high := new(big.Int).Rsh(coefficient, 64).Uint64()
+
+	h |= biased<<49 | high
+	return Decimal128{h: h, l: l}, nil
+}
+
+func (d Decimal128) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"$numberDecimal": d.String()})
+}
+
+// bsonValueType returns the BSON type name of a value in a decoded document
+func bsonValueType(value interface{}) string {
+
+	switch v := value.(type) {
+	case nil:
+		return BSON_TYPE_NULL
+	case bool:
+		return BSON_TYPE_BOOL
+	case string:
+		return BSON_TYPE_STRING
+	case int32, int8, int16, uint8, uint16:
+		return BSON_TYPE_INT
+	case int64, uint32:
+		return BSON_TYPE_LONG
+	case int:
+		if v >= math.MinInt32 && v <= math.MaxInt32 {
+			return BSON_TYPE_INT
+		}
+		return BSON_TYPE_LONG
+	case float64, float32:
+		return BSON_TYPE_DOUBLE
+	case json.Number:
+		// decoded from JSON, the smallest fitting BSON type is used like MongoDB tools do
+		if i, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+			if i >= math.MinInt32 && i <= math.MaxInt32 {
+				return BSON_TYPE_INT
+			}
+			return BSON_TYPE_LONG
+		}
+		return BSON_TYPE_DOUBLE
+	case []interface{}:
+		return BSON_TYPE_ARRAY
+	case map[string]interface{}, map[interface{}]interface{}:
+		return BSON_TYPE_OBJECT
+	}
+
+	if t, ok := bsonScalarType(value); ok {
+		return t
+	}
+
+	return typeOfGoValue(value)
+}
+
+// bsonScalarType returns the BSON type name of the BSON values that have no JSON equivalent
+func bsonScalarType(value interface{}) (string, bool) {
+
+	switch value.(type) {
+	case ObjectID:
+		return BSON_TYPE_OBJECT_ID, true
+	case DateTime, time.Time:
+		return BSON_TYPE_DATE, true
+	case Timestamp:
+		return BSON_TYPE_TIMESTAMP, true
+	case Binary:
+		return BSON_TYPE_BINARY, true
+	case Regex:
+		return BSON_TYPE_REGEX, true
+	case Decimal128:
+		return BSON_TYPE_DECIMAL, true
+	case JavaScript:
+		return BSON_TYPE_JAVASCRIPT, true
+	case CodeWithScope:
+		return BSON_TYPE_JAVASCRIPT_WITH_SCOPE, true
+	case Symbol:
+		return BSON_TYPE_SYMBOL, true
+	case DBPointer:
+		return BSON_TYPE_DB_POINTER, true
+	case Undefined:
+		return BSON_TYPE_UNDEFINED, true
+	case MinKey:
+		return BSON_TYPE_MIN_KEY, true
+	case MaxKey:
+		return BSON_TYPE_MAX_KEY, true
+	}
+
+	return "", false
+}
+
+// typeOfGoValue maps other Go values by kind
+func typeOfGoValue(value interface{}) string {
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Slice, reflect.Array:
+		return BSON_TYPE_ARRAY
+	case reflect.Map, reflect.Struct:
+		return BSON_TYPE_OBJECT
+	}
+	return STRING_UNDEFINED
+}
+
+// bsonSchemaType is the bsonType counterpart of jsonSchemaType
+type bsonSchemaType struct {
+	types []string
+}
+
+func (t *bsonSchemaType) IsTyped() bool {
+	return len(t.types) > 0
+}
+
+func (t *bsonSchemaType) Add(etype string) error {
+
+	if !isStringInSlice(BSON_TYPES, etype) {
+		return errors.New(formatErrorDescription(Locale.NotAValidType(), ErrorDetails{"given": "/" + etype + "/", "expected": BSON_TYPES}))
+	}
+
+	if t.Contains(etype) {
+		return errors.New(formatErrorDescription(Locale.Duplicated(), ErrorDetails{"type": etype}))
+	}
+
+	t.types = append(t.types, etype)
+
+	return nil
+}
+
+func (t *bsonSchemaType) Contains(etype string) bool {
+	return isStringInSlice(t.types, etype)
+}
+
+// Matches checks a BSON type name against the allowed types, resolving the number alias
+func (t *bsonSchemaType) Matches(etype string) bool {
+	if t.Contains(etype) {
+		return true
+	}
+	if t.Contains(BSON_TYPE_NUMBER) {
+		switch etype {
+		case BSON_TYPE_INT, BSON_TYPE_LONG, BSON_TYPE_DOUBLE, BSON_TYPE_DECIMAL:
+			return true
+		}
+	}
+	return false
+}
+
+func (t *bsonSchemaType) String() string {
+
+	if len(t.types) == 0 {
+		return STRING_UNDEFINED // should never happen
+	}
+
+	if len(t.types) > 1 {
+		return fmt.Sprintf("[%s]", strings.Join(t.types, ","))
+	}
+
+	return t.types[0]
+}
@@ -0,0 +1,345 @@
+package gojsonschema
+
+import (
+	"errors"
+	"net/url"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/xeipuuv/gojsonreference"
+)
+
+// Schema bundling
+// Bundle makes a single document of a schema and the resources it references. Every document
+// reached through a non-local $ref is copied into the definitions of the root, and every $ref is
+// rewritten as a JSON pointer into the bundle. The copied resources lose their $id so that
+// nothing in the bundle is resolved against their original URL, cycles between documents
+// becoming internal references.
+
+// Keywords whose value is a schema, an array of schemas or an object of schemas
+var (
+	schemaKeywords = []string{
+		KEY_ADDITIONAL_ITEMS, KEY_ADDITIONAL_PROPERTIES, KEY_PROPERTY_NAMES, KEY_CONTAINS,
+		KEY_NOT, KEY_IF, KEY_THEN, KEY_ELSE,
+	}
+	schemaArrayKeywords = []string{KEY_ALL_OF, KEY_ANY_OF, KEY_ONE_OF}
+	schemaMapKeywords   = []string{KEY_PROPERTIES, KEY_PATTERN_PROPERTIES, KEY_DEFINITIONS, KEY_DEFS, KEY_DEPENDENCIES}
+)
+
+// KEY_DEFS is the draft 2019-09 name of definitions, used by the bundle when the root has it
+const KEY_DEFS = "$defs"
+
+// Bundle returns a schema and all the schemas it references as one document
+func Bundle(l JSONLoader) (interface{}, error) {
+	return NewSchemaLoader().Bundle(l)
+}
+
+// Bundle returns a schema and all the schemas it references as one document,
+// the schemas registered with AddSchema being bundled like any other
+func (sl *SchemaLoader) Bundle(rootSchema JSONLoader) (interface{}, error) {
+
+	ref, err := rootSchema.JsonReference()
+	if err != nil {
+		return nil, err
+	}
+
+	pool := sl.newSchemaPool(rootSchema.LoaderFactory())
+	doc, err := loadRootDocument(pool, ref, rootSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	root, ok := doc.(map[string]interface{})
+	if !ok {
+		// boolean schemas do not reference anything
+		return doc, nil
+	}
+
+	base, err := url.Parse(ref.String())
+	if err != nil {
+		return nil, err
+	}
+	base.Fragment = ""
+
+	b := &bundler{
+		pool:        pool,
+		root:        root,
+		definitions: KEY_DEFINITIONS,
+		locations:   map[string]string{},
+	}
+	if _, ok := root[KEY_DEFINITIONS]; !ok && existsMapKey(root, KEY_DEFS) {
+		b.definitions = KEY_DEFS
+	}
+	if existing, ok := root[b.definitions].(map[string]interface{}); ok {
+		b.names = existing
+	}
+
+	// the root is known by its location as well as by its $id
+	b.locations[base.String()] = ""
+	b.index(root, base, "")
+	if err := b.rewrite(root, base, true); err != nil {
+		return nil, err
+	}
+
+	// resources copied while rewriting are rewritten in turn
+	for len(b.pending) > 0 {
+		resource := b.pending[0]
+		b.pending = b.pending[1:]
+		if err := b.rewrite(resource.node, resource.base, false); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(b.added) > 0 {
+		if b.names == nil {
+			b.names = map[string]interface{}{}
+			root[b.definitions] = b.names
+		}
+		for name, resource := range b.added {
+			b.names[name] = resource
+		}
+	}
+
+	return root, nil
+}
+
+type bundledResource struct {
+	node interface{}
+	base *url.URL
+}
+
+type bundler struct {
+	pool *schemaPool
+	root map[string]interface{}
+	// definitions keyword of the root, its members and the documents copied into it
+	definitions string
+	names       map[string]interface{}
+	added       map[string]interface{}
+	// JSON pointers in the bundle of the resources, and of their plain name fragments, by absolute URL
+	locations map[string]string
+	// resources copied into the bundle and not rewritten yet
+	pending []bundledResource
+}
+
+// schemaID returns the $id, or the draft-04 id, of a schema
+func schemaID(m map[string]interface{}) (string, string, bool) {
+	for _, k := range []string{KEY_ID, KEY_ID_NEW} {
+		if id, ok := m[k].(string); ok {
+			return k, id, true
+		}
+	}
+	return "", "", false
+}
+
+// forEachSubschema calls f with the subschemas of a schema and the pointer tokens leading to them
+func forEachSubschema(m map[string]interface{}, f func(node interface{}, tokens ...string) error) error {
+
+	for _, k := range schemaKeywords {
+		if v, ok := m[k]; ok && isKind(v, reflect.Map, reflect.Bool) {
+			if err := f(v, k); err != nil {
+				return err
+			}
+		}
+	}
+
+	items := append([]string{KEY_ITEMS}, schemaArrayKeywords...)
+	for _, k := range items {
+		switch v := m[k].(type) {
+		case []interface{}:
+			for i, item := range v {
+				if err := f(item, k, strconv.Itoa(i)); err != nil {
+					return err
+				}
+			}
+		case map[string]interface{}, bool:
+			if k == KEY_ITEMS {
+				if err := f(v, k); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, k := range schemaMapKeywords {
+		if members, ok := m[k].(map[string]interface{}); ok {
+			for name, v := range members {
+				if isKind(v, reflect.Map, reflect.Bool) {
+					if err := f(v, k, name); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolveID returns the base URL of a schema
+func resolveID(m map[string]interface{}, base *url.URL) *url.URL {
+	if _, id, ok := schemaID(m); ok {
+		if idURL, err := url.Parse(id); err == nil {
+			return base.ResolveReference(idURL)
+		}
+	}
+	return base
+}
+
+// withoutFragment returns an URL as a string, without its fragment
+func withoutFragment(u *url.URL) string {
+	v := *u
+	v.Fragment = ""
+	return v.String()
+}
+
+// index records the location of the resources identified by an $id, pointer being the
+// location of node in the bundle
+func (b *bundler) index(node interface{}, base *url.URL, pointer string) {
+
+	m, ok := node.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	base = resolveID(m, base)
+	if base.Fragment != "" {
+		// plain name fragment
+		if _, exists := b.locations[base.String()]; !exists {
+			b.locations[base.String()] = pointer
+		}
+		base, _ = url.Parse(withoutFragment(base))
+	} else if _, exists := b.locations[base.String()]; !exists {
+		b.locations[base.String()] = pointer
+	}
+
+	forEachSubschema(m, func(child interface{}, tokens ...string) error {
+		childPointer := pointer
+		for _, token := range tokens {
+			childPointer += "/" + jsonPointerToken(token)
+		}
+		b.index(child, base, childPointer)
+		return nil
+	})
+}
+
+// rewrite replaces the references of a schema by pointers into the bundle
+func (b *bundler) rewrite(node interface{}, base *url.URL, isRoot bool) error {
+
+	m, ok := node.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	base = resolveID(m, base)
+	if !isRoot {
+		if k, _, ok := schemaID(m); ok {
+			delete(m, k)
+		}
+	}
+
+	if ref, ok := m[KEY_REF].(string); ok {
+		refURL, err := url.Parse(ref)
+		if err != nil {
+			return err
+		}
+		pointer, err := b.locate(base.ResolveReference(refURL))
+		if err != nil {
+			return err
+		}
+		m[KEY_REF] = "#" + pointer
+	}
+
+	return forEachSubschema(m, func(child interface{}, tokens ...string) error {
+		return b.rewrite(child, base, false)
+	})
+}
+
+// locate returns the JSON pointer in the bundle of a reference, copying its document into the
+// bundle the first time it is referenced
+func (b *bundler) locate(ref *url.URL) (string, error) {
+
+	document := withoutFragment(ref)
+	fragment := ref.Fragment
+	isPointer := fragment == "" || strings.HasPrefix(fragment, "/")
+
+	if isPointer {
+		if pointer, ok := b.locations[document]; ok {
+			return pointer + fragment, nil
+		}
+	} else if pointer, ok := b.locations[ref.String()]; ok {
+		return pointer, nil
+	}
+
+	if _, ok := b.locations[document]; ok {
+		// the document is bundled but does not have this plain name
+		return "", errors.New(formatErrorDescription(
+			Locale.BundleUnresolvedReference(),
+			ErrorDetails{"reference": ref.String()},
+		))
+	}
+
+	if _, err := b.copyDocument(document); err != nil {
+		return "", err
+	}
+	return b.locate(ref)
+}
+
+// copyDocument copies a referenced document into the definitions of the root
+func (b *bundler) copyDocument(document string) (string, error) {
+
+	reference, err := gojsonreference.NewJsonReference(document)
+	if err != nil {
+		return "", err
+	}
+	spd, err := b.pool.GetDocument(reference)
+	if err != nil {
+		return "", err
+	}
+
+	node := convertDocumentNode(spd.Document)
+	if m, ok := node.(map[string]interface{}); ok {
+		delete(m, KEY_SCHEMA)
+	}
+
+	base, err := url.Parse(document)
+	if err != nil {
+		return "", err
+	}
+
+	name := b.definitionName(base)
+	if b.added == nil {
+		b.added = map[string]interface{}{}
+	}
+	b.added[name] = node
+
+	pointer := "/" + jsonPointerToken(b.definitions) + "/" + jsonPointerToken(name)
+	b.locations[document] = pointer
+	b.index(node, base, pointer)
+	b.pending = append(b.pending, bundledResource{node: node, base: base})
+
+	return pointer, nil
+}
+
+// definitionName returns a definition name for a document, based on its file name
+func (b *bundler) definitionName(u *url.URL) string {
+
+	name := strings.TrimSuffix(path.Base(u.Path), path.Ext(u.Path))
+	if name == "" || name == "." || name == "/" {
+		name = u.Hostname()
+	}
+	if name == "" {
+		name = "schema"
+	}
+
+	unique := name
+	for i := 2; ; i++ {
+		_, defined := b.names[unique]
+		_, added := b.added[unique]
+		if !defined && !added {
+			return unique
+		}
+		unique = name + strconv.Itoa(i)
+	}
+}
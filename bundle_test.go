@@ -0,0 +1,174 @@
+package gojsonschema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// bundleRefs returns the $ref values of a bundled document
+func bundleRefs(node interface{}) []string {
+	var refs []string
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for k, v := range n {
+			if ref, ok := v.(string); ok && k == KEY_REF {
+				refs = append(refs, ref)
+			}
+			refs = append(refs, bundleRefs(v)...)
+		}
+	case []interface{}:
+		for _, v := range n {
+			refs = append(refs, bundleRefs(v)...)
+		}
+	}
+	return refs
+}
+
+func TestBundle(t *testing.T) {
+	sl := NewSchemaLoader()
+	assert.Nil(t, sl.AddSchema("", NewStringLoader(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"$id": "http://example.com/schemas/customer.json",
+		"type": "object",
+		"properties": {
+			"address": {"$ref": "#/definitions/address"},
+			"orders": {"type": "array", "items": {"$ref": "order.json"}}
+		},
+		"required": ["address"],
+		"definitions": {
+			"address": {"type": "string", "minLength": 3}
+		}
+	}`)))
+	assert.Nil(t, sl.AddSchema("http://example.com/schemas/product.json", NewStringLoader(`{
+		"definitions": {
+			"price": {"type": "number", "minimum": 0}
+		}
+	}`)))
+
+	root := `{
+		"$id": "http://example.com/schemas/order.json",
+		"type": "object",
+		"properties": {
+			"customer": {"$ref": "customer.json"},
+			"total": {"$ref": "product.json#/definitions/price"},
+			"note": {"$ref": "#/definitions/customer"}
+		},
+		"definitions": {
+			"customer": {"type": "string"}
+		}
+	}`
+
+	bundle, err := sl.Bundle(NewStringLoader(root))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	definitions := bundle.(map[string]interface{})[KEY_DEFINITIONS].(map[string]interface{})
+	assert.Len(t, definitions, 3)
+	assert.Contains(t, definitions, "customer2")
+	assert.Contains(t, definitions, "product")
+	customer := definitions["customer2"].(map[string]interface{})
+	assert.NotContains(t, customer, KEY_ID_NEW)
+	assert.NotContains(t, customer, KEY_SCHEMA)
+
+	refs := bundleRefs(bundle)
+	assert.ElementsMatch(t, []string{
+		"#/definitions/customer2",
+		"#/definitions/product/definitions/price",
+		"#/definitions/customer",
+		"#/definitions/customer2/definitions/address",
+		"#",
+	}, refs)
+
+	// the bundle is self-contained and validates like the original schema
+	original, err := sl.Compile(NewStringLoader(root))
+	if !assert.Nil(t, err) {
+		return
+	}
+	bundled, err := NewSchema(NewGoLoader(bundle))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	documents := []string{
+		`{"customer": {"address": "Main street", "orders": [{"total": 3}]}, "total": 12.5, "note": "-"}`,
+		`{"customer": {"address": "Main street", "orders": [{"total": -3}]}}`,
+		`{"customer": {"orders": []}}`,
+		`{"customer": {"address": "x"}, "note": 1}`,
+	}
+	for _, document := range documents {
+		expected, err := original.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		result, err := bundled.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		assert.Equal(t, expected.Valid(), result.Valid(), "document: %s", document)
+		assert.Equal(t, len(expected.Errors()), len(result.Errors()), "document: %s", document)
+	}
+
+	text, err := json.Marshal(bundle)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(text), "http://example.com/schemas/customer.json")
+}
+
+func TestBundleIDs(t *testing.T) {
+	sl := NewSchemaLoader()
+	assert.Nil(t, sl.AddSchema("", NewStringLoader(`{
+		"$id": "http://example.com/common.json",
+		"definitions": {
+			"positive": {"$id": "#positive", "type": "integer", "minimum": 1},
+			"name": {"$id": "http://example.com/name.json", "type": "string", "pattern": "^[A-Z]"}
+		}
+	}`)))
+
+	bundle, err := sl.Bundle(NewStringLoader(`{
+		"$defs": {
+			"local": {"$id": "#local", "maxLength": 5}
+		},
+		"allOf": [
+			{"properties": {"count": {"$ref": "http://example.com/common.json#positive"}}},
+			{"properties": {"name": {"allOf": [{"$ref": "http://example.com/name.json"}, {"$ref": "#local"}]}}}
+		]
+	}`))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.ElementsMatch(t, []string{
+		"#/$defs/common/definitions/positive",
+		"#/$defs/common/definitions/name",
+		"#/$defs/local",
+	}, bundleRefs(bundle))
+
+	schema, err := NewSchema(NewGoLoader(bundle))
+	if !assert.Nil(t, err) {
+		return
+	}
+	result, err := schema.Validate(NewStringLoader(`{"count": 2, "name": "Jo"}`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid(), "%v", result.Errors())
+	result, err = schema.Validate(NewStringLoader(`{"count": 0, "name": "jonathan"}`))
+	assert.Nil(t, err)
+	assert.Len(t, result.Errors(), 5)
+}
+
+func TestBundleErrors(t *testing.T) {
+	sl := NewSchemaLoader()
+	assert.Nil(t, sl.AddSchema("http://example.com/a.json", NewStringLoader(`{"type": "string"}`)))
+
+	_, err := sl.Bundle(NewStringLoader(`{"$ref": "http://example.com/a.json#missing"}`))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Reference http://example.com/a.json#missing cannot be resolved", err.Error())
+	}
+
+	_, err = sl.Bundle(NewStringLoader(`{"$ref": "relative.json"}`))
+	if assert.NotNil(t, err) {
+		assert.True(t, strings.Contains(err.Error(), "canonical"), err.Error())
+	}
+
+	bundle, err := Bundle(NewStringLoader(`true`))
+	assert.Nil(t, err)
+	assert.Equal(t, true, bundle)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func runBundle(args []string, stdout io.Writer, stderr io.Writer) int {
+
+	flags := flag.NewFlagSet("bundle", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var refs stringList
+	flags.Var(&refs, "ref", "schema referenced by the others, registered under its $id or location (repeatable)")
+	output := flags.String("o", "", "output file, defaults to the standard output")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: gojsonschema bundle [-ref schema]... [-o file] schema")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	sl := gojsonschema.NewSchemaLoader()
+	for _, ref := range refs {
+		if err := sl.AddSchema("", fileLoader(ref)); err != nil {
+			fmt.Fprintf(stderr, "%s: %s\n", ref, err)
+			return exitError
+		}
+	}
+
+	bundle, err := sl.Bundle(fileLoader(flags.Arg(0)))
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %s\n", flags.Arg(0), err)
+		return exitError
+	}
+
+	source, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %s\n", flags.Arg(0), err)
+		return exitError
+	}
+	source = append(source, '\n')
+
+	if *output == "" {
+		stdout.Write(source)
+		return exitOK
+	}
+	if err := ioutil.WriteFile(*output, source, 0644); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	return exitOK
+}
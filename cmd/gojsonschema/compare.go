@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func runCompare(args []string, stdout io.Writer, stderr io.Writer) int {
+
+	flags := flag.NewFlagSet("compare", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var refs stringList
+	flags.Var(&refs, "ref", "schema referenced by the others, registered under its $id or location (repeatable)")
+	forward := flags.Bool("forward", false, "also fail when documents valid under the new schema may be invalid under the old one")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: gojsonschema compare [-ref schema]... [-forward] old new")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	sl := gojsonschema.NewSchemaLoader()
+	for _, ref := range refs {
+		if err := sl.AddSchema("", fileLoader(ref)); err != nil {
+			fmt.Fprintf(stderr, "%s: %s\n", ref, err)
+			return exitError
+		}
+	}
+
+	var schemas [2]*gojsonschema.Schema
+	for i, path := range flags.Args() {
+		schema, err := sl.Compile(fileLoader(path))
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %s\n", path, err)
+			return exitError
+		}
+		schemas[i] = schema
+	}
+
+	code := exitOK
+	for _, change := range gojsonschema.Compare(schemas[0], schemas[1]) {
+		compatibility := "compatible"
+		switch {
+		case !change.Backward && !change.Forward:
+			compatibility = "breaking, not forward compatible"
+		case !change.Backward:
+			compatibility = "breaking"
+		case !change.Forward:
+			compatibility = "not forward compatible"
+		}
+		fmt.Fprintf(stdout, "%s (%s)\n", change, compatibility)
+
+		if !change.Backward || *forward && !change.Forward {
+			code = exitInvalid
+		}
+	}
+
+	return code
+}
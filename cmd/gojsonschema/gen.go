@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func runGen(args []string, stdout io.Writer, stderr io.Writer) int {
+
+	flags := flag.NewFlagSet("gen", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	packageName := flags.String("package", "schema", "package of the generated file")
+	rootType := flags.String("type", "", "name of the root type, defaults to the title of the schema")
+	output := flags.String("o", "", "output file, defaults to the standard output")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: gojsonschema gen [-package name] [-type name] [-o file] schema")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	schema, err := gojsonschema.NewSchema(fileLoader(flags.Arg(0)))
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %s\n", flags.Arg(0), err)
+		return exitError
+	}
+
+	source, err := gojsonschema.GenerateGo(schema, gojsonschema.GoGeneratorOptions{
+		PackageName: *packageName,
+		RootType:    *rootType,
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %s\n", flags.Arg(0), err)
+		return exitError
+	}
+
+	if *output == "" {
+		stdout.Write(source)
+		return exitOK
+	}
+	if err := ioutil.WriteFile(*output, source, 0644); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	return exitOK
+}
@@ -0,0 +1,93 @@
+// Command gojsonschema works with JSON schemas from the command line.
+//
+// Usage:
+//
+//	gojsonschema validate [-ref schema]... [-draft version] [-format text|json|junit] schema [document|glob|-]...
+//	gojsonschema gen [-package name] [-type name] [-o file] schema
+//	gojsonschema bundle [-ref schema]... [-o file] schema
+//	gojsonschema compare [-ref schema]... [-forward] old new
+//	gojsonschema sample [-ref schema]... [-seed n] [-depth n] [-invalid] schema
+//
+// validate checks documents against a schema, the standard input when no document is given.
+// It exits with 0 when all the documents are valid and 1 when some are invalid.
+//
+// gen prints Go types mirroring the schema, see GenerateGo.
+//
+// bundle prints the schema and the schemas it references as one document, see Bundle.
+//
+// compare prints the changes between two versions of a schema, see Compare. It exits with 1
+// when a change is breaking, or with -forward not forward compatible.
+//
+// sample prints a random document valid against the schema, or with -invalid the documents
+// violating each of its keywords, see GenerateSample and GenerateInvalidSamples.
+//
+// All exit with 2 on usage errors and 3 when the schema, a reference or a document cannot be loaded.
+//
+// Schemas and documents are file paths, JSON or YAML, or http(s) URLs.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Exit codes
+const (
+	exitOK      = 0
+	exitInvalid = 1
+	exitUsage   = 2
+	// the schema, a reference or a document cannot be loaded
+	exitError = 3
+)
+
+const usage = `usage: gojsonschema <command> [arguments]
+
+commands:
+  validate    validate documents against a schema
+  gen         generate Go types from a schema
+  bundle      bundle a schema and its references into one document
+  compare     compare two versions of a schema
+  sample      generate sample documents of a schema
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes a command and returns the exit code
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "validate":
+		return runValidate(args[1:], stdin, stdout, stderr)
+	case "gen":
+		return runGen(args[1:], stdout, stderr)
+	case "bundle":
+		return runBundle(args[1:], stdout, stderr)
+	case "compare":
+		return runCompare(args[1:], stdout, stderr)
+	case "sample":
+		return runSample(args[1:], stdout, stderr)
+	}
+
+	fmt.Fprintf(stderr, "unknown command %q\n\n%s", args[0], usage)
+	return exitUsage
+}
+
+// fileLoader loads a schema or a document from a URL or a file, so the relative $ref's of schemas resolve.
+// Files are decoded as YAML or JSON depending on their extension.
+func fileLoader(path string) gojsonschema.JSONLoader {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return gojsonschema.NewReferenceLoader(path)
+	}
+	return gojsonschema.NewYAMLFileLoader(path)
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func runCommand(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestUsage(t *testing.T) {
+	code, _, stderr := runCommand("")
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, "usage: gojsonschema")
+
+	code, _, stderr = runCommand("", "unknown")
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, `unknown command "unknown"`)
+
+	for _, args := range [][]string{
+		{"validate"},
+		{"validate", "-draft", "5", "testdata/person.json"},
+		{"validate", "-format", "xml", "testdata/person.json"},
+		{"validate", "-unknown", "testdata/person.json"},
+	} {
+		code, _, _ = runCommand("", args...)
+		assert.Equal(t, exitUsage, code, "%v", args)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	code, stdout, stderr := runCommand("", "validate", "testdata/person.json", "testdata/people/jane.json", "testdata/people/jane.yaml")
+	assert.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "testdata/people/jane.json: valid\ntestdata/people/jane.yaml: valid\n", stdout)
+
+	code, stdout, _ = runCommand("", "validate", "testdata/person.json", "testdata/people/*.json")
+	assert.Equal(t, exitInvalid, code)
+	assert.Equal(t, `testdata/people/jane.json: valid
+testdata/people/john.json: invalid
+  - age: Must be greater than or equal to 0
+  - name: String length must be greater than or equal to 1
+  - status: status must be one of the following: "active", "inactive"
+`, stdout)
+
+	// the standard input
+	code, stdout, _ = runCommand(`{"id": "7d3c6a4e-5e0a-4b6f-9a57-2f1c9c1a8f10", "name": "Jo"}`, "validate", "testdata/person.json")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "-: valid\n", stdout)
+	code, stdout, _ = runCommand(`{"id": 1}`, "validate", "testdata/person.json", "-")
+	assert.Equal(t, exitInvalid, code)
+	assert.Contains(t, stdout, "-: invalid\n")
+
+	// documents that cannot be loaded
+	code, stdout, _ = runCommand("", "validate", "testdata/person.json", "testdata/broken.json")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stdout, "testdata/broken.json: error: ")
+	code, _, stderr = runCommand("", "validate", "testdata/person.json", "testdata/none/*.json")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "no matching document")
+	code, _, stderr = runCommand("", "validate", "testdata/missing.json", "testdata/people/jane.json")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "testdata/missing.json")
+}
+
+func TestValidateRef(t *testing.T) {
+	// the person schema is only known by its $id
+	code, _, _ := runCommand("", "validate", "testdata/team.json", "testdata/team-valid.json")
+	assert.Equal(t, exitError, code)
+
+	code, stdout, stderr := runCommand("", "validate", "-ref", "testdata/person.json", "testdata/team.json", "testdata/team-valid.json")
+	assert.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "testdata/team-valid.json: valid\n", stdout)
+
+	code, stdout, _ = runCommand(`{"name": "core", "members": [{"id": "7d3c6a4e-5e0a-4b6f-9a57-2f1c9c1a8f10"}]}`, "validate", "--ref", "testdata/person.json", "testdata/team.json")
+	assert.Equal(t, exitInvalid, code)
+	assert.Contains(t, stdout, "  - name: name is required\n")
+
+	code, _, _ = runCommand("", "validate", "-ref", "testdata/missing.json", "testdata/team.json", "testdata/team-valid.json")
+	assert.Equal(t, exitError, code)
+}
+
+func TestValidateFormats(t *testing.T) {
+	code, stdout, _ := runCommand("", "validate", "-format", "json", "testdata/person.json", "testdata/people/jane.json", "testdata/people/john.json", "testdata/broken.json")
+	assert.Equal(t, exitError, code)
+
+	var report struct {
+		Schema    string `json:"schema"`
+		Documents []struct {
+			Document string `json:"document"`
+			Valid    bool   `json:"valid"`
+			Errors   []struct {
+				Field   string                 `json:"field"`
+				Context string                 `json:"context"`
+				Type    string                 `json:"type"`
+				Details map[string]interface{} `json:"details"`
+			} `json:"errors"`
+			Error string `json:"error"`
+		} `json:"documents"`
+	}
+	if !assert.Nil(t, json.Unmarshal([]byte(stdout), &report)) {
+		return
+	}
+	assert.Equal(t, "testdata/person.json", report.Schema)
+	if assert.Len(t, report.Documents, 3) {
+		assert.True(t, report.Documents[0].Valid)
+		assert.False(t, report.Documents[1].Valid)
+		assert.Len(t, report.Documents[1].Errors, 3)
+		assert.Equal(t, "name", report.Documents[1].Errors[1].Field)
+		assert.Equal(t, "(root).name", report.Documents[1].Errors[1].Context)
+		assert.Equal(t, "string_gte", report.Documents[1].Errors[1].Type)
+		assert.Equal(t, float64(1), report.Documents[1].Errors[1].Details["min"])
+		assert.NotEmpty(t, report.Documents[2].Error)
+	}
+
+	code, stdout, _ = runCommand("", "validate", "-format", "junit", "testdata/person.json", "testdata/people/*")
+	assert.Equal(t, exitInvalid, code)
+
+	var suite struct {
+		Name      string `xml:"name,attr"`
+		Tests     int    `xml:"tests,attr"`
+		Failures  int    `xml:"failures,attr"`
+		Errors    int    `xml:"errors,attr"`
+		TestCases []struct {
+			Name    string `xml:"name,attr"`
+			Failure *struct {
+				Message string `xml:"message,attr"`
+				Text    string `xml:",chardata"`
+			} `xml:"failure"`
+		} `xml:"testcase"`
+	}
+	if !assert.Nil(t, xml.Unmarshal([]byte(stdout), &suite)) {
+		return
+	}
+	assert.Equal(t, "testdata/person.json", suite.Name)
+	assert.Equal(t, 3, suite.Tests)
+	assert.Equal(t, 1, suite.Failures)
+	assert.Equal(t, 0, suite.Errors)
+	if assert.Len(t, suite.TestCases, 3) {
+		assert.Nil(t, suite.TestCases[0].Failure)
+		assert.Equal(t, "testdata/people/john.json", suite.TestCases[2].Name)
+		assert.Equal(t, "3 validation errors", suite.TestCases[2].Failure.Message)
+		assert.Contains(t, suite.TestCases[2].Failure.Text, "age: Must be greater than or equal to 0")
+	}
+}
+
+// TestValidateSuite runs draft-07 files of the test suite through the command
+func TestValidateSuite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gojsonschema")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"type.json", "required.json", "properties.json", "if-then-else.json", "const.json"} {
+		source, err := ioutil.ReadFile(filepath.Join("..", "..", "testdata", "draft7", name))
+		if !assert.Nil(t, err) {
+			return
+		}
+		var tests []struct {
+			Description string          `json:"description"`
+			Schema      json.RawMessage `json:"schema"`
+			Tests       []struct {
+				Description string          `json:"description"`
+				Data        json.RawMessage `json:"data"`
+				Valid       bool            `json:"valid"`
+			} `json:"tests"`
+		}
+		if !assert.Nil(t, json.Unmarshal(source, &tests)) {
+			return
+		}
+
+		for _, test := range tests {
+			schema := filepath.Join(dir, "schema.json")
+			assert.Nil(t, ioutil.WriteFile(schema, test.Schema, 0644))
+			for _, testCase := range test.Tests {
+				expected := exitOK
+				if !testCase.Valid {
+					expected = exitInvalid
+				}
+				code, stdout, stderr := runCommand(string(testCase.Data), "validate", "-draft", "7", schema)
+				assert.Equal(t, expected, code, "%s: %s, %s\n%s%s", name, test.Description, testCase.Description, stdout, stderr)
+			}
+		}
+	}
+}
+
+func TestGen(t *testing.T) {
+	code, stdout, stderr := runCommand("", "gen", "-package", "people", "testdata/person.json")
+	assert.Equal(t, exitOK, code, stderr)
+	assert.Contains(t, stdout, "package people\n")
+	assert.Contains(t, stdout, "type Person struct {")
+	assert.Contains(t, stdout, "Friends []Person")
+	assert.Contains(t, stdout, "CountryFR Country = \"FR\"")
+
+	dir, err := ioutil.TempDir("", "gojsonschema")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	output := filepath.Join(dir, "person.go")
+	code, _, stderr = runCommand("", "gen", "-type", "Contact", "-o", output, "testdata/person.json")
+	assert.Equal(t, exitOK, code, stderr)
+	source, err := ioutil.ReadFile(output)
+	assert.Nil(t, err)
+	assert.Contains(t, string(source), "type Contact struct {")
+
+	code, _, stderr = runCommand("", "gen", "testdata/missing.json")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "testdata/missing.json")
+
+	code, _, _ = runCommand("", "gen")
+	assert.Equal(t, exitUsage, code)
+}
+
+func TestBundle(t *testing.T) {
+	code, stdout, stderr := runCommand("", "bundle", "-ref", "testdata/person.json", "testdata/team.json")
+	assert.Equal(t, exitOK, code, stderr)
+
+	var bundle map[string]interface{}
+	if !assert.Nil(t, json.Unmarshal([]byte(stdout), &bundle)) {
+		return
+	}
+	assert.Contains(t, bundle["definitions"], "person")
+	assert.Contains(t, stdout, `"$ref": "#/definitions/person"`)
+
+	// the bundle validates without the referenced schema
+	dir, err := ioutil.TempDir("", "gojsonschema")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	output := filepath.Join(dir, "team.json")
+	code, _, stderr = runCommand("", "bundle", "-ref", "testdata/person.json", "-o", output, "testdata/team.json")
+	assert.Equal(t, exitOK, code, stderr)
+	code, stdout, stderr = runCommand("", "validate", output, "testdata/team-valid.json")
+	assert.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "testdata/team-valid.json: valid\n", stdout)
+
+	code, _, _ = runCommand("", "bundle", "testdata/team.json")
+	assert.Equal(t, exitError, code)
+	code, _, _ = runCommand("", "bundle")
+	assert.Equal(t, exitUsage, code)
+}
+
+func TestCompare(t *testing.T) {
+	code, stdout, stderr := runCommand("", "compare", "testdata/person.json", "testdata/person-v2.json")
+	assert.Equal(t, exitInvalid, code, stderr)
+	assert.Equal(t, `#: status is now required (breaking)
+#/definitions/address: street is no longer required (not forward compatible)
+#/properties/name: maxLength changed from none to 100 (breaking)
+#/properties/status: enum changed, added: "suspended", removed: - (not forward compatible)
+`, stdout)
+
+	code, stdout, _ = runCommand("", "compare", "testdata/person.json", "testdata/person.json")
+	assert.Equal(t, exitOK, code)
+	assert.Empty(t, stdout)
+
+	code, _, _ = runCommand("", "compare", "testdata/person.json", "testdata/missing.json")
+	assert.Equal(t, exitError, code)
+	code, _, _ = runCommand("", "compare", "testdata/person.json")
+	assert.Equal(t, exitUsage, code)
+}
+
+func TestSample(t *testing.T) {
+	code, stdout, stderr := runCommand("", "sample", "-seed", "7", "testdata/person.json")
+	assert.Equal(t, exitOK, code, stderr)
+
+	// the sample is valid
+	dir, err := ioutil.TempDir("", "gojsonschema")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+	sample := filepath.Join(dir, "sample.json")
+	if !assert.Nil(t, ioutil.WriteFile(sample, []byte(stdout), 0644)) {
+		return
+	}
+	code, _, stderr = runCommand("", "validate", "testdata/person.json", sample)
+	assert.Equal(t, exitOK, code, stderr)
+
+	code, stdout, stderr = runCommand("", "sample", "-invalid", "testdata/person.json")
+	assert.Equal(t, exitOK, code, stderr)
+	var samples []map[string]interface{}
+	if !assert.Nil(t, json.Unmarshal([]byte(stdout), &samples)) {
+		return
+	}
+	assert.Contains(t, samples, map[string]interface{}{
+		"pointer":  "#",
+		"keyword":  "type",
+		"field":    "(root)",
+		"type":     "invalid_type",
+		"document": nil,
+	})
+
+	code, _, _ = runCommand("", "sample", "testdata/missing.json")
+	assert.Equal(t, exitError, code)
+	code, _, _ = runCommand("", "sample")
+	assert.Equal(t, exitUsage, code)
+}
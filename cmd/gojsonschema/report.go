@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Reports of validate, built from Result.Errors()
+
+// sortedErrors returns the errors of a result by position in the document, so the reports are stable
+func sortedErrors(result *gojsonschema.Result) []gojsonschema.ResultError {
+	errors := append([]gojsonschema.ResultError{}, result.Errors()...)
+	sort.SliceStable(errors, func(i, j int) bool {
+		return errors[i].Context().String() < errors[j].Context().String()
+	})
+	return errors
+}
+
+func reportText(w io.Writer, schema string, results []documentResult) error {
+
+	for _, r := range results {
+		switch {
+		case r.err != nil:
+			fmt.Fprintf(w, "%s: error: %s\n", r.name, r.err)
+		case r.result.Valid():
+			fmt.Fprintf(w, "%s: valid\n", r.name)
+		default:
+			fmt.Fprintf(w, "%s: invalid\n", r.name)
+			for _, e := range sortedErrors(r.result) {
+				fmt.Fprintf(w, "  - %s\n", e)
+			}
+		}
+	}
+
+	return nil
+}
+
+type jsonReportError struct {
+	Field       string                 `json:"field"`
+	Context     string                 `json:"context"`
+	Type        string                 `json:"type"`
+	Description string                 `json:"description"`
+	Details     map[string]interface{} `json:"details,omitempty"`
+}
+
+type jsonReportDocument struct {
+	Document string            `json:"document"`
+	Valid    bool              `json:"valid"`
+	Errors   []jsonReportError `json:"errors,omitempty"`
+	Error    string            `json:"error,omitempty"`
+}
+
+func reportJSON(w io.Writer, schema string, results []documentResult) error {
+
+	report := struct {
+		Schema    string               `json:"schema"`
+		Documents []jsonReportDocument `json:"documents"`
+	}{Schema: schema, Documents: []jsonReportDocument{}}
+
+	for _, r := range results {
+		document := jsonReportDocument{Document: r.name}
+		if r.err != nil {
+			document.Error = r.err.Error()
+		} else {
+			document.Valid = r.result.Valid()
+			for _, e := range sortedErrors(r.result) {
+				document.Errors = append(document.Errors, jsonReportError{
+					Field:       e.Field(),
+					Context:     e.Context().String(),
+					Type:        e.Type(),
+					Description: e.Description(),
+					Details:     e.Details(),
+				})
+			}
+		}
+		report.Documents = append(report.Documents, document)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+}
+
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+// reportJUnit reports each document as a test case of a suite named after the schema
+func reportJUnit(w io.Writer, schema string, results []documentResult) error {
+
+	suite := junitTestSuite{Name: schema, Tests: len(results)}
+
+	for _, r := range results {
+		testCase := junitTestCase{Name: r.name, Classname: schema}
+		switch {
+		case r.err != nil:
+			suite.Errors++
+			testCase.Error = &junitFailure{Message: r.err.Error(), Type: "error"}
+		case !r.result.Valid():
+			suite.Failures++
+			var lines []string
+			for _, e := range sortedErrors(r.result) {
+				lines = append(lines, e.String())
+			}
+			testCase.Failure = &junitFailure{
+				Message: fmt.Sprintf("%d validation errors", len(lines)),
+				Type:    "invalid",
+				Text:    strings.Join(lines, "\n"),
+			}
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	io.WriteString(w, xml.Header)
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suite); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// invalidSample is the JSON form of an invalid sample
+type invalidSample struct {
+	Pointer  string      `json:"pointer"`
+	Keyword  string      `json:"keyword"`
+	Field    string      `json:"field"`
+	Type     string      `json:"type"`
+	Document interface{} `json:"document"`
+}
+
+func runSample(args []string, stdout io.Writer, stderr io.Writer) int {
+
+	flags := flag.NewFlagSet("sample", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var refs stringList
+	flags.Var(&refs, "ref", "schema referenced by the others, registered under its $id or location (repeatable)")
+	seed := flags.Int64("seed", 0, "seed of the random choices")
+	depth := flags.Int("depth", 0, "nesting depth of the optional properties and items (default 5)")
+	invalid := flags.Bool("invalid", false, "print a document violating each keyword instead of a valid one")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: gojsonschema sample [-ref schema]... [-seed n] [-depth n] [-invalid] schema")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	sl := gojsonschema.NewSchemaLoader()
+	for _, ref := range refs {
+		if err := sl.AddSchema("", fileLoader(ref)); err != nil {
+			fmt.Fprintf(stderr, "%s: %s\n", ref, err)
+			return exitError
+		}
+	}
+
+	schema, err := sl.Compile(fileLoader(flags.Arg(0)))
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %s\n", flags.Arg(0), err)
+		return exitError
+	}
+
+	options := gojsonschema.SampleOptions{Seed: *seed, MaxDepth: *depth}
+	var output interface{}
+	if *invalid {
+		samples, err := gojsonschema.GenerateInvalidSamples(schema, options)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %s\n", flags.Arg(0), err)
+			return exitError
+		}
+		invalidSamples := []invalidSample{}
+		for _, sample := range samples {
+			invalidSamples = append(invalidSamples, invalidSample{sample.Pointer, sample.Keyword, sample.Field, sample.Type, sample.Document})
+		}
+		output = invalidSamples
+	} else {
+		output, err = gojsonschema.GenerateSample(schema, options)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %s\n", flags.Arg(0), err)
+			return exitError
+		}
+	}
+
+	source, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %s\n", flags.Arg(0), err)
+		return exitError
+	}
+	stdout.Write(append(source, '\n'))
+	return exitOK
+}
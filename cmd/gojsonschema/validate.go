@@ -0,0 +1,162 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// stringList is a repeatable flag
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// documentResult is the validation of a document
+type documentResult struct {
+	name   string
+	result *gojsonschema.Result
+	// the document could not be loaded
+	err error
+}
+
+func runValidate(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var refs stringList
+	flags.Var(&refs, "ref", "schema referenced by the others, registered under its $id or location (repeatable)")
+	draft := flags.String("draft", "hybrid", "draft of the schemas: 4, 6, 7 or hybrid")
+	format := flags.String("format", "text", "output format: text, json or junit")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: gojsonschema validate [-ref schema]... [-draft version] [-format text|json|junit] schema [document|glob|-]...")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() < 1 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	sl := gojsonschema.NewSchemaLoader()
+	switch *draft {
+	case "4", "draft-04":
+		sl.Draft = gojsonschema.Draft4
+	case "6", "draft-06":
+		sl.Draft = gojsonschema.Draft6
+	case "7", "draft-07":
+		sl.Draft = gojsonschema.Draft7
+	case "hybrid":
+		sl.Draft = gojsonschema.Hybrid
+	default:
+		fmt.Fprintf(stderr, "unknown draft %q\n", *draft)
+		return exitUsage
+	}
+
+	var report func(io.Writer, string, []documentResult) error
+	switch *format {
+	case "text":
+		report = reportText
+	case "json":
+		report = reportJSON
+	case "junit":
+		report = reportJUnit
+	default:
+		fmt.Fprintf(stderr, "unknown format %q\n", *format)
+		return exitUsage
+	}
+
+	for _, ref := range refs {
+		if err := sl.AddSchema("", fileLoader(ref)); err != nil {
+			fmt.Fprintf(stderr, "%s: %s\n", ref, err)
+			return exitError
+		}
+	}
+
+	schemaPath := flags.Arg(0)
+	schema, err := sl.Compile(fileLoader(schemaPath))
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %s\n", schemaPath, err)
+		return exitError
+	}
+
+	documents, err := expandDocuments(flags.Args()[1:])
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	code := exitOK
+	var results []documentResult
+
+	for _, document := range documents {
+		var loader gojsonschema.JSONLoader
+		if document == "-" {
+			source, err := ioutil.ReadAll(stdin)
+			if err != nil {
+				fmt.Fprintln(stderr, err)
+				return exitError
+			}
+			loader = gojsonschema.NewBytesLoader(source)
+		} else {
+			loader = fileLoader(document)
+		}
+
+		result, err := schema.Validate(loader)
+		results = append(results, documentResult{name: document, result: result, err: err})
+
+		switch {
+		case err != nil:
+			code = exitError
+		case !result.Valid() && code == exitOK:
+			code = exitInvalid
+		}
+	}
+
+	if err := report(stdout, schemaPath, results); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	return code
+}
+
+// expandDocuments expands the globs of the documents, no document standing for the standard input
+func expandDocuments(args []string) ([]string, error) {
+
+	if len(args) == 0 {
+		return []string{"-"}, nil
+	}
+
+	var documents []string
+	for _, arg := range args {
+		if arg == "-" || !strings.ContainsAny(arg, "*?[") {
+			documents = append(documents, arg)
+			continue
+		}
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", arg, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("%s: no matching document", arg)
+		}
+		documents = append(documents, matches...)
+	}
+
+	return documents, nil
+}
@@ -0,0 +1,259 @@
+package gojsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Type coercion
+// The values of forms, query strings, CSV files and environment variables are strings. With the
+// Coerce option of ValidateWithOptions, the values whose type the schema does not allow are
+// converted before the validation, following the types of the subschemas the value is validated
+// against : $ref, allOf, the first branch of anyOf and oneOf the coerced value validates, then or
+// else, the properties, items and their additional schemas.
+
+// Coercion is the conversion of a value of the document to the type of its schema : "42" to an
+// integer and "4.2" to a number, as json.Number, "true" and "false" to a boolean, "" to null, and a
+// value that is not an array to an array of this value
+type Coercion struct {
+	// JSON pointer to the value in the document
+	Pointer string
+	From    interface{}
+	To      interface{}
+}
+
+var coercionNumberRegex = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// coerceValue returns the value coerced to the types of a subschema and its children, the value
+// being copied when coerced
+func coerceValue(s *subSchema, value interface{}, pointer string) (interface{}, []Coercion) {
+
+	if s.refSchema != nil {
+		return coerceValue(s.refSchema, value, pointer)
+	}
+
+	var coercions []Coercion
+	coerce := func(child *subSchema, value interface{}, pointer string) interface{} {
+		coerced, childCoercions := coerceValue(child, value, pointer)
+		coercions = append(coercions, childCoercions...)
+		return coerced
+	}
+
+	if coerced, ok := coerceScalar(s.types, value); ok {
+		coercions = append(coercions, Coercion{Pointer: pointer, From: value, To: coerced})
+		value = coerced
+	}
+
+	for _, allOf := range s.allOf {
+		value = coerce(allOf, value, pointer)
+	}
+	for _, branches := range [][]*subSchema{s.anyOf, s.oneOf} {
+		for _, branch := range branches {
+			coerced, branchCoercions := coerceValue(branch, value, pointer)
+			if branch.subValidateWithContext(coerced, NewJsonContext(STRING_CONTEXT_ROOT, nil)).Valid() {
+				coercions = append(coercions, branchCoercions...)
+				value = coerced
+				break
+			}
+		}
+	}
+	if s._if != nil {
+		if s._if.subValidateWithContext(value, NewJsonContext(STRING_CONTEXT_ROOT, nil)).Valid() {
+			if s._then != nil {
+				value = coerce(s._then, value, pointer)
+			}
+		} else if s._else != nil {
+			value = coerce(s._else, value, pointer)
+		}
+	}
+
+	switch node := value.(type) {
+
+	case map[string]interface{}:
+		var copied map[string]interface{}
+		for _, key := range sortedMapKeys(node) {
+			before := len(coercions)
+			child := node[key]
+			for _, childSchema := range propertySchemas(s, key) {
+				child = coerce(childSchema, child, pointer+"/"+jsonPointerToken(key))
+			}
+			if len(coercions) > before {
+				if copied == nil {
+					copied = map[string]interface{}{}
+					for k, v := range node {
+						copied[k] = v
+					}
+				}
+				copied[key] = child
+			}
+		}
+		if copied != nil {
+			return copied, coercions
+		}
+
+	case []interface{}:
+		var copied []interface{}
+		for i, item := range node {
+			var itemSchema *subSchema
+			if s.itemsChildrenIsSingleSchema {
+				itemSchema = s.itemsChildren[0]
+			} else if i < len(s.itemsChildren) {
+				itemSchema = s.itemsChildren[i]
+			} else if additionalItems, ok := s.additionalItems.(*subSchema); ok {
+				itemSchema = additionalItems
+			}
+			if itemSchema == nil {
+				continue
+			}
+			before := len(coercions)
+			coerced := coerce(itemSchema, item, pointer+"/"+strconv.Itoa(i))
+			if len(coercions) > before {
+				if copied == nil {
+					copied = append([]interface{}{}, node...)
+				}
+				copied[i] = coerced
+			}
+		}
+		if copied != nil {
+			return copied, coercions
+		}
+	}
+
+	return value, coercions
+}
+
+// propertySchemas returns the subschemas validating a property of an object
+func propertySchemas(s *subSchema, key string) []*subSchema {
+
+	var schemas []*subSchema
+	for _, property := range s.propertiesChildren {
+		if property.property == key {
+			schemas = append(schemas, property)
+		}
+	}
+	for _, pattern := range sortedMapKeys(s.patternProperties) {
+		if s.patternMatchers[pattern].MatchString(key) {
+			schemas = append(schemas, s.patternProperties[pattern])
+		}
+	}
+	if additionalProperties, ok := s.additionalProperties.(*subSchema); ok && len(schemas) == 0 {
+		schemas = append(schemas, additionalProperties)
+	}
+	return schemas
+}
+
+// coerceScalar converts a string or a value that is not an array to a type allowed by a schema
+func coerceScalar(types jsonSchemaType, value interface{}) (interface{}, bool) {
+
+	switch value.(type) {
+	case nil, bool, json.Number, string:
+	default:
+		return nil, false
+	}
+	if !types.IsTyped() || allowsType(types, jsonTypeOf(value)) {
+		return nil, false
+	}
+
+	if s, ok := value.(string); ok {
+		if s == "" && types.Contains(TYPE_NULL) {
+			return nil, true
+		}
+		if coercionNumberRegex.MatchString(s) && allowsType(types, jsonTypeOf(json.Number(s))) {
+			return json.Number(s), true
+		}
+		if (s == "true" || s == "false") && types.Contains(TYPE_BOOLEAN) {
+			return s == "true", true
+		}
+	}
+
+	if types.Contains(TYPE_ARRAY) {
+		return []interface{}{value}, true
+	}
+	return nil, false
+}
+
+// applyCoercions coerces the values of a Go document in place, the document being left untouched
+// when a value cannot be coerced. A coerced document is left untouched too : the caller holds it by
+// value, and the other coercions are within its coerced copy.
+func applyCoercions(document interface{}, coercions []Coercion, locale LocaleMessages) error {
+
+	for _, coercion := range coercions {
+		if coercion.Pointer == "" {
+			return nil
+		}
+	}
+
+	// every pointer is resolved before anything is written, a coercion resolving within the values
+	// of the previous ones, like the items of a value coerced to an array
+	written := map[string]interface{}{}
+	for _, coercion := range coercions {
+		if _, _, ok := coercionParent(document, coercion.Pointer, written); !ok {
+			return errors.New(formatErrorDescription(locale.CoercionNotInPlace(), ErrorDetails{"pointer": coercion.Pointer}))
+		}
+		written[coercion.Pointer] = coercion.To
+	}
+
+	for _, coercion := range coercions {
+		to := coercion.To
+		if array, ok := to.([]interface{}); ok {
+			to = append([]interface{}{}, array...)
+		}
+
+		parent, key, _ := coercionParent(document, coercion.Pointer, nil)
+		switch container := parent.(type) {
+		case map[string]interface{}:
+			container[key] = to
+		case []interface{}:
+			index, _ := strconv.Atoi(key)
+			container[index] = to
+		}
+	}
+	return nil
+}
+
+// coercionParent returns the object or array of a Go document holding the value at a JSON pointer
+// and its key, the values of written replacing the ones of the document at their pointers
+func coercionParent(document interface{}, pointer string, written map[string]interface{}) (interface{}, string, bool) {
+
+	tokens := strings.Split(pointer, "/")[1:]
+	if len(tokens) == 0 {
+		return nil, "", false
+	}
+
+	node, current := document, ""
+	for i, token := range tokens {
+		key := strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+
+		var child interface{}
+		switch container := node.(type) {
+		case map[string]interface{}:
+			value, ok := container[key]
+			if !ok {
+				return nil, "", false
+			}
+			child = value
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(container) {
+				return nil, "", false
+			}
+			child = container[index]
+		default:
+			return nil, "", false
+		}
+
+		if i == len(tokens)-1 {
+			return node, key, true
+		}
+
+		current += "/" + token
+		if value, ok := written[current]; ok {
+			child = value
+		}
+		node = child
+	}
+	return nil, "", false
+}
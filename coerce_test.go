@@ -0,0 +1,129 @@
+package gojsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const coerceSchema = `{
+	"type": "object",
+	"properties": {
+		"page": {"type": "integer", "minimum": 1},
+		"ratio": {"type": "number"},
+		"debug": {"type": "boolean"},
+		"parent": {"type": ["integer", "null"]},
+		"tags": {"type": "array", "items": {"type": "integer"}},
+		"name": {"type": "string"},
+		"id": {"anyOf": [{"type": "string", "pattern": "^[a-z]+$"}, {"type": "integer"}]}
+	},
+	"additionalProperties": {"$ref": "#/definitions/flag"},
+	"definitions": {"flag": {"type": "boolean"}}
+}`
+
+func TestValidateWithCoercion(t *testing.T) {
+	schema, err := NewSchema(NewStringLoader(coerceSchema))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	document := `{"page": "2", "ratio": "0.5", "debug": "true", "parent": "", "tags": "7", "name": "42", "id": "12", "verbose": "false"}`
+	result, err := schema.ValidateWithOptions(NewStringLoader(document), ValidateOptions{Coerce: true})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.True(t, result.Valid(), "%v", result.Errors())
+	assert.Equal(t, map[string]interface{}{
+		"page":    json.Number("2"),
+		"ratio":   json.Number("0.5"),
+		"debug":   true,
+		"parent":  nil,
+		"tags":    []interface{}{json.Number("7")},
+		"name":    "42",
+		"id":      json.Number("12"),
+		"verbose": false,
+	}, result.Document())
+
+	assert.Equal(t, []Coercion{
+		{Pointer: "/debug", From: "true", To: true},
+		{Pointer: "/id", From: "12", To: json.Number("12")},
+		{Pointer: "/page", From: "2", To: json.Number("2")},
+		{Pointer: "/parent", From: "", To: nil},
+		{Pointer: "/ratio", From: "0.5", To: json.Number("0.5")},
+		{Pointer: "/tags", From: "7", To: []interface{}{"7"}},
+		{Pointer: "/tags/0", From: "7", To: json.Number("7")},
+		{Pointer: "/verbose", From: "false", To: false},
+	}, result.Coercions())
+
+	// the values that cannot be coerced are validated as they are
+	result, err = schema.ValidateWithOptions(NewStringLoader(`{"page": "0", "debug": "yes", "id": "abc"}`), ValidateOptions{Coerce: true})
+	if !assert.Nil(t, err) {
+		return
+	}
+	var fields []string
+	for _, resultError := range result.Errors() {
+		fields = append(fields, resultError.Field()+" "+resultError.Type())
+	}
+	assert.ElementsMatch(t, []string{"page number_gte", "debug invalid_type"}, fields)
+	assert.Len(t, result.Coercions(), 1)
+
+	// without the option, the document is not coerced
+	result, err = schema.ValidateWithOptions(NewStringLoader(`{"page": "2"}`), ValidateOptions{})
+	if assert.Nil(t, err) {
+		assert.False(t, result.Valid())
+		assert.Equal(t, map[string]interface{}{"page": "2"}, result.Document())
+		assert.Empty(t, result.Coercions())
+	}
+}
+
+func TestValidateWithCoercionInPlace(t *testing.T) {
+	schema, err := NewSchema(NewStringLoader(coerceSchema))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	document := map[string]interface{}{"page": "3", "tags": []interface{}{"1", "2"}}
+	result, err := schema.ValidateWithOptions(NewGoLoader(document), ValidateOptions{Coerce: true, InPlace: true})
+	if assert.Nil(t, err) {
+		assert.True(t, result.Valid())
+		assert.Equal(t, map[string]interface{}{
+			"page": json.Number("3"),
+			"tags": []interface{}{json.Number("1"), json.Number("2")},
+		}, document)
+	}
+
+	_, err = schema.ValidateWithOptions(NewStringLoader(`{}`), ValidateOptions{Coerce: true, InPlace: true})
+	assert.EqualError(t, err, "Coercing in place needs the document of a Go loader")
+
+	// a coerced document cannot be replaced in place, Result.Document holds its value
+	schema, _ = NewSchema(NewStringLoader(`{"type": "integer"}`))
+	result, err = schema.ValidateWithOptions(NewGoLoader("42"), ValidateOptions{Coerce: true, InPlace: true})
+	if assert.Nil(t, err) {
+		assert.True(t, result.Valid())
+		assert.Equal(t, json.Number("42"), result.Document())
+		assert.Equal(t, []Coercion{{Pointer: "", From: "42", To: json.Number("42")}}, result.Coercions())
+	}
+}
+
+func TestValidateWithCoercionInPlaceUnresolved(t *testing.T) {
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {
+			"page": {"type": "integer"},
+			"filter": {"properties": {"limit": {"type": "integer"}}}
+		}
+	}`))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// the document is left untouched when one of its values cannot be coerced
+	document := map[string]interface{}{"page": "3", "filter": map[string]string{"limit": "10"}}
+	_, err = schema.ValidateWithOptions(NewGoLoader(document), ValidateOptions{Coerce: true, InPlace: true})
+	assert.EqualError(t, err, `The value at "/filter/limit" cannot be coerced in place`)
+	assert.Equal(t, "3", document["page"])
+
+	locale := NewCatalogLocale(map[string]string{"CoercionNoGoDocument": "Pas de document Go"})
+	_, err = schema.ValidateWithOptions(NewStringLoader(`{}`), ValidateOptions{Coerce: true, InPlace: true, Locale: locale})
+	assert.EqualError(t, err, "Pas de document Go")
+}
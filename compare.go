@@ -0,0 +1,555 @@
+package gojsonschema
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Schema compatibility
+// Compare walks two versions of a schema side by side, matching their subschemas by location,
+// and classifies the changes of their constraints. A change tightening a constraint may reject
+// documents the old schema accepted: it is not backward compatible, that is breaking. A change
+// loosening a constraint may accept documents the old schema rejected: it is not forward compatible.
+
+// Types of schema changes
+const (
+	CHANGE_REQUIRED_ADDED       = "required_added"
+	CHANGE_REQUIRED_REMOVED     = "required_removed"
+	CHANGE_PROPERTY_ADDED       = "property_added"
+	CHANGE_PROPERTY_REMOVED     = "property_removed"
+	CHANGE_TYPE_NARROWED        = "type_narrowed"
+	CHANGE_TYPE_WIDENED         = "type_widened"
+	CHANGE_TYPE_CHANGED         = "type_changed"
+	CHANGE_CONSTRAINT_TIGHTENED = "constraint_tightened"
+	CHANGE_CONSTRAINT_LOOSENED  = "constraint_loosened"
+	CHANGE_CONSTRAINT_CHANGED   = "constraint_changed"
+	CHANGE_ENUM_NARROWED        = "enum_narrowed"
+	CHANGE_ENUM_WIDENED         = "enum_widened"
+	CHANGE_ENUM_CHANGED         = "enum_changed"
+	CHANGE_REFERENCE_CHANGED    = "reference_changed"
+)
+
+const (
+	STRING_COMPARE_NONE      = "none"
+	STRING_COMPARE_NO_VALUE  = "-"
+	STRING_COMPARE_ANY_VALUE = "any value"
+	STRING_COMPARE_SCHEMA    = "schema"
+)
+
+// Directions of a change
+const (
+	compareTightened = 1
+	compareLoosened  = -1
+	compareChanged   = 2
+)
+
+// SchemaChange is a change of a constraint between two versions of a schema
+type SchemaChange struct {
+	// Type of the change, one of the CHANGE_ constants
+	Type string
+	// Pointer is the JSON pointer of the subschema in both versions, #/properties/name for instance
+	Pointer string
+	// Keyword the change is about
+	Keyword string
+	// Backward is true when the documents valid under the old schema are still valid
+	Backward bool
+	// Forward is true when the documents valid under the new schema are valid under the old one
+	Forward bool
+	// Breaking is true when the change is not backward compatible
+	Breaking    bool
+	Description string
+	Details     ErrorDetails
+}
+
+func (c SchemaChange) String() string {
+	return c.Pointer + ": " + c.Description
+}
+
+// Compare returns the changes from an old to a new version of a schema, sorted by pointer
+func Compare(old *Schema, new *Schema) []SchemaChange {
+
+	c := &comparer{visited: map[[2]*subSchema]bool{}}
+	c.compare(old.rootSchema, old.pool.GetStandaloneDocument(), new.rootSchema, new.pool.GetStandaloneDocument(), "#")
+
+	sort.SliceStable(c.changes, func(i, j int) bool {
+		return c.changes[i].Pointer < c.changes[j].Pointer
+	})
+
+	return c.changes
+}
+
+type comparer struct {
+	changes []SchemaChange
+	visited map[[2]*subSchema]bool
+}
+
+// comparedSchema is a subschema with its node in the schema document
+type comparedSchema struct {
+	schema *subSchema
+	node   interface{}
+}
+
+// anySchema returns a subschema allowing any value, the one of the undeclared properties of open objects
+func anySchema() comparedSchema {
+	return comparedSchema{&subSchema{}, map[string]interface{}{}}
+}
+
+// report records a change, direction being compareTightened, compareLoosened or compareChanged
+func (c *comparer) report(changeType string, direction int, pointer string, keyword string, format string, details ErrorDetails) {
+	change := SchemaChange{
+		Type:        changeType,
+		Pointer:     pointer,
+		Keyword:     keyword,
+		Backward:    direction == compareLoosened,
+		Forward:     direction == compareTightened,
+		Description: formatErrorDescription(format, details),
+		Details:     details,
+	}
+	change.Breaking = !change.Backward
+	c.changes = append(c.changes, change)
+}
+
+// compare compares two versions of a subschema, and their children found at the same location
+func (c *comparer) compare(old *subSchema, oldNode interface{}, new *subSchema, newNode interface{}, pointer string) {
+
+	if c.visited[[2]*subSchema{old, new}] {
+		return
+	}
+	c.visited[[2]*subSchema{old, new}] = true
+
+	oldRef, oldIsRef := schemaNode(oldNode)[KEY_REF].(string)
+	newRef, newIsRef := schemaNode(newNode)[KEY_REF].(string)
+	if oldIsRef || newIsRef {
+		// the referenced subschemas are compared where they are defined
+		if oldRef != newRef {
+			if !oldIsRef {
+				oldRef = STRING_COMPARE_NONE
+			}
+			if !newIsRef {
+				newRef = STRING_COMPARE_NONE
+			}
+			c.report(CHANGE_REFERENCE_CHANGED, compareChanged, pointer, KEY_REF, Locale.CompareReferenceChanged(), ErrorDetails{"old": oldRef, "new": newRef})
+		}
+		return
+	}
+
+	c.compareTypes(old, new, pointer)
+	c.compareRequired(old, new, pointer)
+	c.compareEnum(old, new, pointer)
+	c.compareConstraints(old, new, pointer)
+	c.compareProperties(comparedSchema{old, oldNode}, comparedSchema{new, newNode}, pointer)
+
+	children := map[string]comparedSchema{}
+	walkSubSchemas(old, oldNode, func(child *subSchema, childNode interface{}, tokens ...string) {
+		children[compareLocation(tokens)] = comparedSchema{child, childNode}
+	})
+	walkSubSchemas(new, newNode, func(child *subSchema, childNode interface{}, tokens ...string) {
+		location := compareLocation(tokens)
+		if tokens[0] == KEY_NOT || tokens[0] == KEY_IF {
+			// tightening not or if may accept more documents, their changes are not classified
+			return
+		}
+		if oldChild, ok := children[location]; ok {
+			c.compare(oldChild.schema, oldChild.node, child, childNode, pointer+location)
+		}
+	})
+}
+
+// compareLocation returns the pointer of a child relative to its parent
+func compareLocation(tokens []string) string {
+	location := ""
+	for _, token := range tokens {
+		location += "/" + jsonPointerToken(token)
+	}
+	return location
+}
+
+// allowsType reports whether a type is allowed by the types of a schema, any type when it is not typed
+func allowsType(types jsonSchemaType, t string) bool {
+	if !types.IsTyped() {
+		return true
+	}
+	return types.Contains(t) || t == TYPE_INTEGER && types.Contains(TYPE_NUMBER)
+}
+
+func typesString(types jsonSchemaType) string {
+	if !types.IsTyped() {
+		return STRING_COMPARE_NONE
+	}
+	return types.String()
+}
+
+func (c *comparer) compareTypes(old *subSchema, new *subSchema, pointer string) {
+
+	narrowed, widened := false, false
+	for _, t := range JSON_TYPES {
+		oldAllows, newAllows := allowsType(old.types, t), allowsType(new.types, t)
+		narrowed = narrowed || oldAllows && !newAllows
+		widened = widened || newAllows && !oldAllows
+	}
+
+	details := ErrorDetails{"old": typesString(old.types), "new": typesString(new.types)}
+	switch {
+	case narrowed && widened:
+		c.report(CHANGE_TYPE_CHANGED, compareChanged, pointer, KEY_TYPE, Locale.CompareTypeChanged(), details)
+	case narrowed:
+		c.report(CHANGE_TYPE_NARROWED, compareTightened, pointer, KEY_TYPE, Locale.CompareTypeChanged(), details)
+	case widened:
+		c.report(CHANGE_TYPE_WIDENED, compareLoosened, pointer, KEY_TYPE, Locale.CompareTypeChanged(), details)
+	}
+}
+
+func (c *comparer) compareRequired(old *subSchema, new *subSchema, pointer string) {
+
+	for _, property := range new.required {
+		if !isStringInSlice(old.required, property) {
+			c.report(CHANGE_REQUIRED_ADDED, compareTightened, pointer, KEY_REQUIRED, Locale.CompareRequiredAdded(), ErrorDetails{"property": property})
+		}
+	}
+	for _, property := range old.required {
+		if !isStringInSlice(new.required, property) {
+			c.report(CHANGE_REQUIRED_REMOVED, compareLoosened, pointer, KEY_REQUIRED, Locale.CompareRequiredRemoved(), ErrorDetails{"property": property})
+		}
+	}
+}
+
+// allowedValues returns the values an enum or a const allows, nil when any value is allowed
+func allowedValues(s *subSchema) ([]*jsonValue, string) {
+	if s._const != nil {
+		return []*jsonValue{s._const}, KEY_CONST
+	}
+	if s.enum.values != nil {
+		return s.enum.values, KEY_ENUM
+	}
+	return nil, ""
+}
+
+// missingValues returns the texts of the values of a list that are not in another one
+func missingValues(values []*jsonValue, from []*jsonValue) []string {
+	var missing []string
+	for _, v := range values {
+		found := false
+		for _, other := range from {
+			if v.equals(other) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, v.text)
+		}
+	}
+	return missing
+}
+
+func (c *comparer) compareEnum(old *subSchema, new *subSchema, pointer string) {
+
+	oldValues, oldKeyword := allowedValues(old)
+	newValues, newKeyword := allowedValues(new)
+	if oldValues == nil && newValues == nil {
+		return
+	}
+
+	keyword := newKeyword
+	if keyword == "" {
+		keyword = oldKeyword
+	}
+
+	var added, removed []string
+	switch {
+	case oldValues == nil:
+		// any value was allowed
+		removed = []string{STRING_COMPARE_NONE}
+	case newValues == nil:
+		added = []string{STRING_COMPARE_NONE}
+	default:
+		added = missingValues(newValues, oldValues)
+		removed = missingValues(oldValues, newValues)
+	}
+
+	details := ErrorDetails{"keyword": keyword, "added": compareValuesString(added), "removed": compareValuesString(removed)}
+	switch {
+	case len(added) > 0 && len(removed) > 0:
+		c.report(CHANGE_ENUM_CHANGED, compareChanged, pointer, keyword, Locale.CompareEnumChanged(), details)
+	case len(removed) > 0:
+		c.report(CHANGE_ENUM_NARROWED, compareTightened, pointer, keyword, Locale.CompareEnumChanged(), details)
+	case len(added) > 0:
+		c.report(CHANGE_ENUM_WIDENED, compareLoosened, pointer, keyword, Locale.CompareEnumChanged(), details)
+	}
+}
+
+func compareValuesString(values []string) string {
+	if len(values) == 0 {
+		return STRING_COMPARE_NO_VALUE
+	}
+	if len(values) == 1 && values[0] == STRING_COMPARE_NONE {
+		return STRING_COMPARE_ANY_VALUE
+	}
+	return strings.Join(values, ", ")
+}
+
+// compareBound returns how a bound changed, lower telling whether it is a lower bound
+func compareBound(old *jsonNumberValue, oldExclusive bool, new *jsonNumberValue, newExclusive bool, lower bool) int {
+	switch {
+	case old == nil && new == nil:
+		return 0
+	case old == nil:
+		return compareTightened
+	case new == nil:
+		return compareLoosened
+	}
+
+	cmp := new.Cmp(old)
+	if !lower {
+		cmp = -cmp
+	}
+	if cmp == 0 && oldExclusive != newExclusive {
+		if newExclusive {
+			cmp = 1
+		} else {
+			cmp = -1
+		}
+	}
+
+	switch {
+	case cmp > 0:
+		return compareTightened
+	case cmp < 0:
+		return compareLoosened
+	}
+	return 0
+}
+
+func intNumber(i *int) *jsonNumberValue {
+	if i == nil {
+		return nil
+	}
+	n, _ := parseJsonNumber(json.Number(strconv.Itoa(*i)))
+	return n
+}
+
+func boundString(n *jsonNumberValue, exclusive bool) string {
+	if n == nil {
+		return STRING_COMPARE_NONE
+	}
+	if exclusive {
+		return "exclusive " + n.Number().String()
+	}
+	return n.Number().String()
+}
+
+func (c *comparer) compareConstraints(old *subSchema, new *subSchema, pointer string) {
+
+	bounds := []struct {
+		keyword                    string
+		old, new                   *jsonNumberValue
+		oldExclusive, newExclusive bool
+		lower                      bool
+	}{
+		{KEY_MINIMUM, old.minimum, new.minimum, old.exclusiveMinimum, new.exclusiveMinimum, true},
+		{KEY_MAXIMUM, old.maximum, new.maximum, old.exclusiveMaximum, new.exclusiveMaximum, false},
+		{KEY_MIN_LENGTH, intNumber(old.minLength), intNumber(new.minLength), false, false, true},
+		{KEY_MAX_LENGTH, intNumber(old.maxLength), intNumber(new.maxLength), false, false, false},
+		{KEY_MIN_ITEMS, intNumber(old.minItems), intNumber(new.minItems), false, false, true},
+		{KEY_MAX_ITEMS, intNumber(old.maxItems), intNumber(new.maxItems), false, false, false},
+		{KEY_MIN_PROPERTIES, intNumber(old.minProperties), intNumber(new.minProperties), false, false, true},
+		{KEY_MAX_PROPERTIES, intNumber(old.maxProperties), intNumber(new.maxProperties), false, false, false},
+	}
+	for _, b := range bounds {
+		direction := compareBound(b.old, b.oldExclusive, b.new, b.newExclusive, b.lower)
+		c.reportConstraint(direction, pointer, b.keyword, boundString(b.old, b.oldExclusive), boundString(b.new, b.newExclusive))
+	}
+
+	// a multiple of the old multipleOf only accepts some of the numbers it accepted
+	direction := 0
+	switch {
+	case old.multipleOf == nil && new.multipleOf == nil:
+	case old.multipleOf == nil:
+		direction = compareTightened
+	case new.multipleOf == nil:
+		direction = compareLoosened
+	case old.multipleOf.Cmp(new.multipleOf) == 0:
+	case new.multipleOf.IsMultipleOf(old.multipleOf):
+		direction = compareTightened
+	case old.multipleOf.IsMultipleOf(new.multipleOf):
+		direction = compareLoosened
+	default:
+		direction = compareChanged
+	}
+	c.reportConstraint(direction, pointer, KEY_MULTIPLE_OF, boundString(old.multipleOf, false), boundString(new.multipleOf, false))
+
+	if old.uniqueItems != new.uniqueItems {
+		direction := compareLoosened
+		if new.uniqueItems {
+			direction = compareTightened
+		}
+		c.reportConstraint(direction, pointer, KEY_UNIQUE_ITEMS, boolString(old.uniqueItems), boolString(new.uniqueItems))
+	}
+
+	// a schema is compared to true by compareProperties
+	oldAdditional, newAdditional := additionalPropertiesString(old), additionalPropertiesString(new)
+	if oldAdditional != newAdditional && (oldAdditional == "false" || newAdditional == "false") {
+		direction := compareLoosened
+		if newAdditional == "false" {
+			direction = compareTightened
+		}
+		c.reportConstraint(direction, pointer, KEY_ADDITIONAL_PROPERTIES, oldAdditional, newAdditional)
+	}
+
+	oldPattern, newPattern := STRING_COMPARE_NONE, STRING_COMPARE_NONE
+	if old.pattern != nil {
+		oldPattern = old.pattern.String()
+	}
+	if new.pattern != nil {
+		newPattern = new.pattern.String()
+	}
+	c.reportConstraint(compareKeywordValue(oldPattern, newPattern), pointer, KEY_PATTERN, oldPattern, newPattern)
+
+	oldFormat, newFormat := old.format, new.format
+	if oldFormat == "" {
+		oldFormat = STRING_COMPARE_NONE
+	}
+	if newFormat == "" {
+		newFormat = STRING_COMPARE_NONE
+	}
+	c.reportConstraint(compareKeywordValue(oldFormat, newFormat), pointer, KEY_FORMAT, oldFormat, newFormat)
+}
+
+// additionalPropertiesString returns the additionalProperties of a subschema, true when not set
+func additionalPropertiesString(s *subSchema) string {
+	switch additionalProperties := s.additionalProperties.(type) {
+	case bool:
+		return boolString(additionalProperties)
+	case *subSchema:
+		return STRING_COMPARE_SCHEMA
+	}
+	return "true"
+}
+
+// compareProperties compares the properties one version declares to the subschema the other version
+// applies to them, and an additionalProperties schema to the any value of an open object
+func (c *comparer) compareProperties(old comparedSchema, new comparedSchema, pointer string) {
+
+	oldProperties, newProperties := declaredProperties(old), declaredProperties(new)
+
+	for _, name := range sortedMapKeys(newProperties) {
+		if _, declared := oldProperties[name]; !declared {
+			implicit, forbidden := undeclaredProperty(old, name)
+			c.compareProperty(CHANGE_PROPERTY_ADDED, Locale.ComparePropertyAdded(), name, implicit, newProperties[name], forbidden, compareLoosened, pointer)
+		}
+	}
+	for _, name := range sortedMapKeys(oldProperties) {
+		if _, declared := newProperties[name]; !declared {
+			implicit, forbidden := undeclaredProperty(new, name)
+			c.compareProperty(CHANGE_PROPERTY_REMOVED, Locale.ComparePropertyRemoved(), name, oldProperties[name], implicit, forbidden, compareTightened, pointer)
+		}
+	}
+
+	oldAdditional, newAdditional := additionalPropertiesString(old.schema), additionalPropertiesString(new.schema)
+	location := pointer + "/" + KEY_ADDITIONAL_PROPERTIES
+	switch {
+	case oldAdditional == "true" && newAdditional == STRING_COMPARE_SCHEMA:
+		anyValue := anySchema()
+		c.compare(anyValue.schema, anyValue.node, new.schema.additionalProperties.(*subSchema), schemaNode(new.node)[KEY_ADDITIONAL_PROPERTIES], location)
+	case oldAdditional == STRING_COMPARE_SCHEMA && newAdditional == "true":
+		anyValue := anySchema()
+		c.compare(old.schema.additionalProperties.(*subSchema), schemaNode(old.node)[KEY_ADDITIONAL_PROPERTIES], anyValue.schema, anyValue.node, location)
+	}
+}
+
+// compareProperty compares a property declared by one version only, forbidden telling whether the
+// other version rejects it, which makes the change go in the direction given
+func (c *comparer) compareProperty(changeType string, format string, name string, old comparedSchema, new comparedSchema, forbidden bool, direction int, pointer string) {
+
+	details := ErrorDetails{"property": name}
+	if forbidden {
+		c.report(changeType, direction, pointer, KEY_PROPERTIES, format, details)
+		return
+	}
+
+	// the change goes in the direction of the changes of the property
+	nested := &comparer{visited: c.visited}
+	nested.compare(old.schema, old.node, new.schema, new.node, pointer+"/"+KEY_PROPERTIES+"/"+jsonPointerToken(name))
+	if len(nested.changes) == 0 {
+		return
+	}
+	direction = 0
+	for _, change := range nested.changes {
+		changeDirection := compareChanged
+		if change.Forward && !change.Backward {
+			changeDirection = compareTightened
+		} else if change.Backward && !change.Forward {
+			changeDirection = compareLoosened
+		}
+		if direction != 0 && direction != changeDirection {
+			changeDirection = compareChanged
+		}
+		direction = changeDirection
+	}
+	c.report(changeType, direction, pointer, KEY_PROPERTIES, format, details)
+	c.changes = append(c.changes, nested.changes...)
+}
+
+// declaredProperties returns the subschemas of the properties a subschema declares, by name
+func declaredProperties(s comparedSchema) map[string]comparedSchema {
+	properties := map[string]comparedSchema{}
+	walkSubSchemas(s.schema, s.node, func(child *subSchema, childNode interface{}, tokens ...string) {
+		if tokens[0] == KEY_PROPERTIES {
+			properties[tokens[1]] = comparedSchema{child, childNode}
+		}
+	})
+	return properties
+}
+
+// undeclaredProperty returns the subschema a subschema applies to a property it does not declare,
+// and whether it rejects it
+func undeclaredProperty(s comparedSchema, name string) (comparedSchema, bool) {
+	for _, pattern := range sortedMapKeys(s.schema.patternProperties) {
+		if s.schema.patternMatchers[pattern].MatchString(name) {
+			patternProperties, _ := schemaNode(s.node)[KEY_PATTERN_PROPERTIES].(map[string]interface{})
+			return comparedSchema{s.schema.patternProperties[pattern], patternProperties[pattern]}, false
+		}
+	}
+	switch additionalProperties := s.schema.additionalProperties.(type) {
+	case bool:
+		return anySchema(), !additionalProperties
+	case *subSchema:
+		return comparedSchema{additionalProperties, schemaNode(s.node)[KEY_ADDITIONAL_PROPERTIES]}, false
+	}
+	return anySchema(), false
+}
+
+// compareKeywordValue returns how a keyword without an order changed
+func compareKeywordValue(old string, new string) int {
+	switch {
+	case old == new:
+		return 0
+	case old == STRING_COMPARE_NONE:
+		return compareTightened
+	case new == STRING_COMPARE_NONE:
+		return compareLoosened
+	}
+	return compareChanged
+}
+
+// isMultipleOf reports whether a is a multiple of b
+func boolString(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
+
+func (c *comparer) reportConstraint(direction int, pointer string, keyword string, old string, new string) {
+	details := ErrorDetails{"keyword": keyword, "old": old, "new": new}
+	switch direction {
+	case compareTightened:
+		c.report(CHANGE_CONSTRAINT_TIGHTENED, direction, pointer, keyword, Locale.CompareConstraintChanged(), details)
+	case compareLoosened:
+		c.report(CHANGE_CONSTRAINT_LOOSENED, direction, pointer, keyword, Locale.CompareConstraintChanged(), details)
+	case compareChanged:
+		c.report(CHANGE_CONSTRAINT_CHANGED, direction, pointer, keyword, Locale.CompareConstraintChanged(), details)
+	}
+}
@@ -0,0 +1,161 @@
+package gojsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func compareSchemas(t *testing.T, old string, new string) []SchemaChange {
+	oldSchema, err := NewSchema(NewStringLoader(old))
+	if !assert.Nil(t, err) {
+		return nil
+	}
+	newSchema, err := NewSchema(NewStringLoader(new))
+	if !assert.Nil(t, err) {
+		return nil
+	}
+	return Compare(oldSchema, newSchema)
+}
+
+func changeTypes(changes []SchemaChange) []string {
+	var types []string
+	for _, change := range changes {
+		types = append(types, change.Pointer+" "+change.Keyword+" "+change.Type)
+	}
+	return types
+}
+
+func TestCompare(t *testing.T) {
+	testCases := []struct {
+		old, new string
+		changes  []string
+	}{
+		{`{"type": "string"}`, `{"type": "string"}`, nil},
+		{`{"required": ["a"]}`, `{"required": ["b"]}`, []string{"# required required_added", "# required required_removed"}},
+		{`{"type": ["string", "integer"]}`, `{"type": "string"}`, []string{"# type type_narrowed"}},
+		{`{"type": "integer"}`, `{"type": "number"}`, []string{"# type type_widened"}},
+		{`{}`, `{"type": "object"}`, []string{"# type type_narrowed"}},
+		{`{"type": "string"}`, `{"type": "boolean"}`, []string{"# type type_changed"}},
+		{
+			`{"properties": {"n": {"minimum": 1, "maximum": 10, "multipleOf": 2}}}`,
+			`{"properties": {"n": {"exclusiveMinimum": 1, "maximum": 20, "multipleOf": 4}}}`,
+			[]string{"#/properties/n minimum constraint_tightened", "#/properties/n maximum constraint_loosened", "#/properties/n multipleOf constraint_tightened"},
+		},
+		{
+			`{"items": {"maxLength": 5, "pattern": "^a"}, "uniqueItems": true}`,
+			`{"items": {"minLength": 1, "format": "email"}, "maxItems": 3}`,
+			[]string{
+				"# maxItems constraint_tightened",
+				"# uniqueItems constraint_loosened",
+				"#/items minLength constraint_tightened",
+				"#/items maxLength constraint_loosened",
+				"#/items pattern constraint_loosened",
+				"#/items format constraint_tightened",
+			},
+		},
+		{`{"enum": [1, 2, 3]}`, `{"enum": [1, 2]}`, []string{"# enum enum_narrowed"}},
+		{`{"enum": [1, 2]}`, `{"enum": [1.0, 2, 3]}`, []string{"# enum enum_widened"}},
+		{`{"const": "a"}`, `{"enum": ["b"]}`, []string{"# enum enum_changed"}},
+		{`{}`, `{"const": "a"}`, []string{"# const enum_narrowed"}},
+		{`{"additionalProperties": true}`, `{"additionalProperties": false}`, []string{"# additionalProperties constraint_tightened"}},
+		{
+			`{"definitions": {"a": {"type": "string"}}, "properties": {"x": {"$ref": "#/definitions/a"}}}`,
+			`{"definitions": {"a": {"type": ["string", "null"]}, "b": {}}, "properties": {"x": {"$ref": "#/definitions/b"}}}`,
+			[]string{"#/definitions/a type type_widened", "#/properties/x $ref reference_changed"},
+		},
+		{`{"not": {"type": "string"}}`, `{"not": {"type": ["string", "null"]}}`, nil},
+		{
+			`{"type": "object"}`,
+			`{"type": "object", "properties": {"age": {"type": "integer"}, "note": {}}}`,
+			[]string{"# properties property_added", "#/properties/age type type_narrowed"},
+		},
+		{
+			`{"properties": {"a": {}, "b": {"type": "string"}}, "additionalProperties": false}`,
+			`{"properties": {"a": {}}, "additionalProperties": false}`,
+			[]string{"# properties property_removed"},
+		},
+		{
+			`{"properties": {"a": {}}, "additionalProperties": false}`,
+			`{"properties": {"a": {}, "b": {"type": "string"}}, "additionalProperties": false}`,
+			[]string{"# properties property_added"},
+		},
+		{
+			`{"properties": {"a": {"type": "string"}}, "additionalProperties": {"type": "string"}}`,
+			`{"additionalProperties": {"type": "string"}}`,
+			nil,
+		},
+		{`{}`, `{"additionalProperties": false}`, []string{"# additionalProperties constraint_tightened"}},
+		{`{"additionalProperties": false}`, `{"additionalProperties": {"type": "string"}}`, []string{"# additionalProperties constraint_loosened"}},
+		{`{}`, `{"additionalProperties": {"type": "string"}}`, []string{"#/additionalProperties type type_narrowed"}},
+	}
+
+	for _, testCase := range testCases {
+		changes := compareSchemas(t, testCase.old, testCase.new)
+		assert.Equal(t, testCase.changes, changeTypes(changes), "old: %s, new: %s", testCase.old, testCase.new)
+	}
+}
+
+func TestCompareCompatibility(t *testing.T) {
+	changes := compareSchemas(t,
+		`{"properties": {"age": {"type": "integer", "minimum": 0}, "name": {"enum": ["a", "b"]}}, "required": ["name"]}`,
+		`{"properties": {"age": {"type": "integer", "minimum": 18}, "name": {"enum": ["a", "b", "c"]}}}`,
+	)
+	if !assert.Len(t, changes, 3) {
+		return
+	}
+
+	assert.Equal(t, SchemaChange{
+		Type:        CHANGE_REQUIRED_REMOVED,
+		Pointer:     "#",
+		Keyword:     KEY_REQUIRED,
+		Backward:    true,
+		Forward:     false,
+		Breaking:    false,
+		Description: "name is no longer required",
+		Details:     ErrorDetails{"property": "name"},
+	}, changes[0])
+
+	assert.Equal(t, "#/properties/age: minimum changed from 0 to 18", changes[1].String())
+	assert.True(t, changes[1].Breaking)
+	assert.True(t, changes[1].Forward)
+
+	assert.Equal(t, "#/properties/name: enum changed, added: \"c\", removed: -", changes[2].String())
+	assert.False(t, changes[2].Breaking)
+
+	// cyclic schemas terminate
+	changes = compareSchemas(t,
+		`{"properties": {"next": {"$ref": "#"}, "value": {"type": "integer"}}}`,
+		`{"properties": {"next": {"$ref": "#"}, "value": {"type": "number"}}}`,
+	)
+	assert.Equal(t, []string{"#/properties/value type type_widened"}, changeTypes(changes))
+}
+
+func TestComparePropertiesCompatibility(t *testing.T) {
+	// a constrained property added to an open object rejects the old documents with another value
+	changes := compareSchemas(t, `{"type": "object"}`, `{"type": "object", "properties": {"age": {"type": "integer"}}}`)
+	if assert.Len(t, changes, 2) {
+		assert.Equal(t, "#: Property age added", changes[0].String())
+		assert.True(t, changes[0].Breaking)
+		assert.True(t, changes[0].Forward)
+	}
+
+	// a property removed from a closed object is rejected from now on
+	changes = compareSchemas(t,
+		`{"properties": {"a": {}, "b": {}}, "additionalProperties": false}`,
+		`{"properties": {"a": {}}, "additionalProperties": false}`,
+	)
+	if assert.Len(t, changes, 1) {
+		assert.Equal(t, CHANGE_PROPERTY_REMOVED, changes[0].Type)
+		assert.Equal(t, ErrorDetails{"property": "b"}, changes[0].Details)
+		assert.True(t, changes[0].Breaking)
+	}
+
+	// removing a constrained property from an open object accepts more documents
+	changes = compareSchemas(t, `{"properties": {"b": {"type": "string"}}}`, `{}`)
+	if assert.Len(t, changes, 2) {
+		assert.Equal(t, CHANGE_PROPERTY_REMOVED, changes[0].Type)
+		assert.False(t, changes[0].Breaking)
+		assert.True(t, changes[0].Backward)
+	}
+}
@@ -0,0 +1,232 @@
+package gojsonschema
+
+import (
+	"sort"
+	"strconv"
+)
+
+// Schema dereferencing
+// Dereference replaces every $ref of a schema by the subschema it references, as resolved by the
+// compilation ( refSchema ). A reference to a subschema being expanded would make the tree
+// infinite: such back-edges stay $ref's and are reported. They reference the root, or the
+// definitions of the result where the subschemas they close the cycle on are expanded once.
+// The other definitions are dropped, as nothing references them anymore.
+
+// Dereference returns a schema with its references expanded, and the JSON pointers
+// of the references kept because they are cyclic
+func Dereference(l JSONLoader) (interface{}, []string, error) {
+	return NewSchemaLoader().Dereference(l)
+}
+
+// Dereference returns a schema with its references expanded, and the JSON pointers
+// of the references kept because they are cyclic
+func (sl *SchemaLoader) Dereference(rootSchema JSONLoader) (interface{}, []string, error) {
+
+	schema, err := sl.Compile(rootSchema)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	d := &dereferencer{
+		schema:          schema,
+		nodes:           map[*subSchema]interface{}{},
+		definitionNames: map[*subSchema]string{},
+		expanding:       map[*subSchema]bool{},
+		names:           map[*subSchema]string{},
+		used:            map[string]bool{},
+	}
+
+	root := schema.pool.GetStandaloneDocument()
+	d.record(schema.rootSchema, root)
+	d.expanding[schema.rootSchema] = true
+	document := d.expand(schema.rootSchema, root, "")
+
+	// the cyclic subschemas, expanded while others may be found
+	definitions := map[string]interface{}{}
+	for i := 0; i < len(d.cyclic) && d.err == nil; i++ {
+		s := d.cyclic[i]
+		name := d.names[s]
+		node, err := d.node(s, s)
+		if err != nil {
+			return nil, nil, err
+		}
+		d.expanding[s] = true
+		definitions[name] = d.expand(s, node, "/"+KEY_DEFINITIONS+"/"+jsonPointerToken(name))
+		delete(d.expanding, s)
+	}
+	if d.err != nil {
+		return nil, nil, d.err
+	}
+	if m, ok := document.(map[string]interface{}); ok && len(definitions) > 0 {
+		m[KEY_DEFINITIONS] = definitions
+	}
+
+	sort.Strings(d.cycles)
+
+	return document, d.cycles, nil
+}
+
+type dereferencer struct {
+	schema *Schema
+	// schema document nodes of the subschemas, and the names of the definitions
+	nodes           map[*subSchema]interface{}
+	definitionNames map[*subSchema]string
+	// subschemas being expanded
+	expanding map[*subSchema]bool
+	// subschemas closing a cycle, and their names in the definitions of the result
+	cyclic []*subSchema
+	names  map[*subSchema]string
+	used   map[string]bool
+	// pointers of the references kept
+	cycles []string
+	err    error
+}
+
+// record maps a subschema and its children to their schema document nodes
+func (d *dereferencer) record(s *subSchema, node interface{}) {
+
+	if _, ok := d.nodes[s]; ok {
+		return
+	}
+	d.nodes[s] = node
+
+	walkSubSchemas(s, node, func(child *subSchema, childNode interface{}, tokens ...string) {
+		if tokens[0] == KEY_DEFINITIONS {
+			d.definitionNames[child] = tokens[1]
+		}
+		d.record(child, childNode)
+	})
+}
+
+// reference returns the $ref of a back-edge to a subschema
+func (d *dereferencer) reference(s *subSchema) string {
+
+	if s == d.schema.rootSchema {
+		return "#"
+	}
+
+	name, ok := d.names[s]
+	if !ok {
+		base := d.definitionNames[s]
+		if base == "" {
+			base = "schema"
+		}
+		name = base
+		for i := 2; d.used[name]; i++ {
+			name = base + strconv.Itoa(i)
+		}
+		d.used[name] = true
+		d.names[s] = name
+		d.cyclic = append(d.cyclic, s)
+	}
+
+	return "#/" + KEY_DEFINITIONS + "/" + jsonPointerToken(name)
+}
+
+// node returns the schema document node of a subschema referenced by s
+func (d *dereferencer) node(s *subSchema, ref *subSchema) (interface{}, error) {
+
+	if node, ok := d.nodes[ref]; ok {
+		return node, nil
+	}
+
+	// parsed by parseReference, from the referenced document
+	document := d.schema.pool.GetStandaloneDocument()
+	if !s.ref.HasFragmentOnly {
+		spd, err := d.schema.pool.GetDocument(*s.ref)
+		if err != nil {
+			return nil, err
+		}
+		document = spd.Document
+	}
+
+	jsonPointer := s.ref.GetPointer()
+	node, _, err := jsonPointer.Get(document)
+	if err != nil {
+		return nil, err
+	}
+	node = convertDocumentNode(node)
+
+	d.record(ref, node)
+	return node, nil
+}
+
+// expand returns the schema document node of a subschema with its references expanded,
+// pointer being its location in the result
+func (d *dereferencer) expand(s *subSchema, node interface{}, pointer string) interface{} {
+
+	m, ok := node.(map[string]interface{})
+	if !ok || d.err != nil {
+		return node
+	}
+
+	if s.refSchema != nil {
+		if d.expanding[s.refSchema] {
+			d.cycles = append(d.cycles, "#"+pointer)
+			return map[string]interface{}{KEY_REF: d.reference(s.refSchema)}
+		}
+
+		refNode, err := d.node(s, s.refSchema)
+		if err != nil {
+			d.err = err
+			return node
+		}
+
+		expanded := d.expand(s.refSchema, refNode, pointer)
+		if em, ok := expanded.(map[string]interface{}); ok {
+			// the annotations of the reference describe it better than the ones of its target
+			for _, k := range []string{KEY_TITLE, KEY_DESCRIPTION} {
+				if v, ok := m[k]; ok {
+					em[k] = v
+				}
+			}
+		}
+		return expanded
+	}
+
+	if s != d.schema.rootSchema && !d.expanding[s] {
+		d.expanding[s] = true
+		defer delete(d.expanding, s)
+	}
+
+	result := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		switch k {
+		case KEY_DEFINITIONS, KEY_DEFS:
+			continue
+		case KEY_ID, KEY_ID_NEW, KEY_SCHEMA:
+			// only the root is a resource, its subschemas are not resolved against their own $id anymore
+			if pointer != "" {
+				continue
+			}
+		}
+		result[k] = convertDocumentNode(v)
+	}
+
+	walkSubSchemas(s, m, func(child *subSchema, childNode interface{}, tokens ...string) {
+		if tokens[0] == KEY_DEFINITIONS {
+			return
+		}
+
+		childPointer := pointer
+		for _, token := range tokens {
+			childPointer += "/" + jsonPointerToken(token)
+		}
+		expanded := d.expand(child, childNode, childPointer)
+
+		if len(tokens) == 1 {
+			result[tokens[0]] = expanded
+			return
+		}
+		switch container := result[tokens[0]].(type) {
+		case map[string]interface{}:
+			container[tokens[1]] = expanded
+		case []interface{}:
+			if i, err := strconv.Atoi(tokens[1]); err == nil && i < len(container) {
+				container[i] = expanded
+			}
+		}
+	})
+
+	return result
+}
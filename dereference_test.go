@@ -0,0 +1,117 @@
+package gojsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDereference(t *testing.T) {
+	sl := NewSchemaLoader()
+	assert.Nil(t, sl.AddSchema("http://example.com/address.json", NewStringLoader(`{
+		"$id": "http://example.com/address.json",
+		"type": "object",
+		"properties": {
+			"street": {"$ref": "#/definitions/line"}
+		},
+		"definitions": {
+			"line": {"type": "string", "maxLength": 40}
+		}
+	}`)))
+
+	document, cycles, err := sl.Dereference(NewStringLoader(`{
+		"definitions": {
+			"name": {"type": "string", "minLength": 1}
+		},
+		"properties": {
+			"first": {"$ref": "#/definitions/name"},
+			"last": {"$ref": "#/definitions/name", "title": "Last name"},
+			"home": {"$ref": "http://example.com/address.json"},
+			"aliases": {"type": "array", "items": [{"$ref": "#/definitions/name"}]}
+		}
+	}`))
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Empty(t, cycles)
+
+	text, err := json.Marshal(document)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"properties": {
+			"first": {"type": "string", "minLength": 1},
+			"last": {"type": "string", "minLength": 1, "title": "Last name"},
+			"home": {
+				"type": "object",
+				"properties": {
+					"street": {"type": "string", "maxLength": 40}
+				}
+			},
+			"aliases": {"type": "array", "items": [{"type": "string", "minLength": 1}]}
+		}
+	}`, string(text))
+}
+
+func TestDereferenceCycles(t *testing.T) {
+	document, cycles, err := Dereference(NewStringLoader(`{
+		"$id": "http://example.com/tree.json",
+		"definitions": {
+			"node": {
+				"type": "object",
+				"properties": {
+					"value": {"type": "integer"},
+					"children": {"type": "array", "items": {"$ref": "#/definitions/node"}},
+					"root": {"$ref": "#"}
+				}
+			}
+		},
+		"properties": {
+			"tree": {"$ref": "#/definitions/node"}
+		}
+	}`))
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, []string{
+		"#/definitions/node/properties/children/items",
+		"#/definitions/node/properties/root",
+		"#/properties/tree/properties/children/items",
+		"#/properties/tree/properties/root",
+	}, cycles)
+
+	node := `{
+		"type": "object",
+		"properties": {
+			"value": {"type": "integer"},
+			"children": {"type": "array", "items": {"$ref": "#/definitions/node"}},
+			"root": {"$ref": "#"}
+		}
+	}`
+	text, err := json.Marshal(document)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"$id": "http://example.com/tree.json",
+		"definitions": {"node": `+node+`},
+		"properties": {"tree": `+node+`}
+	}`, string(text))
+
+	// the kept references validate like the original ones
+	schema, err := NewSchema(NewGoLoader(document))
+	if !assert.Nil(t, err) {
+		return
+	}
+	result, err := schema.Validate(NewStringLoader(`{"tree": {"value": 1, "children": [{"value": 2, "children": [{"value": "3"}]}]}}`))
+	assert.Nil(t, err)
+	assert.Len(t, result.Errors(), 1)
+}
+
+func TestDereferenceErrors(t *testing.T) {
+	_, _, err := Dereference(NewStringLoader(`{"$ref": "#/definitions/missing"}`))
+	assert.NotNil(t, err)
+
+	document, cycles, err := Dereference(NewStringLoader(`false`))
+	assert.Nil(t, err)
+	assert.Empty(t, cycles)
+	assert.Equal(t, false, document)
+}
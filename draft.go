@@ -0,0 +1,98 @@
+package gojsonschema
+
+import (
+	"errors"
+	"reflect"
+)
+
+// Draft selects the version of JSON Schema a schema is compiled as
+type Draft int
+
+const (
+	// Hybrid accepts the keywords of draft-04, draft-06 and draft-07 together
+	Hybrid Draft = iota
+	// Draft4 is draft-04 : id, boolean exclusive bounds, no boolean schemas
+	Draft4
+	// Draft6 is draft-06 : $id, numeric exclusive bounds, const, contains and propertyNames
+	Draft6
+	// Draft7 is draft-07 : draft-06 with if, then and else
+	Draft7
+)
+
+func (d Draft) String() string {
+	switch d {
+	case Draft4:
+		return "draft-04"
+	case Draft6:
+		return "draft-06"
+	case Draft7:
+		return "draft-07"
+	}
+	return "hybrid"
+}
+
+// Keywords introduced after draft-04 and the first draft defining them.
+// The keywords of later drafts are unknown to earlier ones, and ignored like any unknown keyword.
+var draftKeywords = map[string]Draft{
+	KEY_ID_NEW:         Draft6,
+	KEY_CONST:          Draft6,
+	KEY_CONTAINS:       Draft6,
+	KEY_PROPERTY_NAMES: Draft6,
+	KEY_IF:             Draft7,
+	KEY_THEN:           Draft7,
+	KEY_ELSE:           Draft7,
+}
+
+// checkDraftKeywords returns the keywords of a schema known to a draft, and checks the ones
+// whose syntax changed between drafts
+func checkDraftKeywords(documentNode interface{}, draft Draft) (interface{}, error) {
+
+	if draft == Hybrid {
+		return documentNode, nil
+	}
+
+	if isKind(documentNode, reflect.Bool) && draft == Draft4 {
+		return nil, errors.New(formatErrorDescription(
+			Locale.KeywordNotSupported(),
+			ErrorDetails{"key": "boolean schema", "dialect": draft.String()},
+		))
+	}
+
+	m, ok := documentNode.(map[string]interface{})
+	if !ok {
+		return documentNode, nil
+	}
+
+	// draft-04 bounds are made exclusive by a boolean, later ones are numbers
+	for _, k := range []string{KEY_EXCLUSIVE_MINIMUM, KEY_EXCLUSIVE_MAXIMUM} {
+		if !existsMapKey(m, k) {
+			continue
+		}
+		if draft == Draft4 && !isKind(m[k], reflect.Bool) {
+			return nil, errors.New(formatErrorDescription(
+				Locale.MustBeOfA(),
+				ErrorDetails{"x": k, "y": TYPE_BOOLEAN},
+			))
+		}
+		if draft != Draft4 && !isJsonNumber(m[k]) {
+			return nil, errors.New(formatErrorDescription(
+				Locale.MustBeOfA(),
+				ErrorDetails{"x": k, "y": STRING_NUMBER},
+			))
+		}
+	}
+
+	known := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if since, ok := draftKeywords[k]; ok && draft < since {
+			continue
+		}
+		if k == KEY_ID && draft != Draft4 {
+			// renamed $id in draft-06
+			continue
+		}
+		known[k] = v
+	}
+
+	return known, nil
+}
@@ -0,0 +1,120 @@
+package gojsonschema
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDraftSuites(t *testing.T) {
+	drafts := map[string]Draft{"draft4": Draft4, "draft6": Draft6, "draft7": Draft7}
+
+	for directory, draft := range drafts {
+		files, err := ioutil.ReadDir(filepath.Join("testdata", directory))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sl := NewSchemaLoader()
+		sl.Draft = draft
+
+		for _, file := range files {
+			// the remote references need the server of TestSuite
+			if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") || file.Name() == "refRemote.json" {
+				continue
+			}
+			testSuiteFile(t, sl, filepath.Join("testdata", directory, file.Name()))
+		}
+	}
+}
+
+func TestDraftKeywords(t *testing.T) {
+	testCases := []struct {
+		draft  Draft
+		schema string
+		valid  bool
+	}{
+		// unknown keywords are ignored
+		{Draft4, `{"const": 1}`, true},
+		{Draft4, `{"contains": {"const": 1}}`, true},
+		{Draft4, `{"propertyNames": {"maxLength": 0}}`, true},
+		{Draft6, `{"const": 1}`, false},
+		{Draft6, `{"if": true, "then": false}`, true},
+		{Draft7, `{"if": true, "then": false}`, false},
+		{Hybrid, `{"if": true, "then": false}`, false},
+		{Draft4, `{"id": "http://example.com/a.json", "definitions": {"x": {"const": 1}}}`, true},
+	}
+
+	for _, testCase := range testCases {
+		sl := NewSchemaLoader()
+		sl.Draft = testCase.draft
+		schema, err := sl.Compile(NewStringLoader(testCase.schema))
+		if !assert.Nil(t, err, "%s %s", testCase.draft, testCase.schema) {
+			continue
+		}
+		result, err := schema.Validate(NewStringLoader(`[2]`))
+		assert.Nil(t, err)
+		assert.Equal(t, testCase.valid, result.Valid(), "%s %s", testCase.draft, testCase.schema)
+	}
+
+	invalid := []struct {
+		draft  Draft
+		schema string
+	}{
+		{Draft4, `true`},
+		{Draft4, `{"items": false}`},
+		{Draft4, `{"minimum": 1, "exclusiveMinimum": 1}`},
+		{Draft6, `{"minimum": 1, "exclusiveMinimum": true}`},
+		{Draft7, `{"maximum": 1, "exclusiveMaximum": false}`},
+	}
+
+	for _, testCase := range invalid {
+		sl := NewSchemaLoader()
+		sl.Draft = testCase.draft
+		_, err := sl.Compile(NewStringLoader(testCase.schema))
+		assert.NotNil(t, err, "%s %s", testCase.draft, testCase.schema)
+	}
+}
+
+func TestAddSchema(t *testing.T) {
+	sl := NewSchemaLoader()
+	err := sl.AddSchema("http://example.com/name.json", NewStringLoader(`{"type": "string", "minLength": 2}`))
+	assert.Nil(t, err)
+	err = sl.AddSchema("", NewStringLoader(`{"$id": "http://example.com/age.json", "type": "integer"}`))
+	assert.Nil(t, err)
+	err = sl.AddSchema("", NewReferenceLoader("file://"+filepath.ToSlash(mustAbs(t, "testdata/remotes/integer.json"))))
+	assert.Nil(t, err)
+
+	assert.NotNil(t, sl.AddSchema("relative.json", NewStringLoader(`{}`)))
+	assert.NotNil(t, sl.AddSchema("", NewStringLoader(`{`)))
+
+	schema, err := sl.Compile(NewStringLoader(`{
+		"properties": {
+			"name": {"$ref": "http://example.com/name.json"},
+			"age": {"$ref": "http://example.com/age.json#"},
+			"count": {"$ref": "file://` + filepath.ToSlash(mustAbs(t, "testdata/remotes/integer.json")) + `"}
+		}
+	}`))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	result, err := schema.Validate(NewStringLoader(`{"name": "jo", "age": 3, "count": 1}`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid(), "%v", result.Errors())
+
+	result, err = schema.Validate(NewStringLoader(`{"name": "j", "age": "3", "count": 1.5}`))
+	assert.Nil(t, err)
+	assert.Len(t, result.Errors(), 3)
+}
+
+func mustAbs(t *testing.T, path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return abs
+}
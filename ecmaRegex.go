@@ -0,0 +1,1033 @@
+package gojsonschema
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ECMA 262 regular expressions
+// JSON Schema requires the regular expression dialect of ECMA 262
+// ( https://tc39.es/ecma262/#sec-regexp-regular-expression-objects ), which has lookarounds
+// and backreferences that RE2 does not support.
+// Expressions follow the syntax of the unicode ("u") flag, without the legacy Annex B extensions,
+// and are matched against code points by a backtracking matcher.
+
+// DefaultRegexStepLimit is the number of backtracking steps a match may take when
+// ECMARegexEngine.StepLimit is not set
+const DefaultRegexStepLimit = 1000000
+
+// ecmaMaxRepeatDepth bounds the iterations of repeated groups in progress during a match, each one
+// holding stack frames until the match ends
+const ecmaMaxRepeatDepth = 10000
+
+// ECMARegexEngine compiles ECMA 262 regular expressions.
+// Backtracking can be exponential for some expressions, a match is aborted after StepLimit steps,
+// a step being a return to a choice point to try its next option. Matching characters and
+// moving to the next start position are free, so linear matches are never aborted. A match is
+// also aborted when more than 10000 iterations of repeated groups are nested, the repetitions of
+// single characters like [a-z]* being matched without nesting. The pattern
+// and patternProperties keywords report an aborted match as a validation error, MatchString as
+// not matching.
+type ECMARegexEngine struct {
+	StepLimit int
+}
+
+func (e ECMARegexEngine) Compile(pattern string) (RegexMatcher, error) {
+
+	p := &ecmaParser{source: pattern, pattern: []rune(pattern), names: map[string]int{}}
+
+	node, anchored, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	limit := e.StepLimit
+	if limit <= 0 {
+		limit = DefaultRegexStepLimit
+	}
+
+	return &ecmaRegex{source: pattern, node: node, groups: p.groups, anchored: anchored, limit: limit}, nil
+}
+
+type ecmaRegex struct {
+	source   string
+	node     ecmaNode
+	groups   int
+	anchored bool
+	limit    int
+}
+
+func (r *ecmaRegex) String() string {
+	return r.source
+}
+
+func (r *ecmaRegex) MatchString(s string) bool {
+	matched, _ := r.matchStringBounded(s)
+	return matched
+}
+
+func (r *ecmaRegex) matchStringBounded(s string) (matched bool, aborted bool) {
+
+	m := &ecmaMachine{input: []rune(s), caps: make([]int, 2*(r.groups+1)), limit: r.limit}
+	accept := func(int) bool { return !m.exceeded() }
+
+	for start := 0; start <= len(m.input); start++ {
+		for i := range m.caps {
+			m.caps[i] = -1
+		}
+		if r.node(m, start, accept) {
+			return true, false
+		}
+		if m.exceeded() || r.anchored {
+			break
+		}
+	}
+
+	return false, m.exceeded()
+}
+
+// ecmaMachine holds the state of a match, captures are the start and end of each group, -1 when unset
+type ecmaMachine struct {
+	input []rune
+	caps  []int
+	steps int
+	limit int
+	// iterations of repeated groups in progress, tooDeep once past ecmaMaxRepeatDepth
+	depth   int
+	tooDeep bool
+}
+
+// backtrack counts a return to a choice point, it reports whether the next option may be tried
+func (m *ecmaMachine) backtrack() bool {
+	m.steps++
+	return !m.exceeded()
+}
+
+func (m *ecmaMachine) exceeded() bool {
+	return m.steps > m.limit || m.tooDeep
+}
+
+// ecmaNode matches at pos and calls the continuation k with the end of each possible match,
+// in order of preference, until k accepts one
+type ecmaNode func(m *ecmaMachine, pos int, k func(int) bool) bool
+
+func ecmaEmpty(m *ecmaMachine, pos int, k func(int) bool) bool {
+	return k(pos)
+}
+
+func ecmaChar(match func(rune) bool) ecmaNode {
+	return func(m *ecmaMachine, pos int, k func(int) bool) bool {
+		if pos < len(m.input) && match(m.input[pos]) {
+			return k(pos + 1)
+		}
+		return false
+	}
+}
+
+func ecmaSequence(nodes []ecmaNode) ecmaNode {
+	switch len(nodes) {
+	case 0:
+		return ecmaEmpty
+	case 1:
+		return nodes[0]
+	}
+	first, rest := nodes[0], ecmaSequence(nodes[1:])
+	return func(m *ecmaMachine, pos int, k func(int) bool) bool {
+		return first(m, pos, func(end int) bool {
+			return rest(m, end, k)
+		})
+	}
+}
+
+func ecmaAlternation(nodes []ecmaNode) ecmaNode {
+	return func(m *ecmaMachine, pos int, k func(int) bool) bool {
+		for i, node := range nodes {
+			if i > 0 && !m.backtrack() {
+				return false
+			}
+			if node(m, pos, k) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func ecmaAssertion(holds func(input []rune, pos int) bool) ecmaNode {
+	return func(m *ecmaMachine, pos int, k func(int) bool) bool {
+		if holds(m.input, pos) {
+			return k(pos)
+		}
+		return false
+	}
+}
+
+func ecmaCapture(group int, inner ecmaNode) ecmaNode {
+	return func(m *ecmaMachine, pos int, k func(int) bool) bool {
+		return inner(m, pos, func(end int) bool {
+			start, previousEnd := m.caps[2*group], m.caps[2*group+1]
+			m.caps[2*group], m.caps[2*group+1] = pos, end
+			if k(end) {
+				return true
+			}
+			m.caps[2*group], m.caps[2*group+1] = start, previousEnd
+			return false
+		})
+	}
+}
+
+// ecmaBackreference matches the text of a group, group is a pointer as named groups are resolved after parsing
+func ecmaBackreference(group *int) ecmaNode {
+	return func(m *ecmaMachine, pos int, k func(int) bool) bool {
+		start, end := m.caps[2*(*group)], m.caps[2*(*group)+1]
+		if start < 0 || end < 0 {
+			// a group that did not participate matches the empty string
+			return k(pos)
+		}
+		if pos+end-start > len(m.input) {
+			return false
+		}
+		for i := 0; i < end-start; i++ {
+			if m.input[start+i] != m.input[pos+i] {
+				return false
+			}
+		}
+		return k(pos + end - start)
+	}
+}
+
+// ecmaLookaround matches inner without consuming input, lookarounds are atomic
+func ecmaLookaround(inner ecmaNode, behind bool, negate bool) ecmaNode {
+	return func(m *ecmaMachine, pos int, k func(int) bool) bool {
+		saved := append([]int(nil), m.caps...)
+
+		matched := false
+		if behind {
+			for start := pos; start >= 0 && !matched && (start == pos || m.backtrack()); start-- {
+				matched = inner(m, start, func(end int) bool { return end == pos && !m.exceeded() })
+			}
+		} else {
+			matched = inner(m, pos, func(int) bool { return !m.exceeded() })
+		}
+
+		if negate {
+			copy(m.caps, saved)
+			if matched || m.exceeded() {
+				return false
+			}
+			return k(pos)
+		}
+
+		if !matched {
+			return false
+		}
+		if k(pos) {
+			return true
+		}
+		copy(m.caps, saved)
+		return false
+	}
+}
+
+// ecmaRepeat matches inner between min and max times ( max -1 is unbounded ),
+// the captures of groups firstGroup to lastGroup are reset before each iteration
+func ecmaRepeat(inner ecmaNode, min int, max int, greedy bool, firstGroup int, lastGroup int) ecmaNode {
+
+	var repeat func(m *ecmaMachine, count int, pos int, k func(int) bool) bool
+	repeat = func(m *ecmaMachine, count int, pos int, k func(int) bool) bool {
+		if max >= 0 && count >= max {
+			return k(pos)
+		}
+
+		iterate := func() bool {
+			var saved []int
+			if lastGroup > firstGroup {
+				saved = append(saved, m.caps[2*firstGroup:2*lastGroup]...)
+				for i := 2 * firstGroup; i < 2*lastGroup; i++ {
+					m.caps[i] = -1
+				}
+			}
+			if m.depth >= ecmaMaxRepeatDepth {
+				m.tooDeep = true
+				return false
+			}
+			m.depth++
+			matched := inner(m, pos, func(end int) bool {
+				// an optional iteration must consume input
+				if end == pos && count >= min {
+					return false
+				}
+				return repeat(m, count+1, end, k)
+			})
+			m.depth--
+			if !matched && saved != nil {
+				copy(m.caps[2*firstGroup:], saved)
+			}
+			return matched
+		}
+
+		if count < min {
+			return iterate()
+		}
+		if greedy {
+			return iterate() || (m.backtrack() && k(pos))
+		}
+		return k(pos) || (m.backtrack() && iterate())
+	}
+
+	return func(m *ecmaMachine, pos int, k func(int) bool) bool {
+		return repeat(m, 0, pos, k)
+	}
+}
+
+// ecmaCharRepeat matches a single character between min and max times ( max -1 is unbounded ),
+// without nesting a call per character like ecmaRepeat
+func ecmaCharRepeat(match func(rune) bool, min int, max int, greedy bool) ecmaNode {
+	return func(m *ecmaMachine, pos int, k func(int) bool) bool {
+		n := 0
+		for pos+n < len(m.input) && (max < 0 || n < max) && match(m.input[pos+n]) {
+			n++
+		}
+		if n < min {
+			return false
+		}
+
+		if greedy {
+			for end := pos + n; end >= pos+min; end-- {
+				if end < pos+n && !m.backtrack() {
+					return false
+				}
+				if k(end) {
+					return true
+				}
+			}
+			return false
+		}
+		for end := pos + min; end <= pos+n; end++ {
+			if end > pos+min && !m.backtrack() {
+				return false
+			}
+			if k(end) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Parser
+
+type ecmaParser struct {
+	source  string
+	pattern []rune
+	pos     int
+	// number of capturing groups opened so far
+	groups int
+	names  map[string]int
+	// backreferences are checked once all the groups are known
+	backreferences      []*int
+	namedBackreferences map[*int]string
+	// matcher of the last atom when it is a single character, repeated by ecmaCharRepeat
+	charMatch func(rune) bool
+}
+
+// char returns the node of a single character atom
+func (p *ecmaParser) char(match func(rune) bool) ecmaNode {
+	p.charMatch = match
+	return ecmaChar(match)
+}
+
+func (p *ecmaParser) error(reason string) error {
+	return errors.New(formatErrorDescription(
+		Locale.RegexSyntaxError(),
+		ErrorDetails{"pattern": p.source, "position": p.pos, "reason": reason},
+	))
+}
+
+func (p *ecmaParser) end() bool {
+	return p.pos >= len(p.pattern)
+}
+
+func (p *ecmaParser) peek() rune {
+	if p.end() {
+		return -1
+	}
+	return p.pattern[p.pos]
+}
+
+func (p *ecmaParser) lookingAt(s string) bool {
+	return strings.HasPrefix(string(p.pattern[p.pos:]), s)
+}
+
+func (p *ecmaParser) parse() (ecmaNode, bool, error) {
+
+	node, anchored, err := p.parseDisjunction()
+	if err != nil {
+		return nil, false, err
+	}
+	if !p.end() {
+		return nil, false, p.error("unmatched )")
+	}
+
+	for _, group := range p.backreferences {
+		if name, ok := p.namedBackreferences[group]; ok {
+			index, exists := p.names[name]
+			if !exists {
+				return nil, false, p.error("reference to undefined group " + name)
+			}
+			*group = index
+		} else if *group > p.groups {
+			return nil, false, p.error("reference to undefined group " + strconv.Itoa(*group))
+		}
+	}
+
+	return node, anchored, nil
+}
+
+// parseDisjunction also reports whether all the alternatives start with ^
+func (p *ecmaParser) parseDisjunction() (ecmaNode, bool, error) {
+
+	var alternatives []ecmaNode
+	anchored := true
+
+	for {
+		alternative, startsWithBegin, err := p.parseAlternative()
+		if err != nil {
+			return nil, false, err
+		}
+		alternatives = append(alternatives, alternative)
+		anchored = anchored && startsWithBegin
+
+		if p.peek() != '|' {
+			break
+		}
+		p.pos++
+	}
+
+	if len(alternatives) == 1 {
+		return alternatives[0], anchored, nil
+	}
+	return ecmaAlternation(alternatives), anchored, nil
+}
+
+func (p *ecmaParser) parseAlternative() (ecmaNode, bool, error) {
+
+	var terms []ecmaNode
+	startsWithBegin := p.peek() == '^'
+
+	for !p.end() && p.peek() != '|' && p.peek() != ')' {
+		term, err := p.parseTerm()
+		if err != nil {
+			return nil, false, err
+		}
+		terms = append(terms, term)
+	}
+
+	return ecmaSequence(terms), startsWithBegin, nil
+}
+
+func (p *ecmaParser) parseTerm() (ecmaNode, error) {
+
+	var assertion ecmaNode
+
+	switch {
+	case p.peek() == '^':
+		p.pos++
+		assertion = ecmaAssertion(func(input []rune, pos int) bool { return pos == 0 })
+	case p.peek() == '$':
+		p.pos++
+		assertion = ecmaAssertion(func(input []rune, pos int) bool { return pos == len(input) })
+	case p.lookingAt(`\b`):
+		p.pos += 2
+		assertion = ecmaAssertion(isECMAWordBoundary)
+	case p.lookingAt(`\B`):
+		p.pos += 2
+		assertion = ecmaAssertion(func(input []rune, pos int) bool { return !isECMAWordBoundary(input, pos) })
+	case p.lookingAt("(?="), p.lookingAt("(?!"), p.lookingAt("(?<="), p.lookingAt("(?<!"):
+		behind := p.lookingAt("(?<")
+		negate := p.lookingAt("(?!") || p.lookingAt("(?<!")
+		if behind {
+			p.pos += 4
+		} else {
+			p.pos += 3
+		}
+		inner, _, err := p.parseDisjunction()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, p.error("unterminated group")
+		}
+		p.pos++
+		assertion = ecmaLookaround(inner, behind, negate)
+	}
+
+	if assertion != nil {
+		if p.isQuantifier() {
+			return nil, p.error("nothing to repeat")
+		}
+		return assertion, nil
+	}
+
+	firstGroup := p.groups + 1
+	p.charMatch = nil
+	atom, err := p.parseAtom()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.parseQuantifier(atom, p.charMatch, firstGroup)
+}
+
+func (p *ecmaParser) isQuantifier() bool {
+	switch p.peek() {
+	case '*', '+', '?', '{':
+		return true
+	}
+	return false
+}
+
+// parseQuantifier repeats an atom, charMatch being its matcher when it is a single character
+func (p *ecmaParser) parseQuantifier(atom ecmaNode, charMatch func(rune) bool, firstGroup int) (ecmaNode, error) {
+
+	min, max := 0, -1
+
+	switch p.peek() {
+	case '*':
+		p.pos++
+	case '+':
+		p.pos++
+		min = 1
+	case '?':
+		p.pos++
+		max = 1
+	case '{':
+		p.pos++
+		var ok bool
+		if min, ok = p.parseDecimal(); !ok {
+			return nil, p.error("incomplete quantifier")
+		}
+		max = min
+		if p.peek() == ',' {
+			p.pos++
+			max = -1
+			if p.peek() != '}' {
+				if max, ok = p.parseDecimal(); !ok {
+					return nil, p.error("incomplete quantifier")
+				}
+			}
+		}
+		if p.peek() != '}' {
+			return nil, p.error("incomplete quantifier")
+		}
+		p.pos++
+		if max >= 0 && min > max {
+			return nil, p.error("numbers out of order in quantifier")
+		}
+	default:
+		return atom, nil
+	}
+
+	greedy := true
+	if p.peek() == '?' {
+		p.pos++
+		greedy = false
+	}
+
+	if charMatch != nil {
+		return ecmaCharRepeat(charMatch, min, max, greedy), nil
+	}
+	return ecmaRepeat(atom, min, max, greedy, firstGroup, p.groups+1), nil
+}
+
+// parseDecimal reads a decimal number, large values are clamped as they cannot be matched anyway
+func (p *ecmaParser) parseDecimal() (int, bool) {
+	start := p.pos
+	n := 0
+	for !p.end() && p.peek() >= '0' && p.peek() <= '9' {
+		if n < 1<<30 {
+			n = n*10 + int(p.peek()-'0')
+		}
+		p.pos++
+	}
+	return n, p.pos > start
+}
+
+func (p *ecmaParser) parseAtom() (ecmaNode, error) {
+
+	c := p.peek()
+
+	switch c {
+
+	case '.':
+		p.pos++
+		return p.char(func(r rune) bool { return !isECMALineTerminator(r) }), nil
+
+	case '(':
+		p.pos++
+		group := 0
+		if p.lookingAt("?:") {
+			p.pos += 2
+		} else if p.lookingAt("?<") {
+			p.pos += 2
+			name, err := p.parseGroupName()
+			if err != nil {
+				return nil, err
+			}
+			if _, exists := p.names[name]; exists {
+				return nil, p.error("duplicate group name " + name)
+			}
+			p.groups++
+			group = p.groups
+			p.names[name] = group
+		} else if p.peek() == '?' {
+			return nil, p.error("invalid group")
+		} else {
+			p.groups++
+			group = p.groups
+		}
+		inner, _, err := p.parseDisjunction()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, p.error("unterminated group")
+		}
+		p.pos++
+		// the atom is the group, whatever its last term
+		p.charMatch = nil
+		if group == 0 {
+			return inner, nil
+		}
+		return ecmaCapture(group, inner), nil
+
+	case '[':
+		p.pos++
+		match, err := p.parseClass()
+		if err != nil {
+			return nil, err
+		}
+		return p.char(match), nil
+
+	case '\\':
+		p.pos++
+		return p.parseAtomEscape()
+
+	case '*', '+', '?', '{':
+		return nil, p.error("nothing to repeat")
+
+	case ')', ']', '}':
+		return nil, p.error("lone " + string(c))
+	}
+
+	p.pos++
+	return p.char(func(r rune) bool { return r == c }), nil
+}
+
+func (p *ecmaParser) parseGroupName() (string, error) {
+	start := p.pos
+	for !p.end() && p.peek() != '>' {
+		c := p.peek()
+		if !(c == '$' || c == '_' || unicode.IsLetter(c) || (p.pos > start && (unicode.IsDigit(c) || unicode.Is(unicode.Mn, c) || unicode.Is(unicode.Mc, c)))) {
+			return "", p.error("invalid group name")
+		}
+		p.pos++
+	}
+	if p.end() || p.pos == start {
+		return "", p.error("invalid group name")
+	}
+	name := string(p.pattern[start:p.pos])
+	p.pos++
+	return name, nil
+}
+
+func (p *ecmaParser) parseAtomEscape() (ecmaNode, error) {
+
+	if p.end() {
+		return nil, p.error(`\ at end of pattern`)
+	}
+
+	c := p.peek()
+
+	switch {
+
+	case c >= '1' && c <= '9':
+		group, _ := p.parseDecimal()
+		p.backreferences = append(p.backreferences, &group)
+		return ecmaBackreference(&group), nil
+
+	case c == 'k':
+		p.pos++
+		if p.peek() != '<' {
+			return nil, p.error("invalid named reference")
+		}
+		p.pos++
+		name, err := p.parseGroupName()
+		if err != nil {
+			return nil, err
+		}
+		group := 0
+		if p.namedBackreferences == nil {
+			p.namedBackreferences = map[*int]string{}
+		}
+		p.namedBackreferences[&group] = name
+		p.backreferences = append(p.backreferences, &group)
+		return ecmaBackreference(&group), nil
+	}
+
+	if match, ok, err := p.parseClassEscape(); ok || err != nil {
+		if err != nil {
+			return nil, err
+		}
+		return p.char(match), nil
+	}
+
+	r, err := p.parseCharacterEscape(false)
+	if err != nil {
+		return nil, err
+	}
+	return p.char(func(c rune) bool { return c == r }), nil
+}
+
+// parseClassEscape parses \d, \D, \s, \S, \w, \W, \p{...} and \P{...}, ok is false for other escapes
+func (p *ecmaParser) parseClassEscape() (match func(rune) bool, ok bool, err error) {
+
+	c := p.peek()
+
+	switch c {
+	case 'd', 'D':
+		match = func(r rune) bool { return r >= '0' && r <= '9' }
+	case 's', 'S':
+		match = isECMAWhiteSpace
+	case 'w', 'W':
+		match = isECMAWordChar
+	case 'p', 'P':
+		p.pos++
+		if p.peek() != '{' {
+			return nil, true, p.error("invalid property name")
+		}
+		start := p.pos + 1
+		for !p.end() && p.peek() != '}' {
+			p.pos++
+		}
+		if p.end() {
+			return nil, true, p.error("invalid property name")
+		}
+		property := string(p.pattern[start:p.pos])
+		match, ok = ecmaUnicodeProperty(property)
+		if !ok {
+			return nil, true, p.error("invalid property name " + property)
+		}
+	default:
+		return nil, false, nil
+	}
+	p.pos++
+
+	if unicode.IsUpper(c) {
+		positive := match
+		match = func(r rune) bool { return !positive(r) }
+	}
+
+	return match, true, nil
+}
+
+func (p *ecmaParser) parseCharacterEscape(inClass bool) (rune, error) {
+
+	c := p.peek()
+	p.pos++
+
+	switch c {
+	case 'f':
+		return '\f', nil
+	case 'n':
+		return '\n', nil
+	case 'r':
+		return '\r', nil
+	case 't':
+		return '\t', nil
+	case 'v':
+		return '\v', nil
+
+	case 'c':
+		letter := p.peek()
+		if !(letter >= 'a' && letter <= 'z' || letter >= 'A' && letter <= 'Z') {
+			return 0, p.error("invalid unicode escape")
+		}
+		p.pos++
+		return letter % 32, nil
+
+	case '0':
+		if p.peek() >= '0' && p.peek() <= '9' {
+			return 0, p.error("invalid decimal escape")
+		}
+		return 0, nil
+
+	case 'x':
+		r, ok := p.parseHex(2)
+		if !ok {
+			return 0, p.error("invalid escape")
+		}
+		return r, nil
+
+	case 'u':
+		if p.peek() == '{' {
+			p.pos++
+			start := p.pos
+			r := rune(0)
+			for !p.end() && p.peek() != '}' {
+				d, ok := hexDigitValue(p.peek())
+				if !ok {
+					return 0, p.error("invalid unicode escape")
+				}
+				r = r*16 + d
+				if r > unicode.MaxRune {
+					return 0, p.error("invalid unicode escape")
+				}
+				p.pos++
+			}
+			if p.end() || p.pos == start {
+				return 0, p.error("invalid unicode escape")
+			}
+			p.pos++
+			return r, nil
+		}
+		r, ok := p.parseHex(4)
+		if !ok {
+			return 0, p.error("invalid unicode escape")
+		}
+		// surrogate pairs are combined into a code point
+		if r >= 0xD800 && r <= 0xDBFF && p.lookingAt(`\u`) {
+			start := p.pos
+			p.pos += 2
+			low, ok := p.parseHex(4)
+			if ok && low >= 0xDC00 && low <= 0xDFFF {
+				return (r-0xD800)<<10 + (low - 0xDC00) + 0x10000, nil
+			}
+			p.pos = start
+		}
+		return r, nil
+
+	case '^', '$', '\\', '.', '*', '+', '?', '(', ')', '[', ']', '{', '}', '|', '/':
+		return c, nil
+
+	case '-':
+		if inClass {
+			return c, nil
+		}
+	}
+
+	p.pos--
+	return 0, p.error("invalid escape")
+}
+
+func (p *ecmaParser) parseHex(digits int) (rune, bool) {
+	if p.pos+digits > len(p.pattern) {
+		return 0, false
+	}
+	r := rune(0)
+	for i := 0; i < digits; i++ {
+		d, ok := hexDigitValue(p.pattern[p.pos+i])
+		if !ok {
+			return 0, false
+		}
+		r = r*16 + d
+	}
+	p.pos += digits
+	return r, true
+}
+
+func hexDigitValue(c rune) (rune, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// parseClass parses a character class, after its opening bracket
+func (p *ecmaParser) parseClass() (func(rune) bool, error) {
+
+	negate := false
+	if p.peek() == '^' {
+		negate = true
+		p.pos++
+	}
+
+	var ranges [][2]rune
+	var escapes []func(rune) bool
+
+	for {
+		if p.end() {
+			return nil, p.error("unterminated character class")
+		}
+		if p.peek() == ']' {
+			p.pos++
+			break
+		}
+
+		from, fromEscape, err := p.parseClassAtom()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.peek() == '-' && p.pos+1 < len(p.pattern) && p.pattern[p.pos+1] != ']' {
+			p.pos++
+			to, toEscape, err := p.parseClassAtom()
+			if err != nil {
+				return nil, err
+			}
+			if fromEscape != nil || toEscape != nil {
+				return nil, p.error("invalid character class")
+			}
+			if from > to {
+				return nil, p.error("range out of order in character class")
+			}
+			ranges = append(ranges, [2]rune{from, to})
+			continue
+		}
+
+		if fromEscape != nil {
+			escapes = append(escapes, fromEscape)
+		} else {
+			ranges = append(ranges, [2]rune{from, from})
+		}
+	}
+
+	return func(r rune) bool {
+		for _, rg := range ranges {
+			if r >= rg[0] && r <= rg[1] {
+				return !negate
+			}
+		}
+		for _, escape := range escapes {
+			if escape(r) {
+				return !negate
+			}
+		}
+		return negate
+	}, nil
+}
+
+// parseClassAtom returns either a character or the predicate of a class escape
+func (p *ecmaParser) parseClassAtom() (rune, func(rune) bool, error) {
+
+	c := p.peek()
+	p.pos++
+
+	if c != '\\' {
+		return c, nil, nil
+	}
+
+	if p.end() {
+		return 0, nil, p.error(`\ at end of pattern`)
+	}
+
+	if p.peek() == 'b' {
+		p.pos++
+		return '\b', nil, nil
+	}
+
+	if match, ok, err := p.parseClassEscape(); ok || err != nil {
+		return 0, match, err
+	}
+
+	r, err := p.parseCharacterEscape(true)
+	return r, nil, err
+}
+
+// Character sets
+
+func isECMALineTerminator(r rune) bool {
+	return r == '\n' || r == '\r' || r == '\u2028' || r == '\u2029'
+}
+
+func isECMAWhiteSpace(r rune) bool {
+	switch r {
+	case '\t', '\n', '\v', '\f', '\r', ' ', '\u00a0', '\u1680', '\u2028', '\u2029', '\u202f', '\u205f', '\u3000', '\ufeff':
+		return true
+	}
+	return r >= '\u2000' && r <= '\u200a'
+}
+
+func isECMAWordChar(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_'
+}
+
+func isECMAWordBoundary(input []rune, pos int) bool {
+	before := pos > 0 && isECMAWordChar(input[pos-1])
+	after := pos < len(input) && isECMAWordChar(input[pos])
+	return before != after
+}
+
+// Long names of the general categories
+var ecmaGeneralCategories = map[string]string{
+	"Other": "C", "Control": "Cc", "cntrl": "Cc", "Format": "Cf", "Unassigned": "Cn", "Private_Use": "Co", "Surrogate": "Cs",
+	"Letter": "L", "Cased_Letter": "LC", "Lowercase_Letter": "Ll", "Modifier_Letter": "Lm", "Other_Letter": "Lo", "Titlecase_Letter": "Lt", "Uppercase_Letter": "Lu",
+	"Mark": "M", "Combining_Mark": "M", "Spacing_Mark": "Mc", "Enclosing_Mark": "Me", "Nonspacing_Mark": "Mn",
+	"Number": "N", "Decimal_Number": "Nd", "digit": "Nd", "Letter_Number": "Nl", "Other_Number": "No",
+	"Punctuation": "P", "punct": "P", "Connector_Punctuation": "Pc", "Dash_Punctuation": "Pd", "Close_Punctuation": "Pe",
+	"Final_Punctuation": "Pf", "Initial_Punctuation": "Pi", "Other_Punctuation": "Po", "Open_Punctuation": "Ps",
+	"Symbol": "S", "Currency_Symbol": "Sc", "Modifier_Symbol": "Sk", "Math_Symbol": "Sm", "Other_Symbol": "So",
+	"Separator": "Z", "Line_Separator": "Zl", "Paragraph_Separator": "Zp", "Space_Separator": "Zs",
+}
+
+func isAssignedRune(r rune) bool {
+	return unicode.In(r, unicode.C, unicode.L, unicode.M, unicode.N, unicode.P, unicode.S, unicode.Z)
+}
+
+func ecmaGeneralCategory(name string) (func(rune) bool, bool) {
+	if short, ok := ecmaGeneralCategories[name]; ok {
+		name = short
+	}
+	switch name {
+	case "Cn":
+		return func(r rune) bool { return !isAssignedRune(r) }, true
+	case "LC":
+		return func(r rune) bool { return unicode.In(r, unicode.Lu, unicode.Ll, unicode.Lt) }, true
+	}
+	if table, ok := unicode.Categories[name]; ok {
+		return func(r rune) bool { return unicode.Is(table, r) }, true
+	}
+	return nil, false
+}
+
+// ecmaUnicodeProperty returns the predicate of \p{property}
+func ecmaUnicodeProperty(property string) (func(rune) bool, bool) {
+
+	if i := strings.Index(property, "="); i >= 0 {
+		name, value := property[:i], property[i+1:]
+		switch name {
+		case "General_Category", "gc":
+			return ecmaGeneralCategory(value)
+		case "Script", "sc", "Script_Extensions", "scx":
+			if table, ok := unicode.Scripts[value]; ok {
+				return func(r rune) bool { return unicode.Is(table, r) }, true
+			}
+		}
+		return nil, false
+	}
+
+	if match, ok := ecmaGeneralCategory(property); ok {
+		return match, true
+	}
+
+	switch property {
+	case "Any":
+		return func(r rune) bool { return true }, true
+	case "ASCII":
+		return func(r rune) bool { return r <= unicode.MaxASCII }, true
+	case "Assigned":
+		return isAssignedRune, true
+	}
+	if table, ok := unicode.Properties[property]; ok {
+		return func(r rune) bool { return unicode.Is(table, r) }, true
+	}
+
+	return nil, false
+}
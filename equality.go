@@ -0,0 +1,259 @@
+package gojsonschema
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+// JSON equality
+// enum, const and uniqueItems compare values as JSON: numbers by their value ( 1 equals 1.0 ),
+// objects regardless of the order of their keys.
+// Values are normalized once, numbers becoming *big.Float, and hashed so a value is only
+// compared to the values of the same hash.
+
+// Precision of the normalized numbers, enough for the integers and decimals found in practice
+const jsonNumberPrecision = 512
+
+// jsonValue is a JSON value prepared for comparisons
+type jsonValue struct {
+	normalized interface{}
+	hash       uint64
+	// position of the value in the array it comes from
+	index int
+	// JSON text, for the values displayed in errors
+	text string
+}
+
+func newJsonValue(value interface{}) (*jsonValue, error) {
+	normalized, err := normalizeJsonValue(value)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonValue{normalized: normalized, hash: hashJsonValue(normalized)}, nil
+}
+
+// equals reports whether two values are equal as JSON
+func (v *jsonValue) equals(other *jsonValue) bool {
+	return v.hash == other.hash && jsonEqual(v.normalized, other.normalized)
+}
+
+// jsonValueSet is a set of JSON values, indexed by hash
+type jsonValueSet struct {
+	values  []*jsonValue
+	buckets map[uint64][]*jsonValue
+}
+
+// find returns the value of the set equal to v, if any
+func (s *jsonValueSet) find(v *jsonValue) *jsonValue {
+	for _, candidate := range s.buckets[v.hash] {
+		if jsonEqual(candidate.normalized, v.normalized) {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// add adds v to the set, or returns the value it is a duplicate of
+func (s *jsonValueSet) add(v *jsonValue) *jsonValue {
+	if existing := s.find(v); existing != nil {
+		return existing
+	}
+	if s.buckets == nil {
+		s.buckets = make(map[uint64][]*jsonValue)
+	}
+	s.values = append(s.values, v)
+	s.buckets[v.hash] = append(s.buckets[v.hash], v)
+	return nil
+}
+
+func (s *jsonValueSet) len() int {
+	return len(s.values)
+}
+
+// String lists the values of the set, as displayed in errors
+func (s *jsonValueSet) String() string {
+	texts := make([]string, len(s.values))
+	for i, v := range s.values {
+		texts[i] = v.text
+	}
+	return strings.Join(texts, ", ")
+}
+
+// jsonValueText returns the JSON text of a value, without the representation differences of numbers when possible
+func jsonValueText(value interface{}) (string, error) {
+	text, err := marshalWithoutNumber(value)
+	if err != nil {
+		// numbers that do not fit a float64 are displayed as given
+		text, err = marshalToJsonString(value)
+		if err != nil {
+			return "", err
+		}
+	}
+	return *text, nil
+}
+
+// normalizeJsonValue converts a decoded value to the JSON data model, with *big.Float numbers
+func normalizeJsonValue(value interface{}) (interface{}, error) {
+
+	if number, ok := toJsonNumber(value); ok {
+		f, _, err := big.ParseFloat(string(number), 10, jsonNumberPrecision, big.ToNearestEven)
+		if err != nil {
+			return nil, err
+		}
+		if f.Sign() == 0 {
+			// -0 equals 0
+			f.SetInt64(0)
+		}
+		return f, nil
+	}
+
+	switch v := value.(type) {
+
+	case nil, bool, string:
+		return v, nil
+
+	case []interface{}:
+		normalized := make([]interface{}, len(v))
+		for i := range v {
+			item, err := normalizeJsonValue(v[i])
+			if err != nil {
+				return nil, err
+			}
+			normalized[i] = item
+		}
+		return normalized, nil
+
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for k := range v {
+			item, err := normalizeJsonValue(v[k])
+			if err != nil {
+				return nil, err
+			}
+			normalized[k] = item
+		}
+		return normalized, nil
+	}
+
+	if isKind(value, reflect.Slice, reflect.Map) {
+		if converted := convertDocumentNode(value); reflect.TypeOf(converted) != reflect.TypeOf(value) {
+			return normalizeJsonValue(converted)
+		}
+	}
+
+	// other values, BSON ones for instance, are compared by their JSON encoding
+	text, err := marshalToJsonString(value)
+	if err != nil {
+		return nil, err
+	}
+	document, err := decodeJsonUsingNumber(strings.NewReader(*text))
+	if err != nil {
+		return nil, err
+	}
+	return normalizeJsonValue(document)
+}
+
+// jsonEqual compares two normalized values
+func jsonEqual(a interface{}, b interface{}) bool {
+
+	switch x := a.(type) {
+
+	case nil:
+		return b == nil
+
+	case bool:
+		y, ok := b.(bool)
+		return ok && x == y
+
+	case string:
+		y, ok := b.(string)
+		return ok && x == y
+
+	case *big.Float:
+		y, ok := b.(*big.Float)
+		return ok && x.Cmp(y) == 0
+
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !jsonEqual(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, xv := range x {
+			yv, exists := y[k]
+			if !exists || !jsonEqual(xv, yv) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return false
+}
+
+// hashJsonValue hashes a normalized value, equal values have the same hash
+func hashJsonValue(value interface{}) uint64 {
+
+	h := fnv.New64a()
+
+	switch v := value.(type) {
+
+	case nil:
+		h.Write([]byte{'n'})
+
+	case bool:
+		if v {
+			h.Write([]byte{'t'})
+		} else {
+			h.Write([]byte{'f'})
+		}
+
+	case string:
+		h.Write([]byte{'s'})
+		h.Write([]byte(v))
+
+	case *big.Float:
+		h.Write([]byte{'d'})
+		h.Write([]byte(v.Text('p', 0)))
+
+	case []interface{}:
+		h.Write([]byte{'a'})
+		b := make([]byte, 8)
+		for _, item := range v {
+			binary.LittleEndian.PutUint64(b, hashJsonValue(item))
+			h.Write(b)
+		}
+
+	case map[string]interface{}:
+		// the hashes of the members are summed so the order of the keys does not matter
+		var sum uint64
+		for k, item := range v {
+			member := fnv.New64a()
+			member.Write([]byte(k))
+			b := make([]byte, 8)
+			binary.LittleEndian.PutUint64(b, hashJsonValue(item))
+			member.Write(b)
+			sum += member.Sum64()
+		}
+		h.Write([]byte{'o'})
+		b := make([]byte, 8)
+		binary.LittleEndian.PutUint64(b, sum)
+		h.Write(b)
+	}
+
+	return h.Sum64()
+}
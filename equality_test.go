@@ -0,0 +1,156 @@
+package gojsonschema
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJsonEquality(t *testing.T) {
+	testCases := []struct {
+		a, b  interface{}
+		equal bool
+	}{
+		{json.Number("1"), json.Number("1.0"), true},
+		{json.Number("1"), json.Number("1e0"), true},
+		{json.Number("-0"), json.Number("0"), true},
+		{json.Number("0.1"), json.Number("0.10"), true},
+		{json.Number("12345678901234567890"), json.Number("12345678901234567891"), false},
+		{json.Number("1"), int32(1), true},
+		{json.Number("1.5"), 1.5, true},
+		{json.Number("1"), "1", false},
+		{json.Number("0"), false, false},
+		{nil, false, false},
+		{nil, nil, true},
+		{
+			map[string]interface{}{"a": json.Number("1"), "b": []interface{}{"x"}},
+			map[string]interface{}{"b": []interface{}{"x"}, "a": json.Number("1.0")},
+			true,
+		},
+		{map[string]interface{}{"a": nil}, map[string]interface{}{}, false},
+		{[]interface{}{json.Number("1"), "a"}, []interface{}{"a", json.Number("1")}, false},
+		{[]string{"a"}, []interface{}{"a"}, true},
+		{DateTime(0), map[string]interface{}{"$date": map[string]interface{}{"$numberLong": "0"}}, true},
+	}
+
+	for _, testCase := range testCases {
+		a, err := newJsonValue(testCase.a)
+		assert.Nil(t, err)
+		b, err := newJsonValue(testCase.b)
+		assert.Nil(t, err)
+		assert.Equal(t, testCase.equal, a.equals(b), "%v and %v", testCase.a, testCase.b)
+		if testCase.equal {
+			assert.Equal(t, a.hash, b.hash)
+		}
+	}
+}
+
+func TestJsonEqualityKeywords(t *testing.T) {
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {
+			"const": {"const": {"a": 1, "b": [1.0]}},
+			"enum": {"enum": [1, "1", null, {"x": 2}]},
+			"unique": {"uniqueItems": true}
+		}
+	}`))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	result, err := schema.Validate(NewStringLoader(`{
+		"const": {"b": [1], "a": 1.0},
+		"enum": {"x": 2.0},
+		"unique": [1, "1", 2, {"a": 1}, {"a": 2}]
+	}`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid(), "%v", result.Errors())
+
+	result, err = schema.Validate(NewStringLoader(`{
+		"const": {"a": 1},
+		"enum": 2,
+		"unique": [1, {"a": 1, "b": 2}, 1.0, {"b": 2, "a": 1}]
+	}`))
+	assert.Nil(t, err)
+
+	var types []string
+	var duplicates [][2]interface{}
+	for _, e := range result.Errors() {
+		types = append(types, e.Type())
+		if e.Type() == "unique" {
+			duplicates = append(duplicates, [2]interface{}{e.Details()["i"], e.Details()["j"]})
+		}
+	}
+	assert.ElementsMatch(t, []string{"const", "enum", "unique", "unique"}, types)
+	assert.Equal(t, [][2]interface{}{{0, 2}, {1, 3}}, duplicates)
+
+	_, err = NewSchema(NewStringLoader(`{"enum": [1, 1.0]}`))
+	assert.NotNil(t, err)
+}
+
+func benchmarkArray(n int, unique bool) []interface{} {
+	items := make([]interface{}, n)
+	for i := range items {
+		items[i] = map[string]interface{}{
+			"id":   json.Number(strconv.Itoa(i)),
+			"name": "item " + strconv.Itoa(i),
+		}
+	}
+	if !unique {
+		items[n-1] = items[0]
+	}
+	return items
+}
+
+func BenchmarkUniqueItems(b *testing.B) {
+	schema, err := NewSchema(NewStringLoader(`{"uniqueItems": true}`))
+	if err != nil {
+		b.Fatal(err)
+	}
+	items := benchmarkArray(100000, true)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		result, err := schema.Validate(NewGoLoader(items))
+		if err != nil || !result.Valid() {
+			b.Fatal("unexpected result")
+		}
+	}
+}
+
+func BenchmarkUniqueItemsDuplicate(b *testing.B) {
+	schema, err := NewSchema(NewStringLoader(`{"uniqueItems": true}`))
+	if err != nil {
+		b.Fatal(err)
+	}
+	items := benchmarkArray(100000, false)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		result, err := schema.Validate(NewGoLoader(items))
+		if err != nil || result.Valid() {
+			b.Fatal("unexpected result")
+		}
+	}
+}
+
+func BenchmarkEnum(b *testing.B) {
+	enum, err := json.Marshal(benchmarkArray(100000, true))
+	if err != nil {
+		b.Fatal(err)
+	}
+	schema, err := NewSchema(NewStringLoader(`{"items": {"enum": ` + string(enum) + `}}`))
+	if err != nil {
+		b.Fatal(err)
+	}
+	items := benchmarkArray(1000, true)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		result, err := schema.Validate(NewGoLoader(items))
+		if err != nil || !result.Valid() {
+			b.Fatal("unexpected result")
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package gojsonschema
+
+import (
+	"errors"
+)
+
+const KEY_ERROR_MESSAGE = "errorMessage"
+
+// Key of an errorMessage object whose message replaces the errors no other key matches
+const errorMessageOthers = "_"
+
+// Keywords of an errorMessage object whose message can be given per property
+var errorMessagePropertyKeywords = []string{KEY_REQUIRED, KEY_PROPERTIES}
+
+// The keywords raising each type of error, the first one present in the schema raising it
+var errorTypeKeywords = map[string][]string{
+	"required":                        {KEY_REQUIRED},
+	"invalid_type":                    {KEY_TYPE},
+	"invalid_bson_type":               {KEY_BSON_TYPE},
+	"number_any_of":                   {KEY_ANY_OF},
+	"number_one_of":                   {KEY_ONE_OF},
+	"number_all_of":                   {KEY_ALL_OF},
+	"number_not":                      {KEY_NOT},
+	"missing_dependency":              {KEY_DEPENDENCIES},
+	"const":                           {KEY_CONST},
+	"enum":                            {KEY_ENUM},
+	"array_no_additional_items":       {KEY_ADDITIONAL_ITEMS},
+	"array_min_items":                 {KEY_MIN_ITEMS},
+	"array_max_items":                 {KEY_MAX_ITEMS},
+	"unique":                          {KEY_UNIQUE_ITEMS},
+	"contains":                        {KEY_CONTAINS},
+	"array_min_properties":            {KEY_MIN_PROPERTIES},
+	"array_max_properties":            {KEY_MAX_PROPERTIES},
+	"additional_property_not_allowed": {KEY_ADDITIONAL_PROPERTIES},
+	"invalid_property_pattern":        {KEY_PATTERN_PROPERTIES},
+	"invalid_property_name":           {KEY_PROPERTY_NAMES},
+	"string_gte":                      {KEY_MIN_LENGTH},
+	"string_lte":                      {KEY_MAX_LENGTH},
+	"pattern":                         {KEY_PATTERN},
+	"format":                          {KEY_FORMAT},
+	"multiple_of":                     {KEY_MULTIPLE_OF},
+	"number_gte":                      {KEY_MINIMUM},
+	"number_gt":                       {KEY_EXCLUSIVE_MINIMUM, KEY_MINIMUM},
+	"number_lte":                      {KEY_MAXIMUM},
+	"number_lt":                       {KEY_EXCLUSIVE_MAXIMUM, KEY_MAXIMUM},
+	"condition_then":                  {KEY_THEN},
+	"condition_else":                  {KEY_ELSE},
+}
+
+// errorMessage holds the messages of the errorMessage keyword of a subschema
+type errorMessage struct {
+	// message replacing all the errors of the subschema, when errorMessage is a string
+	message string
+	// messages of the keywords and of _
+	keywords map[string]string
+	// messages of the properties, for required and properties
+	properties map[string]map[string]string
+	// keywords of the subschema
+	declared []string
+}
+
+// Parses the errorMessage keyword, a string or an object of messages by keyword
+func (d *Schema) parseErrorMessage(m map[string]interface{}, currentSchema *subSchema) error {
+
+	if !existsMapKey(m, KEY_ERROR_MESSAGE) {
+		return nil
+	}
+
+	invalid := errors.New(formatErrorDescription(
+		Locale.MustBeOfA(),
+		ErrorDetails{"x": KEY_ERROR_MESSAGE, "y": STRING_STRING_OR_OBJECT},
+	))
+
+	e := &errorMessage{keywords: map[string]string{}, properties: map[string]map[string]string{}}
+	for k := range m {
+		e.declared = append(e.declared, k)
+	}
+
+	switch value := m[KEY_ERROR_MESSAGE].(type) {
+	case string:
+		e.message = value
+	case map[string]interface{}:
+		for keyword, message := range value {
+			switch message := message.(type) {
+			case string:
+				e.keywords[keyword] = message
+			case map[string]interface{}:
+				if !isStringInSlice(errorMessagePropertyKeywords, keyword) {
+					return invalid
+				}
+				e.properties[keyword] = map[string]string{}
+				for property, propertyMessage := range message {
+					text, ok := propertyMessage.(string)
+					if !ok {
+						return invalid
+					}
+					e.properties[keyword][property] = text
+				}
+			default:
+				return invalid
+			}
+		}
+	default:
+		return invalid
+	}
+
+	currentSchema.errorMessage = e
+	return nil
+}
+
+// apply replaces the errors raised by validating a value against the subschema, context
+// being the location of the value, by the matching messages
+func (e *errorMessage) apply(raised []ResultError, context *JsonContext, value interface{}) []ResultError {
+
+	var errs []ResultError
+	groups := map[string]*ErrorMessageError{}
+
+	for _, err := range raised {
+		key, keyword, message, errContext, errValue := e.match(err, context, value)
+		if message == "" {
+			errs = append(errs, err)
+			continue
+		}
+		if group, ok := groups[key]; ok {
+			group.errors = append(group.errors, err)
+			continue
+		}
+		group := newErrorMessageError(message, keyword, err, errContext, errValue)
+		groups[key] = group
+		errs = append(errs, group)
+	}
+
+	return errs
+}
+
+// match returns the message replacing an error, the key grouping the errors it replaces
+// with their keyword, context and value
+func (e *errorMessage) match(err ResultError, context *JsonContext, value interface{}) (key string, keyword string, message string, errContext *JsonContext, errValue interface{}) {
+
+	if e.message != "" {
+		return "", "", e.message, context, value
+	}
+
+	own := err.Context() == context
+	if own {
+		for _, keyword := range errorTypeKeywords[err.Type()] {
+			if !isStringInSlice(e.declared, keyword) {
+				continue
+			}
+			if property, ok := err.Details()["property"].(string); ok && e.properties[keyword][property] != "" {
+				return keyword + "/" + property, keyword, e.properties[keyword][property], context, value
+			}
+			if e.keywords[keyword] != "" {
+				return keyword, keyword, e.keywords[keyword], context, value
+			}
+		}
+	} else {
+		// the errors of a property, whose context is a child of the one of the value
+		child := err.Context()
+		for child != nil && child.tail != context {
+			child = child.tail
+		}
+		if child != nil && e.properties[KEY_PROPERTIES][child.head] != "" {
+			var propertyValue interface{}
+			if object, ok := value.(map[string]interface{}); ok {
+				propertyValue = object[child.head]
+			}
+			return KEY_PROPERTIES + "/" + child.head, KEY_PROPERTIES, e.properties[KEY_PROPERTIES][child.head], child, propertyValue
+		}
+	}
+
+	if own && e.keywords[errorMessageOthers] != "" {
+		return errorMessageOthers, "", e.keywords[errorMessageOthers], context, value
+	}
+	return "", "", "", nil, nil
+}
+
+func newErrorMessageError(message string, keyword string, first ResultError, context *JsonContext, value interface{}) *ErrorMessageError {
+
+	// the details of the first error replaced, for the placeholders of the message.
+	// Messages replacing errors of several keywords do not have the property of the first one.
+	details := ErrorDetails{}
+	for k, v := range first.Details() {
+		if k != "property" || keyword != "" {
+			details[k] = v
+		}
+	}
+	details["keyword"] = keyword
+
+	err := &ErrorMessageError{errors: []ResultError{first}}
+	err.SetType("error_message")
+	err.SetContext(context)
+	err.SetValue(value)
+	err.SetDetails(details)
+	err.SetDescriptionFormat(message)
+	details["field"] = err.Field()
+	details["context"] = context.String()
+	err.SetDescription(formatErrorDescription(message, details))
+
+	return err
+}
+
+// Errors returns the errors replaced by the message, for logging
+func (e *ErrorMessageError) Errors() []ResultError {
+	return e.errors
+}
@@ -0,0 +1,112 @@
+package gojsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func errorDescriptions(t *testing.T, schema string, document string) ([]string, []ResultError) {
+	s, err := NewSchema(NewStringLoader(schema))
+	if !assert.Nil(t, err) {
+		return nil, nil
+	}
+	result, err := s.Validate(NewStringLoader(document))
+	if !assert.Nil(t, err) {
+		return nil, nil
+	}
+	var descriptions []string
+	for _, resultError := range result.Errors() {
+		descriptions = append(descriptions, resultError.Field()+": "+resultError.Description())
+	}
+	return descriptions, result.Errors()
+}
+
+func TestErrorMessage(t *testing.T) {
+	schema := `{
+		"type": "object",
+		"properties": {
+			"zip": {
+				"type": "string",
+				"pattern": "^[0-9]{5}$",
+				"errorMessage": "Please enter a valid postal code"
+			},
+			"age": {
+				"type": "integer",
+				"minimum": 18,
+				"maximum": 130,
+				"errorMessage": {"minimum": "{{.field}} must be at least {{.min}}"}
+			}
+		},
+		"required": ["zip", "name", "email"],
+		"errorMessage": {
+			"required": {"zip": "The postal code is required"},
+			"properties": {"name": "Invalid name"},
+			"_": "Invalid address"
+		}
+	}`
+
+	descriptions, errs := errorDescriptions(t, schema, `{"zip": "1234", "age": 12, "name": "Jane", "email": "jane@example.com"}`)
+	assert.ElementsMatch(t, []string{
+		"zip: Please enter a valid postal code",
+		"age: age must be at least 18",
+	}, descriptions)
+
+	// the raw errors stay available
+	for _, resultError := range errs {
+		assert.Equal(t, "error_message", resultError.Type())
+		raw := resultError.(*ErrorMessageError).Errors()
+		if assert.Len(t, raw, 1) && resultError.Field() == "zip" {
+			assert.Equal(t, "pattern", raw[0].Type())
+		} else {
+			assert.Equal(t, KEY_MINIMUM, resultError.Details()["keyword"])
+		}
+	}
+
+	// errors of keywords without a message are kept
+	descriptions, _ = errorDescriptions(t, schema, `{"zip": "12345", "age": 140, "name": "Jane", "email": "jane@example.com"}`)
+	assert.Equal(t, []string{"age: Must be less than or equal to 130"}, descriptions)
+
+	// the remaining errors of the schema, not of its properties
+	descriptions, _ = errorDescriptions(t, schema, `{"zip": 12345}`)
+	assert.ElementsMatch(t, []string{"(root): Invalid address", "zip: Please enter a valid postal code"}, descriptions)
+
+	// per property messages, the remaining errors being grouped
+	descriptions, errs = errorDescriptions(t, schema, `{"name": 1, "age": 20}`)
+	assert.Equal(t, []string{
+		"zip: The postal code is required",
+		"(root): Invalid address",
+	}, descriptions)
+	if assert.Len(t, errs, 2) {
+		assert.Len(t, errs[1].(*ErrorMessageError).Errors(), 1)
+	}
+
+	descriptions, _ = errorDescriptions(t, `{
+		"properties": {"name": {"type": "string", "minLength": 2}},
+		"errorMessage": {"properties": {"name": "Invalid name {{.field}}"}}
+	}`, `{"name": 1}`)
+	assert.Equal(t, []string{"name: Invalid name name"}, descriptions)
+
+	// all the errors of a subschema
+	descriptions, errs = errorDescriptions(t, `{
+		"items": {"type": "string"},
+		"minItems": 3,
+		"errorMessage": "Must be at least three strings"
+	}`, `[1, 2]`)
+	assert.Equal(t, []string{"(root): Must be at least three strings"}, descriptions)
+	if assert.Len(t, errs, 1) {
+		assert.Len(t, errs[0].(*ErrorMessageError).Errors(), 3)
+	}
+}
+
+func TestErrorMessageInvalid(t *testing.T) {
+	for _, schema := range []string{
+		`{"errorMessage": 1}`,
+		`{"errorMessage": {"minimum": 1}}`,
+		`{"errorMessage": {"minimum": {"a": "b"}}}`,
+		`{"errorMessage": {"required": {"a": 1}}}`,
+	} {
+		_, err := NewSchema(NewStringLoader(schema))
+		assert.EqualError(t, err, "errorMessage must be of a string or object of strings", schema)
+	}
+}
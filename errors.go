@@ -26,14 +26,20 @@ type (
 		ResultErrorFields
 	}
 
+	// InvalidBSONTypeError. ErrorDetails: expected, given
+	InvalidBSONTypeError struct {
+		ResultErrorFields
+	}
+
 	// NumberAnyOfError. ErrorDetails: -
 	NumberAnyOfError struct {
 		ResultErrorFields
 	}
 
-	// NumberOneOfError. ErrorDetails: -
+	// NumberOneOfError. ErrorDetails: matched, indices when several schemas validate, failures when none does
 	NumberOneOfError struct {
 		ResultErrorFields
+		titles []string // Titles of the schemas, empty for the ones without
 	}
 
 	// NumberAllOfError. ErrorDetails: -
@@ -81,7 +87,7 @@ type (
 		ResultErrorFields
 	}
 
-	// ItemsMustBeUniqueError. ErrorDetails: type
+	// ItemsMustBeUniqueError. ErrorDetails: type, i, j
 	ItemsMustBeUniqueError struct {
 		ResultErrorFields
 	}
@@ -131,6 +137,11 @@ type (
 		ResultErrorFields
 	}
 
+	// RegexStepLimitError. ErrorDetails: pattern
+	RegexStepLimitError struct {
+		ResultErrorFields
+	}
+
 	// DoesNotMatchFormatError. ErrorDetails: format
 	DoesNotMatchFormatError struct {
 		ResultErrorFields
@@ -161,6 +172,11 @@ type (
 		ResultErrorFields
 	}
 
+	// DiscriminatorError. ErrorDetails: property, allowed
+	DiscriminatorError struct {
+		ResultErrorFields
+	}
+
 	// ConditionThenError. ErrorDetails: -
 	ConditionThenError struct {
 		ResultErrorFields
@@ -170,12 +186,36 @@ type (
 	ConditionElseError struct {
 		ResultErrorFields
 	}
+
+	// ErrorMessageError replaces errors by a message of the errorMessage keyword.
+	// ErrorDetails: keyword, and the details of the first error replaced
+	ErrorMessageError struct {
+		ResultErrorFields
+		errors []ResultError
+	}
 )
 
 // newError takes a ResultError type and sets the type, context, description, details, value, and field
-func newError(err ResultError, context *JsonContext, value interface{}, locale locale, details ErrorDetails) {
-	var t string
-	var d string
+func newError(err ResultError, context *JsonContext, value interface{}, locale LocaleMessages, details ErrorDetails) {
+	t, d := errorTypeFormat(err, locale)
+
+	err.SetType(t)
+	err.SetContext(context)
+	err.SetValue(value)
+	err.SetDetails(details)
+	err.SetDescriptionFormat(d)
+	details["field"] = err.Field()
+
+	if _, exists := details["context"]; !exists && context != nil {
+		details["context"] = context.String()
+	}
+
+	err.SetDescription(formatErrorDescription(err.DescriptionFormat(), details))
+}
+
+// errorTypeFormat returns the type of an error of the validation and its description format in a locale,
+// empty strings for the other errors
+func errorTypeFormat(err ResultError, locale LocaleMessages) (t string, d string) {
 	switch err.(type) {
 	case *RequiredError:
 		t = "required"
@@ -183,6 +223,9 @@ func newError(err ResultError, context *JsonContext, value interface{}, locale l
 	case *InvalidTypeError:
 		t = "invalid_type"
 		d = locale.InvalidType()
+	case *InvalidBSONTypeError:
+		t = "invalid_bson_type"
+		d = locale.InvalidBSONType()
 	case *NumberAnyOfError:
 		t = "number_any_of"
 		d = locale.NumberAnyOf()
@@ -246,6 +289,9 @@ func newError(err ResultError, context *JsonContext, value interface{}, locale l
 	case *DoesNotMatchPatternError:
 		t = "pattern"
 		d = locale.DoesNotMatchPattern()
+	case *RegexStepLimitError:
+		t = "regex_step_limit"
+		d = locale.RegexStepLimit()
 	case *DoesNotMatchFormatError:
 		t = "format"
 		d = locale.DoesNotMatchFormat()
@@ -264,6 +310,9 @@ func newError(err ResultError, context *JsonContext, value interface{}, locale l
 	case *NumberLTError:
 		t = "number_lt"
 		d = locale.NumberLT()
+	case *DiscriminatorError:
+		t = "discriminator"
+		d = locale.Discriminator()
 	case *ConditionThenError:
 		t = "condition_then"
 		d = locale.ConditionThen()
@@ -272,18 +321,7 @@ func newError(err ResultError, context *JsonContext, value interface{}, locale l
 		d = locale.ConditionElse()
 	}
 
-	err.SetType(t)
-	err.SetContext(context)
-	err.SetValue(value)
-	err.SetDetails(details)
-	err.SetDescriptionFormat(d)
-	details["field"] = err.Field()
-
-	if _, exists := details["context"]; !exists && context != nil {
-		details["context"] = context.String()
-	}
-
-	err.SetDescription(formatErrorDescription(err.DescriptionFormat(), details))
+	return t, d
 }
 
 // formatErrorDescription takes a string in the default text/template
@@ -0,0 +1,352 @@
+package gojsonschema
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xeipuuv/gojsonreference"
+)
+
+// MongoDB Extended JSON loader
+// Unwraps the canonical and relaxed Extended JSON v2 representations
+// ( https://github.com/mongodb/specifications/blob/master/source/extended-json.rst )
+// as well as the legacy ones produced by older tools, into the values of the BSON loader.
+// Plain JSON numbers are kept as json.Number, their BSON type is int, long or double
+// depending on their syntax and range, like in relaxed Extended JSON.
+
+type extendedJsonLoader struct {
+	source string
+}
+
+func (l *extendedJsonLoader) JsonSource() interface{} {
+	return l.source
+}
+
+func (l *extendedJsonLoader) JsonReference() (gojsonreference.JsonReference, error) {
+	return gojsonreference.NewJsonReference("#")
+}
+
+func (l *extendedJsonLoader) LoaderFactory() JSONLoaderFactory {
+	return &DefaultJSONLoaderFactory{}
+}
+
+// NewExtendedJSONLoader returns a loader for a MongoDB Extended JSON document, i.e. exported by mongoexport
+func NewExtendedJSONLoader(source string) *extendedJsonLoader {
+	return &extendedJsonLoader{source: source}
+}
+
+func (l *extendedJsonLoader) LoadJSON() (interface{}, error) {
+
+	document, err := decodeJsonUsingNumber(strings.NewReader(l.JsonSource().(string)))
+	if err != nil {
+		return nil, err
+	}
+
+	return unwrapExtendedJSON(document, NewJsonContext(STRING_CONTEXT_ROOT, nil))
+}
+
+// unwrapExtendedJSON replaces the Extended JSON wrappers of a decoded JSON document by their values.
+// context is the path of the value, used to report invalid wrappers.
+func unwrapExtendedJSON(value interface{}, context *JsonContext) (interface{}, error) {
+
+	switch v := value.(type) {
+
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i := range v {
+			item, err := unwrapExtendedJSON(v[i], NewJsonContext(strconv.Itoa(i), context))
+			if err != nil {
+				return nil, err
+			}
+			res[i] = item
+		}
+		return res, nil
+
+	case map[string]interface{}:
+		if wrapped, ok, err := unwrapExtendedJSONValue(v, context); ok || err != nil {
+			return wrapped, err
+		}
+		res := make(map[string]interface{}, len(v))
+		for k := range v {
+			item, err := unwrapExtendedJSON(v[k], NewJsonContext(k, context))
+			if err != nil {
+				return nil, err
+			}
+			res[k] = item
+		}
+		return res, nil
+	}
+
+	return value, nil
+}
+
+func extendedJSONError(context *JsonContext, reason string) error {
+	return errors.New(formatErrorDescription(
+		Locale.ExtendedJSONParseError(),
+		ErrorDetails{"context": context.String(), "reason": reason},
+	))
+}
+
+// hasExactKeys checks the keys of a wrapper, other keys make it a regular document
+func hasExactKeys(m map[string]interface{}, keys ...string) bool {
+	if len(m) != len(keys) {
+		return false
+	}
+	for _, k := range keys {
+		if _, ok := m[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// unwrapExtendedJSONValue returns the value of a wrapper, ok is false for regular documents
+func unwrapExtendedJSONValue(m map[string]interface{}, context *JsonContext) (value interface{}, ok bool, err error) {
+
+	invalid := func(key string) (interface{}, bool, error) {
+		return nil, true, extendedJSONError(context, "invalid "+key+" value")
+	}
+
+	switch {
+
+	case hasExactKeys(m, "$oid"):
+		s, isString := m["$oid"].(string)
+		if !isString {
+			return invalid("$oid")
+		}
+		id, err := ObjectIDFromHex(s)
+		if err != nil {
+			return invalid("$oid")
+		}
+		return id, true, nil
+
+	case hasExactKeys(m, "$symbol"):
+		s, isString := m["$symbol"].(string)
+		if !isString {
+			return invalid("$symbol")
+		}
+		return Symbol(s), true, nil
+
+	case hasExactKeys(m, "$numberInt"):
+		s, isString := m["$numberInt"].(string)
+		if !isString {
+			return invalid("$numberInt")
+		}
+		i, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return invalid("$numberInt")
+		}
+		return int32(i), true, nil
+
+	case hasExactKeys(m, "$numberLong"):
+		s, isString := m["$numberLong"].(string)
+		if !isString {
+			return invalid("$numberLong")
+		}
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return invalid("$numberLong")
+		}
+		return i, true, nil
+
+	case hasExactKeys(m, "$numberDouble"):
+		s, isString := m["$numberDouble"].(string)
+		if !isString {
+			return invalid("$numberDouble")
+		}
+		switch s {
+		case "Infinity":
+			return math.Inf(1), true, nil
+		case "-Infinity":
+			return math.Inf(-1), true, nil
+		case "NaN":
+			return math.NaN(), true, nil
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return invalid("$numberDouble")
+		}
+		return f, true, nil
+
+	case hasExactKeys(m, "$numberDecimal"):
+		s, isString := m["$numberDecimal"].(string)
+		if !isString {
+			return invalid("$numberDecimal")
+		}
+		d, err := ParseDecimal128(s)
+		if err != nil {
+			return invalid("$numberDecimal")
+		}
+		return d, true, nil
+
+	case hasExactKeys(m, "$binary"):
+		b, isMap := m["$binary"].(map[string]interface{})
+		if !isMap || !hasExactKeys(b, "base64", "subType") {
+			return invalid("$binary")
+		}
+		return unwrapExtendedJSONBinary(b["base64"], b["subType"], context)
+
+	case hasExactKeys(m, "$binary", "$type"):
+		// legacy binary
+		return unwrapExtendedJSONBinary(m["$binary"], m["$type"], context)
+
+	case hasExactKeys(m, "$code"):
+		s, isString := m["$code"].(string)
+		if !isString {
+			return invalid("$code")
+		}
+		return JavaScript(s), true, nil
+
+	case hasExactKeys(m, "$code", "$scope"):
+		s, isString := m["$code"].(string)
+		scope, isMap := m["$scope"].(map[string]interface{})
+		if !isString || !isMap {
+			return invalid("$code")
+		}
+		unwrappedScope, err := unwrapExtendedJSON(scope, NewJsonContext("$scope", context))
+		if err != nil {
+			return nil, true, err
+		}
+		return CodeWithScope{Code: JavaScript(s), Scope: unwrappedScope}, true, nil
+
+	case hasExactKeys(m, "$timestamp"):
+		ts, isMap := m["$timestamp"].(map[string]interface{})
+		if !isMap || !hasExactKeys(ts, "t", "i") {
+			return invalid("$timestamp")
+		}
+		t, tOk := extendedJSONUint32(ts["t"])
+		i, iOk := extendedJSONUint32(ts["i"])
+		if !tOk || !iOk {
+			return invalid("$timestamp")
+		}
+		return Timestamp{T: t, I: i}, true, nil
+
+	case hasExactKeys(m, "$regularExpression"):
+		re, isMap := m["$regularExpression"].(map[string]interface{})
+		if !isMap || !hasExactKeys(re, "pattern", "options") {
+			return invalid("$regularExpression")
+		}
+		pattern, patternOk := re["pattern"].(string)
+		options, optionsOk := re["options"].(string)
+		if !patternOk || !optionsOk {
+			return invalid("$regularExpression")
+		}
+		return Regex{Pattern: pattern, Options: options}, true, nil
+
+	case hasExactKeys(m, "$regex", "$options"):
+		// legacy regular expression, {"$regex": ...} alone is a query operator and is left as is
+		pattern, patternOk := m["$regex"].(string)
+		options, optionsOk := m["$options"].(string)
+		if !patternOk || !optionsOk {
+			return nil, false, nil
+		}
+		return Regex{Pattern: pattern, Options: options}, true, nil
+
+	case hasExactKeys(m, "$dbPointer"):
+		p, isMap := m["$dbPointer"].(map[string]interface{})
+		if !isMap || !hasExactKeys(p, "$ref", "$id") {
+			return invalid("$dbPointer")
+		}
+		db, isString := p["$ref"].(string)
+		id, _, err := unwrapExtendedJSONValue(mapOrEmpty(p["$id"]), context)
+		pointer, isObjectID := id.(ObjectID)
+		if !isString || err != nil || !isObjectID {
+			return invalid("$dbPointer")
+		}
+		return DBPointer{DB: db, Pointer: pointer}, true, nil
+
+	case hasExactKeys(m, "$date"):
+		return unwrapExtendedJSONDate(m["$date"], context)
+
+	case hasExactKeys(m, "$minKey"):
+		if n, isNumber := m["$minKey"].(json.Number); !isNumber || n != "1" {
+			return invalid("$minKey")
+		}
+		return MinKey{}, true, nil
+
+	case hasExactKeys(m, "$maxKey"):
+		if n, isNumber := m["$maxKey"].(json.Number); !isNumber || n != "1" {
+			return invalid("$maxKey")
+		}
+		return MaxKey{}, true, nil
+
+	case hasExactKeys(m, "$undefined"):
+		if b, isBool := m["$undefined"].(bool); !isBool || !b {
+			return invalid("$undefined")
+		}
+		return Undefined{}, true, nil
+	}
+
+	return nil, false, nil
+}
+
+func mapOrEmpty(value interface{}) map[string]interface{} {
+	if m, ok := value.(map[string]interface{}); ok {
+		return m
+	}
+	return map[string]interface{}{}
+}
+
+func extendedJSONUint32(value interface{}) (uint32, bool) {
+	n, ok := value.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	i, err := strconv.ParseUint(string(n), 10, 32)
+	return uint32(i), err == nil
+}
+
+func unwrapExtendedJSONBinary(data interface{}, subType interface{}, context *JsonContext) (interface{}, bool, error) {
+
+	b64, dataOk := data.(string)
+	st, subTypeOk := subType.(string)
+	if !dataOk || !subTypeOk || len(st) == 0 || len(st) > 2 {
+		return nil, true, extendedJSONError(context, "invalid $binary value")
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return nil, true, extendedJSONError(context, "invalid $binary value")
+	}
+	subTypeByte, err := hex.DecodeString(strings.Repeat("0", 2-len(st)) + st)
+	if err != nil {
+		return nil, true, extendedJSONError(context, "invalid $binary value")
+	}
+
+	return Binary{Subtype: subTypeByte[0], Data: decoded}, true, nil
+}
+
+func unwrapExtendedJSONDate(date interface{}, context *JsonContext) (interface{}, bool, error) {
+
+	switch d := date.(type) {
+
+	case map[string]interface{}:
+		// canonical, {"$date": {"$numberLong": "..."}}
+		ms, ok, err := unwrapExtendedJSONValue(d, context)
+		if ms, isLong := ms.(int64); ok && err == nil && isLong && hasExactKeys(d, "$numberLong") {
+			return DateTime(ms), true, nil
+		}
+
+	case string:
+		// relaxed, ISO-8601
+		t, err := time.Parse(time.RFC3339Nano, d)
+		if err == nil {
+			return NewDateTimeFromTime(t), true, nil
+		}
+
+	case json.Number:
+		// legacy, milliseconds since the epoch
+		ms, err := d.Int64()
+		if err == nil {
+			return DateTime(ms), true, nil
+		}
+	}
+
+	return nil, true, extendedJSONError(context, "invalid $date value")
+}
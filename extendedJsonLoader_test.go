@@ -0,0 +1,208 @@
+package gojsonschema
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtendedJSONLoader(t *testing.T) {
+	value, err := NewExtendedJSONLoader(`{
+		"_id": {"$oid": "5a934e000102030405000000"},
+		"int": {"$numberInt": "3"},
+		"long": {"$numberLong": "1099511627776"},
+		"double": {"$numberDouble": "-Infinity"},
+		"decimal": {"$numberDecimal": "19.99"},
+		"plain": 42,
+		"binary": {"$binary": {"base64": "yv4=", "subType": "00"}},
+		"legacyBinary": {"$binary": "yv4=", "$type": "5"},
+		"code": {"$code": "x"},
+		"scope": {"$code": "x", "$scope": {"y": {"$numberInt": "1"}}},
+		"ts": {"$timestamp": {"t": 5, "i": 1}},
+		"re": {"$regularExpression": {"pattern": "^a", "options": "i"}},
+		"legacyRe": {"$regex": "^a", "$options": "i"},
+		"query": {"$regex": "^a"},
+		"pointer": {"$dbPointer": {"$ref": "db.c", "$id": {"$oid": "5a934e000102030405000000"}}},
+		"ref": {"$ref": "c", "$id": {"$oid": "5a934e000102030405000000"}},
+		"canonicalDate": {"$date": {"$numberLong": "1519603200000"}},
+		"relaxedDate": {"$date": "2018-02-26T00:00:00Z"},
+		"legacyDate": {"$date": 1519603200000},
+		"min": {"$minKey": 1},
+		"max": {"$maxKey": 1},
+		"undefined": {"$undefined": true},
+		"symbol": {"$symbol": "s"},
+		"array": [{"$numberInt": "1"}, "a"]
+	}`).LoadJSON()
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	id, err := ObjectIDFromHex("5a934e000102030405000000")
+	assert.Nil(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"_id":           id,
+		"int":           int32(3),
+		"long":          int64(1 << 40),
+		"double":        math.Inf(-1),
+		"decimal":       NewDecimal128(uint64(6176-2)<<49, 1999),
+		"plain":         json.Number("42"),
+		"binary":        Binary{Subtype: 0, Data: []byte{0xCA, 0xFE}},
+		"legacyBinary":  Binary{Subtype: 5, Data: []byte{0xCA, 0xFE}},
+		"code":          JavaScript("x"),
+		"scope":         CodeWithScope{Code: "x", Scope: map[string]interface{}{"y": int32(1)}},
+		"ts":            Timestamp{T: 5, I: 1},
+		"re":            Regex{Pattern: "^a", Options: "i"},
+		"legacyRe":      Regex{Pattern: "^a", Options: "i"},
+		"query":         map[string]interface{}{"$regex": "^a"},
+		"pointer":       DBPointer{DB: "db.c", Pointer: id},
+		"ref":           map[string]interface{}{"$ref": "c", "$id": id},
+		"canonicalDate": DateTime(1519603200000),
+		"relaxedDate":   NewDateTimeFromTime(time.Date(2018, 2, 26, 0, 0, 0, 0, time.UTC)),
+		"legacyDate":    DateTime(1519603200000),
+		"min":           MinKey{},
+		"max":           MaxKey{},
+		"undefined":     Undefined{},
+		"symbol":        Symbol("s"),
+		"array":         []interface{}{int32(1), "a"},
+	}, value)
+}
+
+func TestExtendedJSONLoaderErrors(t *testing.T) {
+	testCases := []struct {
+		document string
+		context  string
+	}{
+		{`{"_id": {"$oid": "xyz"}}`, "(root)._id"},
+		{`{"a": {"b": {"$numberInt": "4294967296"}}}`, "(root).a.b"},
+		{`{"a": [1, {"$numberLong": 5}]}`, "(root).a.1"},
+		{`{"a": {"$numberDecimal": "1.2.3"}}`, "(root).a"},
+		{`{"a": {"$binary": {"base64": "!", "subType": "00"}}}`, "(root).a"},
+		{`{"a": {"$timestamp": {"t": -1, "i": 0}}}`, "(root).a"},
+		{`{"a": {"$date": "yesterday"}}`, "(root).a"},
+		{`{"a": {"$minKey": 0}}`, "(root).a"},
+	}
+
+	for _, testCase := range testCases {
+		_, err := NewExtendedJSONLoader(testCase.document).LoadJSON()
+		if assert.NotNil(t, err, "document: %s", testCase.document) {
+			assert.True(t, strings.Contains(err.Error(), testCase.context), "%s does not contain %s", err, testCase.context)
+		}
+	}
+}
+
+func TestExtendedJSONValidation(t *testing.T) {
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {
+			"count": {"type": "integer", "minimum": 5},
+			"ratio": {"type": "number", "multipleOf": 0.25},
+			"price": {"type": "number", "maximum": 20}
+		}
+	}`))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	result, err := schema.Validate(NewExtendedJSONLoader(`{
+		"count": {"$numberLong": "5"},
+		"ratio": {"$numberDouble": "0.75"},
+		"price": {"$numberDecimal": "19.99"}
+	}`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid(), "%v", result.Errors())
+
+	result, err = schema.Validate(NewExtendedJSONLoader(`{
+		"count": {"$numberInt": "4"},
+		"ratio": {"$numberDouble": "0.1"},
+		"price": {"$numberDecimal": "20.01"}
+	}`))
+	assert.Nil(t, err)
+
+	types := map[string]string{}
+	for _, e := range result.Errors() {
+		types[e.Field()] = e.Type()
+	}
+	assert.Equal(t, map[string]string{
+		"count": "number_gte",
+		"ratio": "multiple_of",
+		"price": "number_lte",
+	}, types)
+
+	mongo := compileMongoDB(t, `{
+		"properties": {
+			"_id": {"bsonType": "objectId"},
+			"created": {"bsonType": "date"},
+			"count": {"bsonType": "long"}
+		}
+	}`)
+
+	result, err = mongo.Validate(NewExtendedJSONLoader(`{
+		"_id": {"$oid": "5a934e000102030405000000"},
+		"created": {"$date": "2018-02-26T00:00:00Z"},
+		"count": {"$numberLong": "1"}
+	}`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid(), "%v", result.Errors())
+
+	result, err = mongo.Validate(NewExtendedJSONLoader(`{"count": {"$numberInt": "1"}}`))
+	assert.Nil(t, err)
+	assert.Len(t, result.Errors(), 1)
+}
+
+func TestExtendedJSONNonFiniteDoubles(t *testing.T) {
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {
+			"nan": {"type": "number", "minimum": 0},
+			"inf": {"maximum": 10, "minimum": 0},
+			"ninf": {"exclusiveMaximum": 10, "multipleOf": 2},
+			"text": {"type": "string"},
+			"free": {"type": "number"}
+		}
+	}`))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	result, err := schema.Validate(NewExtendedJSONLoader(`{
+		"nan": {"$numberDouble": "NaN"},
+		"inf": {"$numberDouble": "Infinity"},
+		"ninf": {"$numberDouble": "-Infinity"},
+		"text": {"$numberDouble": "NaN"},
+		"free": {"$numberDouble": "-Infinity"}
+	}`))
+	assert.Nil(t, err)
+
+	var errorTypes []string
+	for _, e := range result.Errors() {
+		errorTypes = append(errorTypes, e.Field()+" "+e.Type())
+	}
+	assert.ElementsMatch(t, []string{
+		"nan number_gte",
+		"inf number_lte",
+		"ninf multiple_of",
+		"text invalid_type",
+	}, errorTypes)
+}
+
+func TestParseDecimal128(t *testing.T) {
+	testCases := []string{
+		"0", "1", "-1", "19.99", "0.00012", "1E-12", "1E+3", "1.2E+4", "NaN", "Infinity", "-Infinity",
+		"9999999999999999999999999999999999",
+	}
+
+	for _, testCase := range testCases {
+		d, err := ParseDecimal128(testCase)
+		if assert.Nil(t, err, "decimal: %s", testCase) {
+			assert.Equal(t, testCase, d.String())
+		}
+	}
+
+	for _, invalid := range []string{"", "-", "1.2.3", "1e", "abc", "1E+99999"} {
+		_, err := ParseDecimal128(invalid)
+		assert.NotNil(t, err, "decimal: %s", invalid)
+	}
+}
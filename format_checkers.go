@@ -64,7 +64,10 @@ type (
 	UUIDFormatChecker struct{}
 
 	// RegexFormatChecker validates a regex is in the correct format
-	RegexFormatChecker struct{}
+	// using Engine, the RE2 syntax of the regexp package when not set
+	RegexFormatChecker struct {
+		Engine RegexEngine
+	}
 )
 
 var (
@@ -242,7 +245,11 @@ func (f RegexFormatChecker) IsFormat(input interface{}) bool {
 	if asString == "" {
 		return true
 	}
-	_, err := regexp.Compile(asString)
+	engine := f.Engine
+	if engine == nil {
+		engine = defaultRegexEngine
+	}
+	_, err := engine.Compile(asString)
 	if err != nil {
 		return false
 	}
@@ -0,0 +1,643 @@
+package gojsonschema
+
+import (
+	"bytes"
+	"go/format"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Go code generation
+// Generates Go types mirroring a compiled schema: objects become structs, the definitions of
+// the root schema named types and enums typed constants. Properties are sorted and declarations
+// named after their position in the schema, so the output is stable and can be checked in.
+
+// GoGeneratorOptions configures GenerateGo
+type GoGeneratorOptions struct {
+	// PackageName of the generated file, defaults to "schema"
+	PackageName string
+	// RootType is the name of the type of the root schema, defaults to its title or "Root"
+	RootType string
+}
+
+// GenerateGo generates the source of a Go file declaring the types of the schema
+func GenerateGo(schema *Schema, options GoGeneratorOptions) ([]byte, error) {
+
+	if options.PackageName == "" {
+		options.PackageName = "schema"
+	}
+
+	g := &goGenerator{
+		decls:    map[string]*goDecl{},
+		named:    map[*subSchema]string{},
+		refs:     map[string]string{},
+		visiting: map[*subSchema]bool{},
+		imports:  map[string]bool{},
+	}
+
+	root := schema.rootSchema
+
+	rootName := options.RootType
+	if rootName == "" && root.title != nil {
+		rootName = goName(*root.title)
+	}
+	if rootName == "" {
+		rootName = "Root"
+	}
+
+	// the root and its definitions are named first, so references to them resolve to their names
+	rootName = g.name(root, rootName)
+	if root.id != nil {
+		g.refs[root.id.String()] = rootName
+		g.refs[strings.TrimSuffix(root.id.String(), "#")+"#"] = rootName
+	}
+	var definitions []string
+	for k := range root.definitions {
+		definitions = append(definitions, k)
+	}
+	sort.Strings(definitions)
+	for _, k := range definitions {
+		definition := root.definitions[k]
+		name := g.name(definition, goName(k))
+		if definition.id != nil {
+			g.refs[definition.id.String()] = name
+		}
+	}
+
+	g.declare(root, rootName)
+	for _, k := range definitions {
+		definition := root.definitions[k]
+		g.declare(definition, g.named[definition])
+	}
+
+	g.breakValueCycles()
+
+	return g.render(options.PackageName, rootName)
+}
+
+type goGenerator struct {
+	decls map[string]*goDecl
+	// named schemas and the names of the schemas they reference
+	named map[*subSchema]string
+	refs  map[string]string
+	// schemas whose type is being computed, to stop on cycles
+	visiting map[*subSchema]bool
+	imports  map[string]bool
+}
+
+type goDecl struct {
+	name    string
+	comment string
+	// a struct has fields, other declarations an underlying type
+	isStruct   bool
+	fields     []*goField
+	underlying string
+	consts     []goConst
+	// whether optional values of the type are pointers
+	pointerable bool
+}
+
+type goField struct {
+	name    string
+	typ     string
+	tag     string
+	comment string
+	// named struct type held by value, checked for recursive types
+	valueOf string
+}
+
+type goConst struct {
+	name  string
+	value string
+}
+
+// goType is the Go type of a schema
+type goType struct {
+	expr string
+	// type held by value when expr is a named struct
+	valueOf string
+	// the schema allows null
+	nullable bool
+	// optional and nullable values are pointers, unlike slices, maps and interfaces
+	pointerable bool
+	comment     string
+}
+
+// name reserves a unique type name for a schema
+func (g *goGenerator) name(s *subSchema, base string) string {
+	name := base
+	for i := 2; ; i++ {
+		if _, exists := g.decls[name]; !exists {
+			break
+		}
+		name = base + strconv.Itoa(i)
+	}
+	g.decls[name] = &goDecl{name: name, isStruct: g.isStruct(s), pointerable: g.isStruct(s) || g.hasEnumConsts(s) || isScalarSchema(s)}
+	g.named[s] = name
+	return name
+}
+
+// resolve follows $ref, returning the name of the referenced type when it has one
+func (g *goGenerator) resolve(s *subSchema) (*subSchema, string) {
+	for s.refSchema != nil {
+		if name, ok := g.refs[s.ref.String()]; ok {
+			return s, name
+		}
+		s = s.refSchema
+	}
+	return s, g.named[s]
+}
+
+func (g *goGenerator) isStruct(s *subSchema) bool {
+	s, _ = g.resolve(s)
+	if len(s.types.types) > 0 && !s.types.Contains(TYPE_OBJECT) {
+		return false
+	}
+	if len(s.propertiesChildren) > 0 {
+		return true
+	}
+	if len(s.allOf) == 0 {
+		return false
+	}
+	for _, branch := range s.allOf {
+		if !g.isStruct(branch) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasEnumConsts reports whether the enum of a schema can be declared as constants
+func (g *goGenerator) hasEnumConsts(s *subSchema) bool {
+	_, base := enumBaseType(s)
+	return base != ""
+}
+
+// enumBaseType returns the Go type of an enum of strings or integers
+func enumBaseType(s *subSchema) ([]*jsonValue, string) {
+	values := s.enum.values
+	if len(values) == 0 {
+		return nil, ""
+	}
+	allStrings, allIntegers := true, true
+	for _, v := range values {
+		switch n := v.normalized.(type) {
+		case string:
+			allIntegers = false
+		case *big.Float:
+			allStrings = false
+			if !n.IsInt() {
+				allIntegers = false
+			}
+		default:
+			allStrings, allIntegers = false, false
+		}
+	}
+	switch {
+	case allStrings:
+		return values, "string"
+	case allIntegers:
+		return values, "int64"
+	}
+	return nil, ""
+}
+
+func isScalarSchema(s *subSchema) bool {
+	var types []string
+	for _, t := range s.types.types {
+		if t != TYPE_NULL {
+			types = append(types, t)
+		}
+	}
+	if len(types) != 1 {
+		return false
+	}
+	switch types[0] {
+	case TYPE_STRING, TYPE_INTEGER, TYPE_NUMBER, TYPE_BOOLEAN:
+		return true
+	}
+	return false
+}
+
+func (g *goGenerator) ref(name string) goType {
+	decl := g.decls[name]
+	t := goType{expr: name, pointerable: decl.pointerable}
+	if decl.isStruct {
+		t.valueOf = name
+	}
+	return t
+}
+
+// typeOf returns the Go type of the values of a schema, declaring the named types it needs
+func (g *goGenerator) typeOf(s *subSchema, hint string) goType {
+
+	s, name := g.resolve(s)
+	if name != "" {
+		t := g.ref(name)
+		t.nullable = s.types.Contains(TYPE_NULL)
+		return t
+	}
+
+	if g.visiting[s] {
+		// a cycle through unnamed schemas
+		g.imports["encoding/json"] = true
+		return goType{expr: "json.RawMessage"}
+	}
+
+	if g.isStruct(s) || g.hasEnumConsts(s) {
+		name := g.name(s, hint)
+		g.declare(s, name)
+		t := g.ref(name)
+		t.nullable = s.types.Contains(TYPE_NULL)
+		return t
+	}
+
+	g.visiting[s] = true
+	defer delete(g.visiting, s)
+
+	return g.inlineType(s, hint)
+}
+
+// inlineType returns the Go type of a schema that is not declared as a named type
+func (g *goGenerator) inlineType(s *subSchema, hint string) goType {
+
+	if len(s.oneOf) > 0 || len(s.anyOf) > 0 {
+		return g.unionType(s, hint)
+	}
+
+	var types []string
+	for _, t := range s.types.types {
+		if t != TYPE_NULL {
+			types = append(types, t)
+		}
+	}
+	nullable := s.types.Contains(TYPE_NULL)
+
+	if len(types) == 0 {
+		switch {
+		case len(s.itemsChildren) > 0:
+			types = []string{TYPE_ARRAY}
+		case s.additionalProperties != nil:
+			if _, ok := s.additionalProperties.(*subSchema); ok {
+				types = []string{TYPE_OBJECT}
+			}
+		}
+	}
+
+	if len(types) != 1 {
+		return goType{expr: "interface{}", nullable: nullable}
+	}
+
+	t := goType{nullable: nullable, pointerable: true}
+
+	switch types[0] {
+
+	case TYPE_STRING:
+		t.expr = "string"
+		if s.format == "date-time" {
+			g.imports["time"] = true
+			t.expr = "time.Time"
+		}
+
+	case TYPE_INTEGER:
+		t.expr = "int64"
+		if s.format == "int32" {
+			t.expr = "int32"
+		}
+
+	case TYPE_NUMBER:
+		t.expr = "float64"
+		if s.format == "float" {
+			t.expr = "float32"
+		}
+
+	case TYPE_BOOLEAN:
+		t.expr = "bool"
+
+	case TYPE_ARRAY:
+		t.pointerable = false
+		t.expr = "[]interface{}"
+		if len(s.itemsChildren) == 1 && s.itemsChildrenIsSingleSchema {
+			t.expr = "[]" + g.typeOf(s.itemsChildren[0], hint+"Item").expr
+		}
+
+	case TYPE_OBJECT:
+		t.pointerable = false
+		t.expr = "map[string]interface{}"
+		if additional, ok := s.additionalProperties.(*subSchema); ok {
+			t.expr = "map[string]" + g.typeOf(additional, hint+"Value").expr
+		}
+
+	default:
+		t.pointerable = false
+		t.expr = "interface{}"
+	}
+
+	return t
+}
+
+// unionType returns the type of oneOf and anyOf: interface{} for alternative scalar types,
+// json.RawMessage to be decoded in one of the branch types otherwise
+func (g *goGenerator) unionType(s *subSchema, hint string) goType {
+
+	keyword, branches := KEY_ONE_OF, s.oneOf
+	if len(branches) == 0 {
+		keyword, branches = KEY_ANY_OF, s.anyOf
+	}
+
+	scalars := true
+	var names []string
+	for i, branch := range branches {
+		t := g.typeOf(branch, hint+"Option"+strconv.Itoa(i+1))
+		names = append(names, t.expr)
+		switch t.expr {
+		case "string", "int64", "int32", "float64", "float32", "bool":
+		default:
+			scalars = false
+		}
+	}
+
+	comment := strings.Replace(keyword, "Of", " of", 1) + ": " + strings.Join(names, ", ")
+
+	if scalars {
+		return goType{expr: "interface{}", comment: comment}
+	}
+	g.imports["encoding/json"] = true
+	return goType{expr: "json.RawMessage", comment: comment}
+}
+
+// declare builds the declaration of a named schema
+func (g *goGenerator) declare(s *subSchema, name string) {
+
+	decl := g.decls[name]
+
+	resolved, _ := g.resolve(s)
+	if resolved.description != nil {
+		decl.comment = *resolved.description
+	}
+
+	if resolved != s {
+		// a definition that is a reference to another one
+		if _, target := g.resolve(s); target != "" && target != name {
+			decl.underlying = target
+			return
+		}
+	}
+
+	g.visiting[resolved] = true
+	defer delete(g.visiting, resolved)
+
+	if decl.isStruct {
+		decl.fields = g.structFields(resolved, name)
+		return
+	}
+
+	if values, base := enumBaseType(resolved); base != "" {
+		decl.underlying = base
+		decl.consts = g.enumConsts(values, name, base)
+		return
+	}
+
+	decl.underlying = g.inlineType(resolved, name).expr
+}
+
+func (g *goGenerator) enumConsts(values []*jsonValue, typeName string, base string) []goConst {
+
+	var consts []goConst
+	used := map[string]bool{}
+
+	for _, v := range values {
+		var suffix, literal string
+		if base == "string" {
+			literal = strconv.Quote(v.normalized.(string))
+			suffix = goName(v.normalized.(string))
+			if suffix == "" {
+				suffix = "Empty"
+			}
+		} else {
+			literal = v.normalized.(*big.Float).Text('f', 0)
+			suffix = strings.Replace(literal, "-", "Minus", 1)
+		}
+
+		name := typeName + suffix
+		for i := 2; used[name]; i++ {
+			name = typeName + suffix + strconv.Itoa(i)
+		}
+		used[name] = true
+
+		consts = append(consts, goConst{name: name, value: literal})
+	}
+
+	return consts
+}
+
+func (g *goGenerator) structFields(s *subSchema, typeName string) []*goField {
+
+	var fields []*goField
+	used := map[string]bool{}
+
+	// allOf branches are embedded when named, merged otherwise
+	for _, branch := range s.allOf {
+		resolved, name := g.resolve(branch)
+		if name != "" {
+			used[name] = true
+			fields = append(fields, &goField{typ: name, valueOf: name})
+		} else {
+			fields = append(fields, g.structFields(resolved, typeName)...)
+		}
+	}
+
+	properties := append([]*subSchema{}, s.propertiesChildren...)
+	sort.Slice(properties, func(i, j int) bool { return properties[i].property < properties[j].property })
+
+	for _, property := range properties {
+
+		fieldName := goName(property.property)
+		if fieldName == "" {
+			fieldName = "Field"
+		}
+		if !unicode.IsLetter([]rune(fieldName)[0]) {
+			fieldName = "X" + fieldName
+		}
+		base := fieldName
+		for i := 2; used[fieldName]; i++ {
+			fieldName = base + strconv.Itoa(i)
+		}
+		used[fieldName] = true
+
+		t := g.typeOf(property, typeName+fieldName)
+		required := isStringInSlice(s.required, property.property)
+
+		field := &goField{name: fieldName, typ: t.expr, comment: t.comment}
+		if (!required || t.nullable) && t.pointerable {
+			field.typ = "*" + t.expr
+		} else {
+			field.valueOf = t.valueOf
+		}
+
+		field.tag = property.property
+		if !required {
+			field.tag += ",omitempty"
+		}
+
+		if resolved, _ := g.resolve(property); property.description != nil || resolved.description != nil {
+			description := property.description
+			if description == nil {
+				description = resolved.description
+			}
+			if field.comment != "" {
+				field.comment = *description + "\n" + field.comment
+			} else {
+				field.comment = *description
+			}
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+// breakValueCycles makes pointers of the struct fields that would make a type contain itself
+func (g *goGenerator) breakValueCycles() {
+
+	for _, name := range g.sortedNames() {
+		for _, field := range g.decls[name].fields {
+			if field.valueOf != "" && g.containsByValue(field.valueOf, name, map[string]bool{}) {
+				field.typ = "*" + field.typ
+				field.valueOf = ""
+			}
+		}
+	}
+}
+
+func (g *goGenerator) containsByValue(from string, target string, seen map[string]bool) bool {
+	if from == target {
+		return true
+	}
+	if seen[from] {
+		return false
+	}
+	seen[from] = true
+	decl, ok := g.decls[from]
+	if !ok {
+		return false
+	}
+	for _, field := range decl.fields {
+		if field.valueOf != "" && g.containsByValue(field.valueOf, target, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *goGenerator) sortedNames() []string {
+	var names []string
+	for name := range g.decls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func writeGoComment(b *bytes.Buffer, comment string, indent string) {
+	if comment == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(comment), "\n") {
+		b.WriteString(indent + "// " + strings.TrimSpace(line) + "\n")
+	}
+}
+
+func (g *goGenerator) render(packageName string, rootName string) ([]byte, error) {
+
+	var b bytes.Buffer
+
+	b.WriteString("// Code generated by gojsonschema gen. DO NOT EDIT.\n\n")
+	b.WriteString("package " + packageName + "\n\n")
+
+	if len(g.imports) > 0 {
+		var imports []string
+		for i := range g.imports {
+			imports = append(imports, i)
+		}
+		sort.Strings(imports)
+		b.WriteString("import (\n")
+		for _, i := range imports {
+			b.WriteString(strconv.Quote(i) + "\n")
+		}
+		b.WriteString(")\n\n")
+	}
+
+	// the root type first, then the others by name
+	names := []string{rootName}
+	for _, name := range g.sortedNames() {
+		if name != rootName {
+			names = append(names, name)
+		}
+	}
+
+	for _, name := range names {
+		decl := g.decls[name]
+
+		writeGoComment(&b, decl.comment, "")
+
+		if !decl.isStruct {
+			b.WriteString("type " + name + " " + decl.underlying + "\n\n")
+			if len(decl.consts) > 0 {
+				b.WriteString("const (\n")
+				for _, c := range decl.consts {
+					b.WriteString(c.name + " " + name + " = " + c.value + "\n")
+				}
+				b.WriteString(")\n\n")
+			}
+			continue
+		}
+
+		b.WriteString("type " + name + " struct {\n")
+		for _, field := range decl.fields {
+			if field.name == "" {
+				// embedded
+				b.WriteString(field.typ + "\n")
+				continue
+			}
+			writeGoComment(&b, field.comment, "")
+			b.WriteString(field.name + " " + field.typ + " `json:" + strconv.Quote(field.tag) + "`\n")
+		}
+		b.WriteString("}\n\n")
+	}
+
+	return format.Source(b.Bytes())
+}
+
+// Common initialisms, written in upper case in Go names
+var goInitialisms = map[string]bool{
+	"API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true, "HTML": true, "HTTP": true,
+	"HTTPS": true, "ID": true, "IP": true, "JSON": true, "SQL": true, "TCP": true, "TLS": true, "TTL": true,
+	"UDP": true, "UI": true, "URI": true, "URL": true, "UTF8": true, "UUID": true, "XML": true,
+}
+
+// goName converts a property, definition or enum value to an exported Go identifier
+func goName(s string) string {
+
+	var b strings.Builder
+
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for _, word := range words {
+		if goInitialisms[strings.ToUpper(word)] {
+			b.WriteString(strings.ToUpper(word))
+			continue
+		}
+		runes := []rune(word)
+		b.WriteRune(unicode.ToUpper(runes[0]))
+		b.WriteString(string(runes[1:]))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,157 @@
+package gojsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func generateGo(t *testing.T, schema string, options GoGeneratorOptions) string {
+	s, err := NewSchema(NewStringLoader(schema))
+	if !assert.Nil(t, err) {
+		return ""
+	}
+	source, err := GenerateGo(s, options)
+	if !assert.Nil(t, err) {
+		return ""
+	}
+	return string(source)
+}
+
+func TestGenerateGo(t *testing.T) {
+	schema := `{
+		"title": "order",
+		"type": "object",
+		"required": ["id", "lines", "customer"],
+		"properties": {
+			"id": {"type": "integer"},
+			"lines": {"type": "array", "items": {"$ref": "#/definitions/line"}},
+			"customer": {
+				"description": "Who placed the order",
+				"type": "object",
+				"required": ["name"],
+				"properties": {"name": {"type": "string"}, "vip": {"type": "boolean"}}
+			},
+			"note": {"type": ["string", "null"]},
+			"reference": {"oneOf": [{"type": "string"}, {"type": "integer"}]},
+			"extra": {"type": "object", "additionalProperties": {"type": "number"}}
+		},
+		"definitions": {
+			"line": {
+				"type": "object",
+				"required": ["quantity"],
+				"properties": {
+					"quantity": {"type": "integer"},
+					"unit": {"$ref": "#/definitions/unit"}
+				}
+			},
+			"unit": {"enum": ["kg", "piece", ""]}
+		}
+	}`
+
+	expected := `// Code generated by gojsonschema gen. DO NOT EDIT.
+
+package orders
+
+type Order struct {
+	// Who placed the order
+	Customer OrderCustomer      ` + "`" + `json:"customer"` + "`" + `
+	Extra    map[string]float64 ` + "`" + `json:"extra,omitempty"` + "`" + `
+	ID       int64              ` + "`" + `json:"id"` + "`" + `
+	Lines    []Line             ` + "`" + `json:"lines"` + "`" + `
+	Note     *string            ` + "`" + `json:"note,omitempty"` + "`" + `
+	// one of: string, int64
+	Reference interface{} ` + "`" + `json:"reference,omitempty"` + "`" + `
+}
+
+type Line struct {
+	Quantity int64 ` + "`" + `json:"quantity"` + "`" + `
+	Unit     *Unit ` + "`" + `json:"unit,omitempty"` + "`" + `
+}
+
+// Who placed the order
+type OrderCustomer struct {
+	Name string ` + "`" + `json:"name"` + "`" + `
+	Vip  *bool  ` + "`" + `json:"vip,omitempty"` + "`" + `
+}
+
+type Unit string
+
+const (
+	UnitKg    Unit = "kg"
+	UnitPiece Unit = "piece"
+	UnitEmpty Unit = ""
+)
+`
+
+	source := generateGo(t, schema, GoGeneratorOptions{PackageName: "orders"})
+	assert.Equal(t, expected, source)
+
+	// the output is stable
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, source, generateGo(t, schema, GoGeneratorOptions{PackageName: "orders"}))
+	}
+}
+
+func TestGenerateGoRecursive(t *testing.T) {
+	source := generateGo(t, `{
+		"type": "object",
+		"required": ["head"],
+		"properties": {"head": {"$ref": "#/definitions/node"}},
+		"definitions": {
+			"node": {
+				"type": "object",
+				"required": ["value", "next"],
+				"properties": {
+					"value": {"type": "integer", "enum": [1, -1]},
+					"next": {"$ref": "#/definitions/node"},
+					"children": {"type": "array", "items": {"$ref": "#/definitions/node"}}
+				}
+			}
+		}
+	}`, GoGeneratorOptions{RootType: "List"})
+
+	assert.Contains(t, source, "package schema\n")
+	assert.Contains(t, source, "Head Node `json:\"head\"`")
+	// a struct cannot contain itself
+	assert.Contains(t, source, "Next     *Node     `json:\"next\"`")
+	assert.Contains(t, source, "Children []Node    `json:\"children,omitempty\"`")
+	assert.Contains(t, source, "NodeValue1      NodeValue = 1\n")
+	assert.Contains(t, source, "NodeValueMinus1 NodeValue = -1\n")
+}
+
+func TestGenerateGoAllOf(t *testing.T) {
+	source := generateGo(t, `{
+		"allOf": [
+			{"$ref": "#/definitions/base"},
+			{"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}
+		],
+		"definitions": {
+			"base": {"type": "object", "properties": {"created": {"type": "string", "format": "date-time"}}}
+		}
+	}`, GoGeneratorOptions{})
+
+	assert.Contains(t, source, "import (\n\t\"time\"\n)")
+	assert.Contains(t, source, "type Root struct {\n\tBase\n\tName string `json:\"name\"`\n}")
+	assert.Contains(t, source, "Created *time.Time `json:\"created,omitempty\"`")
+}
+
+func TestGoName(t *testing.T) {
+	testCases := map[string]string{
+		"name":         "Name",
+		"first_name":   "FirstName",
+		"birth-date":   "BirthDate",
+		"userId":       "UserId",
+		"user id":      "UserID",
+		"url":          "URL",
+		"$schema":      "Schema",
+		"élément":      "Élément",
+		"":             "",
+		"2fa":          "2fa",
+		"HTTP-headers": "HTTPHeaders",
+	}
+
+	for input, expected := range testCases {
+		assert.Equal(t, expected, goName(input), "input: %s", input)
+	}
+}
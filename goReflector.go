@@ -0,0 +1,583 @@
+package gojsonschema
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Go types reflection
+// Builds a draft-07 schema of the JSON encoding of a Go type, following the rules of encoding/json:
+// json tags name the properties, omitempty ones are not required, pointers, slices and maps are
+// nullable and the fields of embedded structs are promoted. The jsonschema tag adds keywords :
+//
+//	Name string `json:"name" jsonschema:"minLength=3,maxLength=20,pattern=^[a-z]+$"`
+//	Kind string `json:"kind,omitempty" jsonschema:"enum=a|b,required"`
+//
+// Recursive types are declared in definitions and referenced with $ref.
+
+const draft07SchemaURI = "http://json-schema.org/draft-07/schema#"
+
+var (
+	timeType           = reflect.TypeOf(time.Time{})
+	jsonNumberType     = reflect.TypeOf(json.Number(""))
+	jsonRawMessageType = reflect.TypeOf(json.RawMessage{})
+	jsonMarshalerType  = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType  = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// ReflectSchema returns the schema of the JSON encoding of the type of v, which can also be a reflect.Type.
+// The schema is a document for NewGoLoader.
+func ReflectSchema(v interface{}) (map[string]interface{}, error) {
+
+	t, ok := v.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(v)
+	}
+	if t == nil {
+		return map[string]interface{}{KEY_SCHEMA: draft07SchemaURI}, nil
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	r := &schemaReflector{
+		root:        t,
+		definitions: map[string]interface{}{},
+		names:       map[reflect.Type]string{},
+		inProgress:  map[reflect.Type]bool{},
+		recursive:   map[reflect.Type]bool{},
+	}
+
+	schema, err := r.reflectType(t, t.String())
+	if err != nil {
+		return nil, err
+	}
+
+	schema[KEY_SCHEMA] = draft07SchemaURI
+	if len(r.definitions) > 0 {
+		schema[KEY_DEFINITIONS] = r.definitions
+	}
+
+	return schema, nil
+}
+
+type schemaReflector struct {
+	root        reflect.Type
+	definitions map[string]interface{}
+	// definition names of the recursive types
+	names map[reflect.Type]string
+	// structs being reflected, a struct met again is recursive
+	inProgress map[reflect.Type]bool
+	recursive  map[reflect.Type]bool
+}
+
+// reflectType returns the schema of a type, field naming the value for errors
+func (r *schemaReflector) reflectType(t reflect.Type, field string) (map[string]interface{}, error) {
+
+	switch t {
+	case timeType:
+		return map[string]interface{}{KEY_TYPE: TYPE_STRING, KEY_FORMAT: "date-time"}, nil
+	case jsonNumberType:
+		return map[string]interface{}{KEY_TYPE: TYPE_NUMBER}, nil
+	case jsonRawMessageType:
+		return map[string]interface{}{}, nil
+	}
+
+	if t.Kind() == reflect.Ptr {
+		schema, err := r.reflectType(t.Elem(), field)
+		if err != nil {
+			return nil, err
+		}
+		return nullableSchema(schema), nil
+	}
+
+	// types encoding themselves
+	if t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType) {
+		return map[string]interface{}{}, nil
+	}
+	if t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType) {
+		return map[string]interface{}{KEY_TYPE: TYPE_STRING}, nil
+	}
+
+	switch t.Kind() {
+
+	case reflect.Bool:
+		return map[string]interface{}{KEY_TYPE: TYPE_BOOLEAN}, nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{KEY_TYPE: TYPE_INTEGER}, nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		maximum := uint64(math.MaxUint64) >> (64 - uint(t.Bits()))
+		return map[string]interface{}{KEY_TYPE: TYPE_INTEGER, KEY_MINIMUM: 0, KEY_MAXIMUM: maximum}, nil
+
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{KEY_TYPE: TYPE_NUMBER}, nil
+
+	case reflect.String:
+		return map[string]interface{}{KEY_TYPE: TYPE_STRING}, nil
+
+	case reflect.Interface:
+		return map[string]interface{}{}, nil
+
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			// []byte is encoded in base64, nil as null
+			return nullableSchema(map[string]interface{}{KEY_TYPE: TYPE_STRING, "contentEncoding": "base64"}), nil
+		}
+		items, err := r.reflectType(t.Elem(), field+"[]")
+		if err != nil {
+			return nil, err
+		}
+		schema := map[string]interface{}{KEY_TYPE: TYPE_ARRAY, KEY_ITEMS: items}
+		if t.Kind() == reflect.Array {
+			schema[KEY_MIN_ITEMS] = t.Len()
+			schema[KEY_MAX_ITEMS] = t.Len()
+			return schema, nil
+		}
+		// nil slices are encoded as null
+		return nullableSchema(schema), nil
+
+	case reflect.Map:
+		switch t.Key().Kind() {
+		case reflect.String,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		default:
+			if !t.Key().Implements(textMarshalerType) {
+				return nil, errors.New(formatErrorDescription(
+					Locale.ReflectUnsupportedType(),
+					ErrorDetails{"type": t.String(), "field": field},
+				))
+			}
+		}
+		values, err := r.reflectType(t.Elem(), field+"[]")
+		if err != nil {
+			return nil, err
+		}
+		// nil maps are encoded as null
+		return nullableSchema(map[string]interface{}{KEY_TYPE: TYPE_OBJECT, KEY_ADDITIONAL_PROPERTIES: values}), nil
+
+	case reflect.Struct:
+		return r.reflectStruct(t, field)
+	}
+
+	// channels, functions and complex numbers
+	return nil, errors.New(formatErrorDescription(
+		Locale.ReflectUnsupportedType(),
+		ErrorDetails{"type": t.String(), "field": field},
+	))
+}
+
+// reference returns a $ref to a recursive type
+func (r *schemaReflector) reference(t reflect.Type) map[string]interface{} {
+
+	if t == r.root {
+		return map[string]interface{}{KEY_REF: "#"}
+	}
+
+	name, ok := r.names[t]
+	if !ok {
+		name = t.Name()
+		if name == "" {
+			name = "type"
+		}
+		base := name
+		for i := 2; r.isDefinitionName(name); i++ {
+			name = base + strconv.Itoa(i)
+		}
+		r.names[t] = name
+	}
+
+	return map[string]interface{}{KEY_REF: "#/" + KEY_DEFINITIONS + "/" + name}
+}
+
+func (r *schemaReflector) isDefinitionName(name string) bool {
+	for _, n := range r.names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *schemaReflector) reflectStruct(t reflect.Type, field string) (map[string]interface{}, error) {
+
+	if r.inProgress[t] || r.recursive[t] && t != r.root {
+		r.recursive[t] = true
+		return r.reference(t), nil
+	}
+
+	r.inProgress[t] = true
+	defer delete(r.inProgress, t)
+
+	properties := map[string]interface{}{}
+	required := []string{}
+
+	for _, f := range structFields(t) {
+
+		name := t.Name() + "." + f.goName
+
+		schema, err := r.reflectType(f.typ, name)
+		if err != nil {
+			return nil, err
+		}
+		if f.quoted {
+			// the ,string option quotes numbers and booleans
+			switch schema[KEY_TYPE] {
+			case TYPE_INTEGER, TYPE_NUMBER, TYPE_BOOLEAN:
+				schema = map[string]interface{}{KEY_TYPE: TYPE_STRING}
+			}
+		}
+
+		forceRequired, err := applySchemaTag(schema, f.tag, f.typ, name)
+		if err != nil {
+			return nil, err
+		}
+
+		properties[f.name] = schema
+		if (!f.omitEmpty && !f.embeddedPtr) || forceRequired {
+			required = append(required, f.name)
+		}
+	}
+
+	schema := map[string]interface{}{KEY_TYPE: TYPE_OBJECT, KEY_PROPERTIES: properties}
+	if len(required) > 0 {
+		schema[KEY_REQUIRED] = required
+	}
+
+	if r.recursive[t] && t != r.root {
+		ref := r.reference(t)
+		r.definitions[r.names[t]] = schema
+		return ref, nil
+	}
+
+	return schema, nil
+}
+
+// reflectField is a field of the JSON encoding of a struct
+type reflectField struct {
+	name   string
+	goName string
+	typ    reflect.Type
+	tag    string
+	// position of the field in the embedded structs, for the conflicts of names
+	depth       int
+	tagged      bool
+	omitEmpty   bool
+	quoted      bool
+	embeddedPtr bool
+}
+
+// structFields lists the fields encoded by encoding/json, in declaration order
+func structFields(t reflect.Type) []reflectField {
+
+	var all []reflectField
+	collectStructFields(t, 0, false, map[reflect.Type]bool{}, &all)
+
+	// a name is given by the shallowest field, by the tagged one if several are at the same depth
+	byName := map[string][]reflectField{}
+	for _, f := range all {
+		byName[f.name] = append(byName[f.name], f)
+	}
+
+	var fields []reflectField
+	for _, f := range all {
+		dominant, ok := dominantField(byName[f.name])
+		if ok && dominant.goName == f.goName && dominant.depth == f.depth {
+			fields = append(fields, f)
+		}
+	}
+
+	return fields
+}
+
+func dominantField(fields []reflectField) (reflectField, bool) {
+
+	sort.SliceStable(fields, func(i, j int) bool {
+		if fields[i].depth != fields[j].depth {
+			return fields[i].depth < fields[j].depth
+		}
+		return fields[i].tagged && !fields[j].tagged
+	})
+
+	if len(fields) > 1 && fields[0].depth == fields[1].depth && fields[0].tagged == fields[1].tagged {
+		// ambiguous names are not encoded
+		return reflectField{}, false
+	}
+	return fields[0], true
+}
+
+func collectStructFields(t reflect.Type, depth int, embeddedPtr bool, visited map[reflect.Type]bool, fields *[]reflectField) {
+
+	if visited[t] {
+		return
+	}
+	visited[t] = true
+	defer delete(visited, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		jsonTag := f.Tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+		name, options := jsonTag, ""
+		if comma := strings.Index(jsonTag, ","); comma >= 0 {
+			name, options = jsonTag[:comma], jsonTag[comma:]
+		}
+
+		if f.Anonymous {
+			ft := f.Type
+			isPtr := ft.Kind() == reflect.Ptr
+			if isPtr {
+				ft = ft.Elem()
+			}
+			if name == "" && ft.Kind() == reflect.Struct {
+				if f.PkgPath != "" && isPtr {
+					// encoding/json cannot set unexported embedded pointers
+					continue
+				}
+				collectStructFields(ft, depth+1, embeddedPtr || isPtr, visited, fields)
+				continue
+			}
+			if f.PkgPath != "" && ft.Kind() != reflect.Struct {
+				continue
+			}
+		} else if f.PkgPath != "" {
+			// unexported
+			continue
+		}
+
+		field := reflectField{
+			name:        name,
+			goName:      f.Name,
+			typ:         f.Type,
+			tag:         f.Tag.Get("jsonschema"),
+			depth:       depth,
+			tagged:      name != "",
+			omitEmpty:   strings.Contains(options+",", ",omitempty,"),
+			quoted:      strings.Contains(options+",", ",string,"),
+			embeddedPtr: embeddedPtr,
+		}
+		if field.name == "" {
+			field.name = f.Name
+		}
+
+		*fields = append(*fields, field)
+	}
+}
+
+// nullableSchema adds null to the values of a schema
+func nullableSchema(schema map[string]interface{}) map[string]interface{} {
+
+	if _, ok := schema[KEY_REF]; ok {
+		return map[string]interface{}{KEY_ANY_OF: []interface{}{schema, map[string]interface{}{KEY_TYPE: TYPE_NULL}}}
+	}
+
+	switch t := schema[KEY_TYPE].(type) {
+	case string:
+		if t != TYPE_NULL {
+			schema[KEY_TYPE] = []interface{}{t, TYPE_NULL}
+		}
+	case []interface{}:
+		if !isStringInSlice(interfacesToStrings(t), TYPE_NULL) {
+			schema[KEY_TYPE] = append(t, TYPE_NULL)
+		}
+	}
+
+	if enum, ok := schema[KEY_ENUM].([]interface{}); ok {
+		schema[KEY_ENUM] = append(enum, nil)
+	}
+
+	return schema
+}
+
+func interfacesToStrings(values []interface{}) []string {
+	var texts []string
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			texts = append(texts, s)
+		}
+	}
+	return texts
+}
+
+// Keywords of the jsonschema tag and the kind of their values
+var schemaTagKeywords = map[string]string{
+	KEY_TITLE:             "string",
+	KEY_DESCRIPTION:       "string",
+	KEY_FORMAT:            "string",
+	KEY_PATTERN:           "string",
+	KEY_MIN_LENGTH:        "integer",
+	KEY_MAX_LENGTH:        "integer",
+	KEY_MIN_ITEMS:         "integer",
+	KEY_MAX_ITEMS:         "integer",
+	KEY_MIN_PROPERTIES:    "integer",
+	KEY_MAX_PROPERTIES:    "integer",
+	KEY_MINIMUM:           "number",
+	KEY_MAXIMUM:           "number",
+	KEY_EXCLUSIVE_MINIMUM: "number",
+	KEY_EXCLUSIVE_MAXIMUM: "number",
+	KEY_MULTIPLE_OF:       "number",
+	KEY_UNIQUE_ITEMS:      "flag",
+	KEY_REQUIRED:          "flag",
+	KEY_ENUM:              "values",
+	KEY_CONST:             "value",
+	"default":             "value",
+}
+
+// applySchemaTag adds the keywords of a jsonschema tag to the schema of a field of type t.
+// It returns whether the field is required by the tag.
+func applySchemaTag(schema map[string]interface{}, tag string, t reflect.Type, field string) (bool, error) {
+
+	if tag == "" {
+		return false, nil
+	}
+
+	invalid := func(reason string) error {
+		return errors.New(formatErrorDescription(
+			Locale.ReflectInvalidTag(),
+			ErrorDetails{"field": field, "reason": reason},
+		))
+	}
+
+	// the keywords apply to the pointed values
+	target := schema
+	if anyOf, ok := schema[KEY_ANY_OF].([]interface{}); ok && t.Kind() == reflect.Ptr {
+		target = anyOf[0].(map[string]interface{})
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	required := false
+
+	for _, option := range splitSchemaTag(tag) {
+
+		key, value := option, ""
+		hasValue := false
+		if equal := strings.Index(option, "="); equal >= 0 {
+			key, value, hasValue = option[:equal], option[equal+1:], true
+		}
+
+		kind, ok := schemaTagKeywords[key]
+		if !ok {
+			return false, invalid("unknown keyword " + key)
+		}
+		if hasValue == (kind == "flag") {
+			return false, invalid("invalid value of " + key)
+		}
+
+		switch kind {
+
+		case "string":
+			target[key] = value
+
+		case "integer":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return false, invalid(key + " must be a non-negative integer")
+			}
+			target[key] = n
+
+		case "number":
+			if _, ok := new(big.Rat).SetString(value); !ok {
+				return false, invalid(key + " must be a number")
+			}
+			target[key] = json.Number(value)
+
+		case "flag":
+			if key == KEY_REQUIRED {
+				required = true
+			} else {
+				target[key] = true
+			}
+
+		case "value", "values":
+			var values []interface{}
+			for _, text := range strings.Split(value, "|") {
+				v, err := tagValue(text, t)
+				if err != nil {
+					return false, invalid(err.Error())
+				}
+				values = append(values, v)
+				if kind == "value" {
+					break
+				}
+			}
+			if kind == "value" {
+				target[key] = values[0]
+				break
+			}
+			if nullable, ok := target[KEY_TYPE].([]interface{}); ok && isStringInSlice(interfacesToStrings(nullable), TYPE_NULL) {
+				values = append(values, nil)
+			}
+			target[key] = values
+		}
+	}
+
+	return required, nil
+}
+
+// splitSchemaTag splits a jsonschema tag on commas, \, escaping a comma
+func splitSchemaTag(tag string) []string {
+	var options []string
+	var current strings.Builder
+	for i := 0; i < len(tag); i++ {
+		switch {
+		case tag[i] == '\\' && i+1 < len(tag) && tag[i+1] == ',':
+			current.WriteByte(',')
+			i++
+		case tag[i] == ',':
+			options = append(options, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(tag[i])
+		}
+	}
+	return append(options, current.String())
+}
+
+// tagValue converts the text of a value of a jsonschema tag to the JSON type of t
+func tagValue(text string, t reflect.Type) (interface{}, error) {
+
+	switch t.Kind() {
+
+	case reflect.Bool:
+		return strconv.ParseBool(text)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		_, err := strconv.ParseInt(text, 10, 64)
+		return json.Number(text), err
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		_, err := strconv.ParseUint(text, 10, 64)
+		return json.Number(text), err
+
+	case reflect.Float32, reflect.Float64:
+		_, err := strconv.ParseFloat(text, 64)
+		return json.Number(text), err
+
+	case reflect.String:
+		if t == jsonNumberType {
+			if _, ok := new(big.Rat).SetString(text); !ok {
+				return nil, errors.New(text + " is not a number")
+			}
+			return json.Number(text), nil
+		}
+		return text, nil
+	}
+
+	// other values are given in JSON
+	return decodeJsonUsingNumber(strings.NewReader(text))
+}
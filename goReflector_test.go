@@ -0,0 +1,228 @@
+package gojsonschema
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type reflectedBase struct {
+	ID      string    `json:"id" jsonschema:"format=uuid"`
+	Created time.Time `json:"created"`
+}
+
+type reflectedUser struct {
+	reflectedBase
+	Name     string            `json:"name" jsonschema:"minLength=3,maxLength=20"`
+	Role     string            `json:"role,omitempty" jsonschema:"enum=admin|user,required"`
+	Email    *string           `json:"email"`
+	Age      uint8             `json:"age,omitempty" jsonschema:"maximum=150"`
+	Score    float64           `json:"score,string"`
+	Tags     []string          `json:"tags,omitempty" jsonschema:"uniqueItems"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Avatar   []byte            `json:"avatar,omitempty"`
+	Level    *int              `json:"level,omitempty" jsonschema:"enum=1|2|3"`
+	Extra    interface{}       `json:"extra,omitempty"`
+	Pattern  string            `json:"pattern,omitempty" jsonschema:"pattern=^a{1\\,3}$"`
+	Ignored  string            `json:"-"`
+	internal string
+}
+
+type reflectedNode struct {
+	Value    int              `json:"value"`
+	Children []*reflectedNode `json:"children,omitempty"`
+}
+
+type reflectedTree struct {
+	Root  *reflectedNode `json:"root"`
+	Other reflectedNode  `json:"other"`
+}
+
+func reflectJSON(t *testing.T, v interface{}) string {
+	schema, err := ReflectSchema(v)
+	if !assert.Nil(t, err) {
+		return ""
+	}
+	text, err := json.Marshal(schema)
+	assert.Nil(t, err)
+	return string(text)
+}
+
+func TestReflectSchema(t *testing.T) {
+	schema, err := ReflectSchema(&reflectedUser{})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Equal(t, draft07SchemaURI, schema[KEY_SCHEMA])
+	assert.Equal(t, []string{"id", "created", "name", "role", "email", "score"}, schema[KEY_REQUIRED])
+
+	properties := schema[KEY_PROPERTIES].(map[string]interface{})
+	assert.Len(t, properties, 13)
+	assert.Equal(t, map[string]interface{}{"type": "string", "format": "uuid"}, properties["id"])
+	assert.Equal(t, map[string]interface{}{"type": "string", "format": "date-time"}, properties["created"])
+	assert.Equal(t, map[string]interface{}{"type": "string", "minLength": 3, "maxLength": 20}, properties["name"])
+	assert.Equal(t, map[string]interface{}{"type": "string", "enum": []interface{}{"admin", "user"}}, properties["role"])
+	assert.Equal(t, map[string]interface{}{"type": []interface{}{"string", "null"}}, properties["email"])
+	assert.Equal(t, map[string]interface{}{"type": "integer", "minimum": 0, "maximum": json.Number("150")}, properties["age"])
+	assert.Equal(t, map[string]interface{}{"type": "string"}, properties["score"])
+	assert.Equal(t, map[string]interface{}{"type": []interface{}{"array", "null"}, "items": map[string]interface{}{"type": "string"}, "uniqueItems": true}, properties["tags"])
+	assert.Equal(t, map[string]interface{}{"type": []interface{}{"object", "null"}, "additionalProperties": map[string]interface{}{"type": "string"}}, properties["labels"])
+	assert.Equal(t, map[string]interface{}{"type": []interface{}{"string", "null"}, "contentEncoding": "base64"}, properties["avatar"])
+	assert.Equal(t, map[string]interface{}{"type": []interface{}{"integer", "null"}, "enum": []interface{}{json.Number("1"), json.Number("2"), json.Number("3"), nil}}, properties["level"])
+	assert.Equal(t, map[string]interface{}{}, properties["extra"])
+	assert.Equal(t, map[string]interface{}{"type": "string", "pattern": "^a{1,3}$"}, properties["pattern"])
+
+	s, err := NewSchema(NewGoLoader(schema))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	email := "jane@example.com"
+	level := 2
+	user := reflectedUser{
+		reflectedBase: reflectedBase{ID: "0b5c3c2e-8e1c-4e0e-9a4f-0b0a8f8e5e8a", Created: time.Now()},
+		Name:          "jane",
+		Role:          "admin",
+		Email:         &email,
+		Score:         1.5,
+		Level:         &level,
+	}
+	result, err := s.Validate(NewGoLoader(user))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid(), "%v", result.Errors())
+
+	user.Name = "jo"
+	user.Role = ""
+	result, err = s.Validate(NewGoLoader(user))
+	assert.Nil(t, err)
+	assert.Len(t, result.Errors(), 2)
+}
+
+func TestReflectSchemaRecursive(t *testing.T) {
+	assert.Equal(t,
+		`{"$schema":"http://json-schema.org/draft-07/schema#","properties":{"children":{"items":{"anyOf":[{"$ref":"#"},{"type":"null"}]},"type":["array","null"]},"value":{"type":"integer"}},"required":["value"],"type":"object"}`,
+		reflectJSON(t, reflectedNode{}),
+	)
+
+	schema, err := ReflectSchema(reflect.TypeOf(reflectedTree{}))
+	if !assert.Nil(t, err) {
+		return
+	}
+	text, _ := json.Marshal(schema)
+	assert.Equal(t,
+		`{"$schema":"http://json-schema.org/draft-07/schema#","definitions":{"reflectedNode":{"properties":{"children":{"items":{"anyOf":[{"$ref":"#/definitions/reflectedNode"},{"type":"null"}]},"type":["array","null"]},"value":{"type":"integer"}},"required":["value"],"type":"object"}},"properties":{"other":{"$ref":"#/definitions/reflectedNode"},"root":{"anyOf":[{"$ref":"#/definitions/reflectedNode"},{"type":"null"}]}},"required":["root","other"],"type":"object"}`,
+		string(text),
+	)
+
+	s, err := NewSchema(NewGoLoader(schema))
+	if !assert.Nil(t, err) {
+		return
+	}
+	tree := reflectedTree{Root: &reflectedNode{Value: 1, Children: []*reflectedNode{{Value: 2}}}}
+	result, err := s.Validate(NewGoLoader(tree))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid(), "%v", result.Errors())
+	result, err = s.Validate(NewStringLoader(`{"root": {"children": [{"value": "x"}]}, "other": {"value": 1}}`))
+	assert.Nil(t, err)
+	assert.False(t, result.Valid())
+}
+
+func TestReflectSchemaEmbedded(t *testing.T) {
+	type Inner struct {
+		A string `json:"a"`
+		B string `json:"b"`
+	}
+	type Pointed struct {
+		C string `json:"c"`
+	}
+	type Outer struct {
+		Inner
+		*Pointed
+		B    int   `json:"b"`
+		Name Inner `json:"name"`
+	}
+
+	assert.Equal(t,
+		`{"$schema":"http://json-schema.org/draft-07/schema#","properties":{"a":{"type":"string"},"b":{"type":"integer"},"c":{"type":"string"},"name":{"properties":{"a":{"type":"string"},"b":{"type":"string"}},"required":["a","b"],"type":"object"}},"required":["a","b","name"],"type":"object"}`,
+		reflectJSON(t, Outer{}),
+	)
+}
+
+func TestReflectSchemaMarshaled(t *testing.T) {
+	type Marshaled struct {
+		S     []string          `json:"s"`
+		M     map[string]int    `json:"m"`
+		B     []byte            `json:"b"`
+		Grid  [][]int           `json:"grid"`
+		Small uint8             `json:"small"`
+		Big   uint64            `json:"big"`
+		Named map[string][]byte `json:"named,omitempty"`
+	}
+
+	schema, err := ReflectSchema(Marshaled{})
+	if !assert.Nil(t, err) {
+		return
+	}
+	s, err := NewSchema(NewGoLoader(schema))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// the zero value and a filled one validate their own schema once encoded
+	for _, value := range []Marshaled{
+		{},
+		{
+			S: []string{"a"}, M: map[string]int{"a": 1}, B: []byte("hi"), Grid: [][]int{nil, {1}},
+			Small: math.MaxUint8, Big: math.MaxUint64, Named: map[string][]byte{"a": nil},
+		},
+	} {
+		text, err := json.Marshal(value)
+		if !assert.Nil(t, err) {
+			continue
+		}
+		result, err := s.Validate(NewBytesLoader(text))
+		assert.Nil(t, err)
+		assert.True(t, result.Valid(), "%s %v", text, result.Errors())
+	}
+
+	result, err := s.Validate(NewStringLoader(`{"s": null, "m": null, "b": null, "grid": null, "small": 256, "big": 18446744073709551616}`))
+	assert.Nil(t, err)
+	var types []string
+	for _, resultError := range result.Errors() {
+		types = append(types, resultError.Field()+" "+resultError.Type())
+	}
+	assert.ElementsMatch(t, []string{"small number_lte", "big number_lte"}, types)
+}
+
+func TestReflectSchemaErrors(t *testing.T) {
+	testCases := []interface{}{
+		struct{ C chan int }{},
+		struct{ F func() }{},
+		map[[2]int]string{},
+		struct {
+			A string `jsonschema:"unknown=1"`
+		}{},
+		struct {
+			A string `jsonschema:"minLength=-1"`
+		}{},
+		struct {
+			A string `jsonschema:"minimum=x"`
+		}{},
+		struct {
+			A string `jsonschema:"required=yes"`
+		}{},
+		struct {
+			A int `jsonschema:"enum=1|x"`
+		}{},
+	}
+
+	for _, testCase := range testCases {
+		_, err := ReflectSchema(testCase)
+		assert.NotNil(t, err, "%T", testCase)
+	}
+}
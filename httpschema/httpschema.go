@@ -0,0 +1,188 @@
+// Package httpschema validates the JSON bodies of HTTP requests against schemas, as a net/http
+// middleware answering the invalid ones with RFC 7807 problem details.
+//
+//	validator := &httpschema.Validator{
+//		Routes: map[string]*gojsonschema.Schema{"POST /orders": orderSchema},
+//	}
+//	http.Handle("/", validator.Handler(mux))
+//
+// The handlers read the validated body from the request context with DocumentFromContext.
+package httpschema
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// DefaultMaxBodySize is the size of the bodies accepted when Validator.MaxBodySize is 0, 1 MiB
+const DefaultMaxBodySize = 1 << 20
+
+// ProblemContentType is the media type of the problem details
+const ProblemContentType = "application/problem+json"
+
+// Validator validates the bodies of the requests against the schema of their route or content type
+type Validator struct {
+	// Routes selects the schema of a request by method and path, "POST /orders", or by path, "/orders"
+	Routes map[string]*gojsonschema.Schema
+	// ContentTypes selects the schema of a request by media type, "application/vnd.order+json",
+	// when no route does
+	ContentTypes map[string]*gojsonschema.Schema
+	// Schema of the requests neither a route nor a content type selects one for. When nil,
+	// these requests are passed on without validation
+	Schema *gojsonschema.Schema
+	// MaxBodySize in bytes, DefaultMaxBodySize when 0
+	MaxBodySize int64
+	// Options of the validation : coercion, removal of the additional properties and locale of the errors
+	Options gojsonschema.ValidateOptions
+}
+
+// Problem is an RFC 7807 problem detail, with the errors of the validation
+type Problem struct {
+	Type   string         `json:"type"`
+	Title  string         `json:"title"`
+	Status int            `json:"status"`
+	Detail string         `json:"detail,omitempty"`
+	Errors []ProblemError `json:"errors,omitempty"`
+}
+
+// ProblemError is an error of the validation of the body
+type ProblemError struct {
+	// Pointer is the JSON pointer to the invalid value, or to the missing or additional property
+	Pointer string `json:"pointer"`
+	Field   string `json:"field"`
+	Type    string `json:"type"`
+	Detail  string `json:"detail"`
+}
+
+type contextKey struct{}
+
+// DocumentFromContext returns the body of the request validated by a Validator, coerced and
+// pruned according to its options
+func DocumentFromContext(ctx context.Context) (interface{}, bool) {
+	document, ok := ctx.Value(contextKey{}).(*validatedDocument)
+	if !ok {
+		return nil, false
+	}
+	return document.value, true
+}
+
+// validatedDocument wraps the document in the context, as a nil document is valid
+type validatedDocument struct {
+	value interface{}
+}
+
+// Handler returns a handler validating the bodies of the requests before passing them to next.
+// The invalid requests are answered with a 400 Bad Request, the bodies larger than MaxBodySize with
+// a 413 Request Entity Too Large. The body next reads is the validated document
+func (v *Validator) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		schema := v.schema(r)
+		if schema == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		maxBodySize := v.MaxBodySize
+		if maxBodySize == 0 {
+			maxBodySize = DefaultMaxBodySize
+		}
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+		if err != nil {
+			if int64(len(body)) >= maxBodySize {
+				WriteProblem(w, &Problem{
+					Title:  "Request body too large",
+					Status: http.StatusRequestEntityTooLarge,
+					Detail: "The request body exceeds " + strconv.FormatInt(maxBodySize, 10) + " bytes",
+				})
+				return
+			}
+			WriteProblem(w, &Problem{Title: "Unreadable request body", Status: http.StatusBadRequest, Detail: err.Error()})
+			return
+		}
+
+		result, err := schema.ValidateWithOptions(gojsonschema.NewBytesLoader(body), v.Options)
+		if err != nil {
+			WriteProblem(w, &Problem{Title: "Malformed request body", Status: http.StatusBadRequest, Detail: err.Error()})
+			return
+		}
+		if !result.Valid() {
+			WriteProblem(w, NewProblem(result))
+			return
+		}
+
+		if len(result.Coercions()) > 0 || len(result.RemovedProperties()) > 0 {
+			if body, err = json.Marshal(result.Document()); err != nil {
+				WriteProblem(w, &Problem{Title: "Internal error", Status: http.StatusInternalServerError, Detail: err.Error()})
+				return
+			}
+		}
+		r = r.WithContext(context.WithValue(r.Context(), contextKey{}, &validatedDocument{result.Document()}))
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		r.ContentLength = int64(len(body))
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// schema returns the schema validating the body of a request, nil when none does
+func (v *Validator) schema(r *http.Request) *gojsonschema.Schema {
+
+	if schema, ok := v.Routes[r.Method+" "+r.URL.Path]; ok {
+		return schema
+	}
+	if schema, ok := v.Routes[r.URL.Path]; ok {
+		return schema
+	}
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err == nil {
+		if schema, ok := v.ContentTypes[mediaType]; ok {
+			return schema
+		}
+	}
+	return v.Schema
+}
+
+// NewProblem returns the 400 Bad Request problem of an invalid result
+func NewProblem(result *gojsonschema.Result) *Problem {
+
+	problem := &Problem{
+		Title:  "Invalid request body",
+		Status: http.StatusBadRequest,
+		Detail: "The request body does not match the schema",
+	}
+	for _, err := range result.Errors() {
+		pointer := err.Context().JSONPointer()
+		if property, ok := err.Details()["property"].(string); ok {
+			pointer += "/" + jsonPointerToken(property)
+		}
+		problem.Errors = append(problem.Errors, ProblemError{
+			Pointer: pointer,
+			Field:   err.Field(),
+			Type:    err.Type(),
+			Detail:  err.Description(),
+		})
+	}
+	return problem
+}
+
+// WriteProblem writes a problem as application/problem+json, its type defaulting to about:blank
+func WriteProblem(w http.ResponseWriter, problem *Problem) {
+	if problem.Type == "" {
+		problem.Type = "about:blank"
+	}
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(problem.Status)
+	json.NewEncoder(w).Encode(problem)
+}
+
+func jsonPointerToken(key string) string {
+	return strings.Replace(strings.Replace(key, "~", "~0", -1), "/", "~1", -1)
+}
@@ -0,0 +1,124 @@
+package httpschema
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func mustSchema(t *testing.T, schema string) *gojsonschema.Schema {
+	s, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(schema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// echo answers with the document of the context and the body it reads
+var echo = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	document, ok := DocumentFromContext(r.Context())
+	body, _ := ioutil.ReadAll(r.Body)
+	json.NewEncoder(w).Encode(map[string]interface{}{"validated": ok, "document": document, "body": string(body)})
+})
+
+func serve(handler http.Handler, method string, path string, contentType string, body string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	var response map[string]interface{}
+	json.Unmarshal(recorder.Body.Bytes(), &response)
+	return recorder, response
+}
+
+func TestHandler(t *testing.T) {
+	orderSchema := mustSchema(t, `{
+		"type": "object",
+		"properties": {
+			"sku": {"type": "string"},
+			"quantity": {"type": "integer", "minimum": 1},
+			"lines": {"type": "array", "items": {"properties": {"a/b": {"type": "string"}}}}
+		},
+		"required": ["sku", "quantity"],
+		"additionalProperties": false
+	}`)
+	handler := (&Validator{
+		Routes:       map[string]*gojsonschema.Schema{"POST /orders": orderSchema},
+		ContentTypes: map[string]*gojsonschema.Schema{"application/vnd.note+json": mustSchema(t, `{"type": "string"}`)},
+	}).Handler(echo)
+
+	recorder, response := serve(handler, "POST", "/orders", "application/json", `{"sku": "A1", "quantity": 2}`)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, true, response["validated"])
+	assert.Equal(t, map[string]interface{}{"sku": "A1", "quantity": 2.0}, response["document"])
+	assert.Equal(t, `{"sku": "A1", "quantity": 2}`, response["body"])
+
+	recorder, response = serve(handler, "POST", "/orders", "application/json", `{"quantity": 0, "lines": [{"a/b": 1}], "note": "x"}`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, ProblemContentType, recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "about:blank", response["type"])
+	assert.Equal(t, "Invalid request body", response["title"])
+	assert.Equal(t, 400.0, response["status"])
+
+	var pointers []string
+	for _, e := range response["errors"].([]interface{}) {
+		problemError := e.(map[string]interface{})
+		pointers = append(pointers, problemError["pointer"].(string)+" "+problemError["type"].(string))
+		assert.NotEmpty(t, problemError["detail"])
+	}
+	assert.ElementsMatch(t, []string{
+		"/sku required",
+		"/quantity number_gte",
+		"/lines/0/a~1b invalid_type",
+		"/note additional_property_not_allowed",
+	}, pointers)
+
+	recorder, response = serve(handler, "POST", "/orders", "application/json", `{"sku": `)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, "Malformed request body", response["title"])
+
+	// by content type
+	recorder, _ = serve(handler, "PUT", "/notes/1", "application/vnd.note+json; charset=utf-8", `12`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	recorder, response = serve(handler, "PUT", "/notes/1", "application/vnd.note+json", `"hello"`)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "hello", response["document"])
+
+	// the requests without schema are passed on
+	recorder, response = serve(handler, "GET", "/orders", "", ``)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, false, response["validated"])
+}
+
+func TestHandlerOptions(t *testing.T) {
+	handler := (&Validator{
+		Schema: mustSchema(t, `{
+			"properties": {"page": {"type": "integer"}},
+			"additionalProperties": false
+		}`),
+		MaxBodySize: 32,
+		Options: gojsonschema.ValidateOptions{
+			Coerce:           true,
+			RemoveAdditional: gojsonschema.RemoveAdditionalFalse,
+		},
+	}).Handler(echo)
+
+	// the handler reads the coerced and pruned document
+	recorder, response := serve(handler, "POST", "/search", "application/json", `{"page": "2", "debug": true}`)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, map[string]interface{}{"page": 2.0}, response["document"])
+	assert.Equal(t, `{"page":2}`, response["body"])
+
+	recorder, response = serve(handler, "POST", "/search", "application/json", `{"page": 1, "query": "`+strings.Repeat("a", 32)+`"}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+	assert.Equal(t, "The request body exceeds 32 bytes", response["detail"])
+}
@@ -70,3 +70,11 @@ func (c *JsonContext) writeStringToBuffer(buf *bytes.Buffer, del []string) {
 
 	buf.WriteString(c.head)
 }
+
+// JSONPointer returns the RFC 6901 JSON pointer of the context in the document, "" for the root
+func (c *JsonContext) JSONPointer() string {
+	if c == nil || c.tail == nil {
+		return ""
+	}
+	return c.tail.JSONPointer() + "/" + jsonPointerToken(c.head)
+}
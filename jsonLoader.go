@@ -183,7 +183,11 @@ func (l *jsonReferenceLoader) loadFromHTTP(address string) (interface{}, error)
 		return nil, err
 	}
 
-	return decodeJsonUsingNumber(bytes.NewReader(bodyBuff))
+	if strings.Contains(resp.Header.Get("Content-Type"), "yaml") {
+		return decodeYAML(bytes.NewReader(bodyBuff))
+	}
+
+	return decodeReferencedDocument(resp.Request.URL.Path, bodyBuff)
 
 }
 
@@ -199,7 +203,7 @@ func (l *jsonReferenceLoader) loadFromFile(path string) (interface{}, error) {
 		return nil, err
 	}
 
-	return decodeJsonUsingNumber(bytes.NewReader(bodyBuff))
+	return decodeReferencedDocument(path, bodyBuff)
 
 }
 
@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -47,12 +48,13 @@ func TestSuite(t *testing.T) {
 	}
 	wd = filepath.Join(wd, "testdata")
 
-	go func() {
-		err := http.ListenAndServe(":1234", http.FileServer(http.Dir(filepath.Join(wd, "remotes"))))
-		if err != nil {
-			panic(err.Error())
-		}
-	}()
+	// listen before running the tests, the meta-schemas being embedded they do not wait for the network anymore
+	listener, err := net.Listen("tcp", ":1234")
+	if err != nil {
+		panic(err.Error())
+	}
+	defer listener.Close()
+	go http.Serve(listener, http.FileServer(http.Dir(filepath.Join(wd, "remotes"))))
 
 	testDirectories := []string{"draft4", "draft6", "draft7"}
 
@@ -131,3 +133,42 @@ func TestSuite(t *testing.T) {
 		}
 	}
 }
+
+// testOptionalSuite runs one of the optional draft-07 test files, which are not part of TestSuite
+func testOptionalSuite(t *testing.T, name string) {
+	testSuiteFile(t, NewSchemaLoader(), filepath.Join("testdata", "draft7", "optional", name))
+}
+
+// testSuiteFile runs a test file of the suite, compiling its schemas with sl
+func testSuiteFile(t *testing.T, sl *SchemaLoader, path string) {
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var tests []jsonSchemaTest
+	d := json.NewDecoder(file)
+	d.UseNumber()
+	if err := d.Decode(&tests); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range tests {
+		testSchema, err := sl.Compile(NewRawLoader(test.Schema))
+		if err != nil {
+			t.Errorf("Error (%s)\n", err.Error())
+			continue
+		}
+		for _, testCase := range test.Tests {
+			result, err := testSchema.Validate(NewRawLoader(testCase.Data))
+			if err != nil {
+				t.Errorf("Error (%s)\n", err.Error())
+				continue
+			}
+			if result.Valid() != testCase.Valid {
+				t.Errorf("Test failed : %s\n%s.\n%s.\nexpects: %t, given %t\n", path, test.Description, testCase.Description, testCase.Valid, result.Valid())
+			}
+		}
+	}
+}
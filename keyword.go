@@ -0,0 +1,81 @@
+package gojsonschema
+
+import (
+	"errors"
+)
+
+// Keyword is a custom keyword of the schemas compiled by a SchemaLoader, registered with AddKeyword
+type Keyword interface {
+	// Parse checks and compiles the value of the keyword in a schema, the other keywords of
+	// the schema being given. The returned value is the one passed to Validate.
+	Parse(value interface{}, schema map[string]interface{}) (interface{}, error)
+	// Validate checks a value of the document against the compiled keyword, adding its errors,
+	// custom ResultError types embedding ResultErrorFields, to result with AddError
+	Validate(compiled interface{}, value interface{}, context *JsonContext, result *Result)
+}
+
+// The keywords of the drafts, that cannot be redefined
+var standardKeywords = []string{
+	KEY_SCHEMA, KEY_ID, KEY_ID_NEW, KEY_REF, KEY_TITLE, KEY_DESCRIPTION, KEY_TYPE,
+	KEY_ITEMS, KEY_ADDITIONAL_ITEMS, KEY_PROPERTIES, KEY_PATTERN_PROPERTIES, KEY_ADDITIONAL_PROPERTIES,
+	KEY_PROPERTY_NAMES, KEY_DEFINITIONS, KEY_MULTIPLE_OF, KEY_MINIMUM, KEY_MAXIMUM,
+	KEY_EXCLUSIVE_MINIMUM, KEY_EXCLUSIVE_MAXIMUM, KEY_MIN_LENGTH, KEY_MAX_LENGTH, KEY_PATTERN,
+	KEY_FORMAT, KEY_MIN_PROPERTIES, KEY_MAX_PROPERTIES, KEY_DEPENDENCIES, KEY_REQUIRED,
+	KEY_MIN_ITEMS, KEY_MAX_ITEMS, KEY_UNIQUE_ITEMS, KEY_CONTAINS, KEY_CONST, KEY_ENUM,
+	KEY_ONE_OF, KEY_ANY_OF, KEY_ALL_OF, KEY_NOT, KEY_IF, KEY_THEN, KEY_ELSE, KEY_BSON_TYPE,
+	KEY_ERROR_MESSAGE,
+}
+
+// AddKeyword registers a custom keyword for the schemas compiled afterwards by the SchemaLoader.
+// Registering a name again replaces its keyword, the keywords of the drafts cannot be redefined.
+func (sl *SchemaLoader) AddKeyword(name string, keyword Keyword) error {
+
+	if name == "" || isStringInSlice(standardKeywords, name) {
+		return errors.New(formatErrorDescription(
+			Locale.KeywordReserved(),
+			ErrorDetails{"keyword": name},
+		))
+	}
+
+	if sl.keywords == nil {
+		sl.keywords = make(map[string]Keyword)
+	}
+	sl.keywords[name] = keyword
+
+	return nil
+}
+
+// customKeyword is a custom keyword of a subschema and its compiled value
+type customKeyword struct {
+	name     string
+	keyword  Keyword
+	compiled interface{}
+}
+
+// parseKeywords compiles the custom keywords of a schema, in the order of their names
+func (d *Schema) parseKeywords(m map[string]interface{}, currentSchema *subSchema) error {
+
+	for _, name := range sortedMapKeys(d.keywords) {
+		if !existsMapKey(m, name) {
+			continue
+		}
+		keyword := d.keywords[name]
+		compiled, err := keyword.Parse(m[name], m)
+		if err != nil {
+			return errors.New(formatErrorDescription(
+				Locale.KeywordInvalid(),
+				ErrorDetails{"keyword": name, "error": err},
+			))
+		}
+		currentSchema.keywords = append(currentSchema.keywords, customKeyword{name: name, keyword: keyword, compiled: compiled})
+	}
+
+	return nil
+}
+
+// validateKeywords checks a value against the custom keywords of a subschema
+func (v *subSchema) validateKeywords(currentSubSchema *subSchema, value interface{}, result *Result, context *JsonContext) {
+	for _, k := range currentSubSchema.keywords {
+		k.keyword.Validate(k.compiled, value, context, result)
+	}
+}
@@ -0,0 +1,145 @@
+package gojsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type duplicateKeyError struct {
+	ResultErrorFields
+}
+
+// uniqueBy checks that the objects of an array have different values for a property
+type uniqueBy struct{}
+
+func (uniqueBy) Parse(value interface{}, schema map[string]interface{}) (interface{}, error) {
+	property, ok := value.(string)
+	if !ok {
+		return nil, errors.New("must be a string")
+	}
+	if schema[KEY_TYPE] != TYPE_ARRAY {
+		return nil, errors.New("only applies to arrays")
+	}
+	return property, nil
+}
+
+func (uniqueBy) Validate(compiled interface{}, value interface{}, context *JsonContext, result *Result) {
+	items, ok := value.([]interface{})
+	if !ok {
+		return
+	}
+	property := compiled.(string)
+	seen := map[string]bool{}
+	for i, item := range items {
+		key := jsonValueTextOrEmpty(item.(map[string]interface{})[property])
+		if seen[key] {
+			err := &duplicateKeyError{}
+			err.SetContext(NewJsonContext(strconv.Itoa(i), context))
+			err.SetType("duplicate_key")
+			err.SetDescriptionFormat("Duplicate {{.name}} {{.key}}")
+			err.SetValue(item)
+			details := ErrorDetails{"name": property, "key": key}
+			err.SetDetails(details)
+			result.AddError(err, details)
+		}
+		seen[key] = true
+	}
+}
+
+func jsonValueTextOrEmpty(value interface{}) string {
+	text, _ := jsonValueText(value)
+	return text
+}
+
+// sumEquals checks that the numbers of an array add up to a value
+type sumEquals struct{}
+
+func (sumEquals) Parse(value interface{}, schema map[string]interface{}) (interface{}, error) {
+	n, ok := value.(json.Number)
+	if !ok {
+		return nil, errors.New("must be a number")
+	}
+	return n.Float64()
+}
+
+func (sumEquals) Validate(compiled interface{}, value interface{}, context *JsonContext, result *Result) {
+	items, ok := value.([]interface{})
+	if !ok {
+		return
+	}
+	sum := 0.0
+	for _, item := range items {
+		if n, ok := item.(json.Number); ok {
+			f, _ := n.Float64()
+			sum += f
+		}
+	}
+	if sum != compiled.(float64) {
+		err := &duplicateKeyError{}
+		err.SetContext(context)
+		err.SetType("sum_not_equal")
+		err.SetDescriptionFormat("Sum must be {{.sum}}")
+		err.SetValue(value)
+		details := ErrorDetails{"sum": compiled}
+		err.SetDetails(details)
+		result.AddError(err, details)
+	}
+}
+
+func TestKeyword(t *testing.T) {
+	sl := NewSchemaLoader()
+	assert.Nil(t, sl.AddKeyword("x-unique-by", uniqueBy{}))
+	assert.Nil(t, sl.AddKeyword("x-sum-equals", sumEquals{}))
+
+	schema, err := sl.Compile(NewStringLoader(`{
+		"properties": {
+			"users": {"type": "array", "x-unique-by": "id"},
+			"shares": {"$ref": "#/definitions/shares"}
+		},
+		"definitions": {
+			"shares": {"type": "array", "items": {"type": "number"}, "x-sum-equals": 100}
+		}
+	}`))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	result, err := schema.Validate(NewStringLoader(`{"users": [{"id": 1}, {"id": 2}], "shares": [60, 40]}`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	result, err = schema.Validate(NewStringLoader(`{"users": [{"id": 1}, {"id": 2}, {"id": 1}], "shares": [60, 30]}`))
+	assert.Nil(t, err)
+	var errorsFound []string
+	for _, resultError := range result.Errors() {
+		errorsFound = append(errorsFound, resultError.Type()+" "+resultError.Field()+": "+resultError.Description())
+	}
+	assert.ElementsMatch(t, []string{
+		"duplicate_key users.2: Duplicate id 1",
+		"sum_not_equal shares: Sum must be 100",
+	}, errorsFound)
+
+	// keywords only apply to the schemas of the loader registering them
+	schema, err = NewSchema(NewStringLoader(`{"type": "array", "x-unique-by": "id"}`))
+	if !assert.Nil(t, err) {
+		return
+	}
+	result, err = schema.Validate(NewStringLoader(`[{"id": 1}, {"id": 1}]`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+}
+
+func TestKeywordErrors(t *testing.T) {
+	sl := NewSchemaLoader()
+	assert.EqualError(t, sl.AddKeyword("minimum", sumEquals{}), "minimum is a keyword of JSON Schema and cannot be registered")
+	assert.Nil(t, sl.AddKeyword("x-unique-by", uniqueBy{}))
+
+	_, err := sl.Compile(NewStringLoader(`{"type": "object", "x-unique-by": "id"}`))
+	assert.EqualError(t, err, "Invalid x-unique-by: only applies to arrays")
+	_, err = sl.Compile(NewStringLoader(`{"items": {"type": "array", "x-unique-by": 1}}`))
+	assert.EqualError(t, err, "Invalid x-unique-by: must be a string")
+}
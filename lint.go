@@ -0,0 +1,396 @@
+package gojsonschema
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Schema linting
+// Lint reports constructs that are legal but most likely mistakes: constraints that can never
+// apply or never be satisfied, values that do not validate against their own schema and
+// definitions nobody references. The compiled subschemas are walked along their document,
+// which gives the JSON pointers and the keywords the compilation does not keep ( default, examples ).
+
+// Types of lint issues
+const (
+	LINT_REQUIRED_NOT_ALLOWED   = "required_not_allowed"
+	LINT_KEYWORD_NOT_APPLICABLE = "keyword_not_applicable"
+	LINT_INVALID_ENUM_VALUE     = "invalid_enum_value"
+	LINT_INVALID_DEFAULT        = "invalid_default"
+	LINT_INVALID_EXAMPLE        = "invalid_example"
+	LINT_EMPTY_BRANCH           = "empty_branch"
+	LINT_OVERLAPPING_BRANCHES   = "overlapping_branches"
+	LINT_UNUSED_DEFINITION      = "unused_definition"
+)
+
+const (
+	KEY_DEFAULT  = "default"
+	KEY_EXAMPLES = "examples"
+)
+
+// LintIssue is a suspicious construct of a schema
+type LintIssue struct {
+	// Type of the issue, one of the LINT_ constants
+	Type string
+	// Pointer is the JSON pointer of the subschema, #/properties/name for instance
+	Pointer string
+	// Keyword the issue is about
+	Keyword     string
+	Description string
+	Details     ErrorDetails
+}
+
+func (i LintIssue) String() string {
+	return i.Pointer + ": " + i.Description
+}
+
+// Keywords applying to a single kind of value, and the types they apply to
+var typeSpecificKeywords = []struct {
+	keywords []string
+	types    []string
+}{
+	{[]string{KEY_MIN_LENGTH, KEY_MAX_LENGTH, KEY_PATTERN}, []string{TYPE_STRING}},
+	{[]string{KEY_MULTIPLE_OF, KEY_MINIMUM, KEY_MAXIMUM, KEY_EXCLUSIVE_MINIMUM, KEY_EXCLUSIVE_MAXIMUM}, []string{TYPE_NUMBER, TYPE_INTEGER}},
+	{[]string{KEY_PROPERTIES, KEY_PATTERN_PROPERTIES, KEY_ADDITIONAL_PROPERTIES, KEY_REQUIRED, KEY_MIN_PROPERTIES, KEY_MAX_PROPERTIES, KEY_DEPENDENCIES, KEY_PROPERTY_NAMES}, []string{TYPE_OBJECT}},
+	{[]string{KEY_ITEMS, KEY_ADDITIONAL_ITEMS, KEY_MIN_ITEMS, KEY_MAX_ITEMS, KEY_UNIQUE_ITEMS, KEY_CONTAINS}, []string{TYPE_ARRAY}},
+}
+
+// Lint analyses a compiled schema, issues are sorted by pointer
+func Lint(schema *Schema) []LintIssue {
+
+	l := &linter{definitions: map[*subSchema]string{}}
+	l.walk(schema.rootSchema, schema.pool.GetStandaloneDocument(), "#")
+	l.lintDefinitions(schema.rootSchema)
+
+	sort.SliceStable(l.issues, func(i, j int) bool {
+		return l.issues[i].Pointer < l.issues[j].Pointer
+	})
+
+	return l.issues
+}
+
+type linter struct {
+	issues []LintIssue
+	// definitions and their pointers
+	definitions map[*subSchema]string
+}
+
+func (l *linter) report(issueType string, pointer string, keyword string, format string, details ErrorDetails) {
+	l.issues = append(l.issues, LintIssue{
+		Type:        issueType,
+		Pointer:     pointer,
+		Keyword:     keyword,
+		Description: formatErrorDescription(format, details),
+		Details:     details,
+	})
+}
+
+// schemaNode returns the keywords of a schema document, boolean schemas included
+func schemaNode(node interface{}) map[string]interface{} {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		return n
+	case bool:
+		if !n {
+			return map[string]interface{}{KEY_NOT: map[string]interface{}{}}
+		}
+	}
+	return map[string]interface{}{}
+}
+
+// jsonPointerToken escapes a key for a JSON pointer
+func jsonPointerToken(key string) string {
+	return strings.Replace(strings.Replace(key, "~", "~0", -1), "/", "~1", -1)
+}
+
+// walk lints a subschema and its children, node being its document
+func (l *linter) walk(s *subSchema, node interface{}, pointer string) {
+
+	m := schemaNode(node)
+
+	l.lintTypeKeywords(s, m, pointer)
+	l.lintRequired(s, pointer)
+	l.lintEnum(s, pointer)
+	l.lintValues(s, m, pointer)
+	l.lintOneOf(s, m, pointer)
+
+	walkSubSchemas(s, node, func(child *subSchema, childNode interface{}, tokens ...string) {
+		childPointer := pointer
+		for _, token := range tokens {
+			childPointer += "/" + jsonPointerToken(token)
+		}
+		if tokens[0] == KEY_DEFINITIONS {
+			l.definitions[child] = childPointer
+		}
+		l.walk(child, childNode, childPointer)
+	})
+}
+
+// lintTypeKeywords reports the keywords of a type the schema does not allow
+func (l *linter) lintTypeKeywords(s *subSchema, m map[string]interface{}, pointer string) {
+
+	if !s.types.IsTyped() {
+		return
+	}
+
+	for _, group := range typeSpecificKeywords {
+		applies := false
+		for _, t := range group.types {
+			if s.types.Contains(t) || t == TYPE_INTEGER && s.types.Contains(TYPE_NUMBER) || t == TYPE_NUMBER && s.types.Contains(TYPE_INTEGER) {
+				applies = true
+			}
+		}
+		if applies {
+			continue
+		}
+		for _, keyword := range group.keywords {
+			if existsMapKey(m, keyword) {
+				l.report(LINT_KEYWORD_NOT_APPLICABLE, pointer, keyword, Locale.LintKeywordNotApplicable(), ErrorDetails{
+					"keyword": keyword,
+					"applies": group.types[0],
+					"type":    s.types.String(),
+				})
+			}
+		}
+	}
+}
+
+// lintRequired reports the required properties additionalProperties rejects
+func (l *linter) lintRequired(s *subSchema, pointer string) {
+
+	if allowed, ok := s.additionalProperties.(bool); !ok || allowed {
+		return
+	}
+
+	for _, property := range s.required {
+		declared := false
+		for _, p := range s.propertiesChildren {
+			if p.property == property {
+				declared = true
+			}
+		}
+		for k := range s.patternProperties {
+			if s.patternMatchers[k].MatchString(property) {
+				declared = true
+			}
+		}
+		if !declared {
+			l.report(LINT_REQUIRED_NOT_ALLOWED, pointer, KEY_REQUIRED, Locale.LintRequiredNotAllowed(), ErrorDetails{"property": property})
+		}
+	}
+}
+
+// validationReason returns why a value does not validate against a subschema, or an empty string
+func validationReason(s *subSchema, value interface{}) string {
+	result := s.subValidateWithContext(value, NewJsonContext(STRING_CONTEXT_ROOT, nil))
+	if result.Valid() {
+		return ""
+	}
+	var reasons []string
+	for _, e := range result.Errors() {
+		reasons = append(reasons, e.String())
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// decodeJsonValue decodes the JSON text of a value, its numbers becoming json.Number like documents
+func decodeJsonValue(v *jsonValue) interface{} {
+	value, err := decodeJsonUsingNumber(strings.NewReader(v.text))
+	if err != nil {
+		return nil
+	}
+	return value
+}
+
+// lintEnum reports the enum values the other keywords of the schema reject
+func (l *linter) lintEnum(s *subSchema, pointer string) {
+
+	for _, v := range s.enum.values {
+		if reason := validationReason(s, decodeJsonValue(v)); reason != "" {
+			l.report(LINT_INVALID_ENUM_VALUE, pointer, KEY_ENUM, Locale.LintInvalidValue(), ErrorDetails{
+				"keyword": KEY_ENUM,
+				"value":   v.text,
+				"reason":  reason,
+			})
+		}
+	}
+}
+
+// lintValues reports the default and examples that do not validate against their schema
+func (l *linter) lintValues(s *subSchema, m map[string]interface{}, pointer string) {
+
+	if _, isRef := m[KEY_REF]; isRef {
+		// the other keywords are ignored
+		return
+	}
+
+	var values []interface{}
+	var issueTypes []string
+	var keywords []string
+
+	if existsMapKey(m, KEY_DEFAULT) {
+		values = append(values, m[KEY_DEFAULT])
+		issueTypes = append(issueTypes, LINT_INVALID_DEFAULT)
+		keywords = append(keywords, KEY_DEFAULT)
+	}
+	if examples, ok := m[KEY_EXAMPLES].([]interface{}); ok {
+		for _, example := range examples {
+			values = append(values, example)
+			issueTypes = append(issueTypes, LINT_INVALID_EXAMPLE)
+			keywords = append(keywords, KEY_EXAMPLES)
+		}
+	}
+
+	for i, value := range values {
+		if reason := validationReason(s, value); reason != "" {
+			text, _ := jsonValueText(value)
+			l.report(issueTypes[i], pointer, keywords[i], Locale.LintInvalidValue(), ErrorDetails{
+				"keyword": keywords[i],
+				"value":   text,
+				"reason":  reason,
+			})
+		}
+	}
+}
+
+// witnesses returns values a schema document is known to accept, or meant to
+func witnesses(node interface{}) []interface{} {
+	m := schemaNode(node)
+	var values []interface{}
+	if existsMapKey(m, KEY_CONST) {
+		values = append(values, m[KEY_CONST])
+	}
+	for _, keyword := range []string{KEY_ENUM, KEY_EXAMPLES} {
+		if list, ok := m[keyword].([]interface{}); ok {
+			values = append(values, list...)
+		}
+	}
+	if existsMapKey(m, KEY_DEFAULT) {
+		values = append(values, m[KEY_DEFAULT])
+	}
+	return values
+}
+
+// lintOneOf reports the oneOf branches that can never validate, and the ones validating the same values
+func (l *linter) lintOneOf(s *subSchema, m map[string]interface{}, pointer string) {
+
+	nodes, ok := m[KEY_ONE_OF].([]interface{})
+	if !ok || len(nodes) != len(s.oneOf) {
+		return
+	}
+	keywordPointer := pointer + "/" + KEY_ONE_OF
+
+	for i, node := range nodes {
+		branch := schemaNode(node)
+
+		never := false
+		if not, ok := branch[KEY_NOT]; ok && len(schemaNode(not)) == 0 && not != false {
+			never = true
+		}
+		if branchTypes := s.oneOf[i].types; s.types.IsTyped() && branchTypes.IsTyped() {
+			disjoint := true
+			for _, t := range branchTypes.types {
+				if s.types.Contains(t) || t == TYPE_INTEGER && s.types.Contains(TYPE_NUMBER) || t == TYPE_NUMBER && s.types.Contains(TYPE_INTEGER) {
+					disjoint = false
+				}
+			}
+			never = never || disjoint
+		}
+		if never {
+			l.report(LINT_EMPTY_BRANCH, keywordPointer+"/"+strconv.Itoa(i), KEY_ONE_OF, Locale.LintEmptyBranch(), ErrorDetails{"branch": i})
+			continue
+		}
+
+		if len(branch) == 0 && len(nodes) > 1 {
+			l.report(LINT_OVERLAPPING_BRANCHES, keywordPointer+"/"+strconv.Itoa(i), KEY_ONE_OF, Locale.LintAlwaysValidBranch(), ErrorDetails{"branch": i})
+		}
+	}
+
+	// two branches overlap when a value validates against both
+	for i := range nodes {
+		for j := i + 1; j < len(nodes); j++ {
+			a, errA := normalizeJsonValue(nodes[i])
+			b, errB := normalizeJsonValue(nodes[j])
+			if errA == nil && errB == nil && jsonEqual(a, b) {
+				l.report(LINT_OVERLAPPING_BRANCHES, keywordPointer, KEY_ONE_OF, Locale.LintIdenticalBranches(), ErrorDetails{"i": i, "j": j})
+				continue
+			}
+
+			candidates := append(witnesses(nodes[i]), witnesses(nodes[j])...)
+			for _, value := range candidates {
+				if validationReason(s.oneOf[i], value) == "" && validationReason(s.oneOf[j], value) == "" {
+					text, _ := jsonValueText(value)
+					l.report(LINT_OVERLAPPING_BRANCHES, keywordPointer, KEY_ONE_OF, Locale.LintOverlappingBranches(), ErrorDetails{"i": i, "j": j, "value": text})
+					break
+				}
+			}
+		}
+	}
+}
+
+// lintDefinitions reports the definitions that cannot be reached from the root schema
+func (l *linter) lintDefinitions(root *subSchema) {
+
+	reached := map[*subSchema]bool{}
+	references := map[string]bool{}
+	reachSubSchemas(root, reached, references)
+
+	for definition, pointer := range l.definitions {
+		if reached[definition] || definition.id != nil && references[definition.id.String()] {
+			continue
+		}
+		name := pointer[strings.LastIndex(pointer, "/")+1:]
+		l.report(LINT_UNUSED_DEFINITION, pointer, KEY_DEFINITIONS, Locale.LintUnusedDefinition(), ErrorDetails{"definition": name})
+	}
+}
+
+// reachSubSchemas marks the subschemas reachable from s, following $ref but not entering definitions
+func reachSubSchemas(s *subSchema, reached map[*subSchema]bool, references map[string]bool) {
+
+	if s == nil || reached[s] {
+		return
+	}
+	reached[s] = true
+
+	if s.refSchema != nil {
+		references[s.ref.String()] = true
+		reachSubSchemas(s.refSchema, reached, references)
+	}
+
+	for _, child := range subSchemaChildren(s) {
+		reachSubSchemas(child, reached, references)
+	}
+}
+
+// subSchemaChildren returns the subschemas of a subschema, but its definitions and $ref
+func subSchemaChildren(s *subSchema) []*subSchema {
+
+	var children []*subSchema
+
+	children = append(children, s.propertiesChildren...)
+	children = append(children, s.itemsChildren...)
+	for _, pattern := range s.patternProperties {
+		children = append(children, pattern)
+	}
+	for _, dependency := range s.dependencies {
+		if dependencySchema, ok := dependency.(*subSchema); ok {
+			children = append(children, dependencySchema)
+		}
+	}
+	for _, single := range []interface{}{s.additionalProperties, s.additionalItems} {
+		if sub, ok := single.(*subSchema); ok {
+			children = append(children, sub)
+		}
+	}
+	for _, sub := range []*subSchema{s.propertyNames, s.contains, s.not, s._if, s._then, s._else} {
+		if sub != nil {
+			children = append(children, sub)
+		}
+	}
+	children = append(children, s.allOf...)
+	children = append(children, s.anyOf...)
+	children = append(children, s.oneOf...)
+
+	return children
+}
@@ -0,0 +1,92 @@
+package gojsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func lintSchema(t *testing.T, schema string) []LintIssue {
+	s, err := NewSchema(NewStringLoader(schema))
+	if !assert.Nil(t, err) {
+		return nil
+	}
+	return Lint(s)
+}
+
+func lintIssueTypes(issues []LintIssue) []string {
+	var types []string
+	for _, issue := range issues {
+		types = append(types, issue.Pointer+" "+issue.Type)
+	}
+	return types
+}
+
+func TestLint(t *testing.T) {
+	testCases := []struct {
+		schema string
+		issues []string
+	}{
+		{`{"type": "object", "properties": {"a": {}}, "required": ["a"], "additionalProperties": true}`, nil},
+		{
+			`{"properties": {"a": {}}, "patternProperties": {"^x-": {}}, "required": ["a", "x-b", "c"], "additionalProperties": false}`,
+			[]string{"# required_not_allowed"},
+		},
+		{
+			`{"properties": {"id": {"type": "integer", "maxLength": 10, "minimum": 1}, "tags": {"type": ["string", "null"], "items": {}}}}`,
+			[]string{"#/properties/id keyword_not_applicable", "#/properties/tags keyword_not_applicable"},
+		},
+		{`{"type": "number", "multipleOf": 2}`, nil},
+		{
+			`{"definitions": {"size": {"type": "string", "enum": ["small", "medium", 3], "maxLength": 5}}, "properties": {"size": {"$ref": "#/definitions/size"}}}`,
+			[]string{"#/definitions/size invalid_enum_value", "#/definitions/size invalid_enum_value"},
+		},
+		{
+			`{"properties": {"port": {"type": "integer", "minimum": 1024, "default": 80, "examples": [8080, "http"]}}}`,
+			[]string{"#/properties/port invalid_default", "#/properties/port invalid_example"},
+		},
+		{`{"type": "integer", "default": 1.0}`, nil},
+		{
+			`{"type": "string", "oneOf": [false, {"not": {}}, {"type": "integer"}, {"minLength": 1}]}`,
+			[]string{"#/oneOf/0 empty_branch", "#/oneOf/1 empty_branch", "#/oneOf/2 empty_branch"},
+		},
+		{`{"oneOf": [{"type": "string"}, true]}`, []string{"#/oneOf/1 overlapping_branches"}},
+		{`{"oneOf": [{"type": "string"}, {"type": "string"}]}`, []string{"#/oneOf overlapping_branches"}},
+		{
+			`{"oneOf": [{"type": "string", "maxLength": 5}, {"type": "string", "examples": ["abc"]}]}`,
+			[]string{"#/oneOf overlapping_branches"},
+		},
+		{`{"oneOf": [{"type": "string", "const": "a"}, {"type": "integer", "enum": [1, 2]}]}`, nil},
+		{
+			`{"definitions": {"used": {"$ref": "#/definitions/nested"}, "nested": {}, "unused": {}, "a/b": {}}, "items": {"$ref": "#/definitions/used"}}`,
+			[]string{"#/definitions/a~1b unused_definition", "#/definitions/unused unused_definition"},
+		},
+		{`{"$id": "http://example.com/root.json", "definitions": {"a": {"$id": "#a"}}, "not": {"$ref": "#a"}}`, nil},
+	}
+
+	for _, testCase := range testCases {
+		issues := lintSchema(t, testCase.schema)
+		assert.Equal(t, testCase.issues, lintIssueTypes(issues), "schema: %s", testCase.schema)
+	}
+}
+
+func TestLintIssue(t *testing.T) {
+	issues := lintSchema(t, `{"properties": {"n": {"type": "integer", "pattern": "^a"}}, "required": ["m"], "additionalProperties": false}`)
+	if !assert.Len(t, issues, 2) {
+		return
+	}
+
+	assert.Equal(t, LintIssue{
+		Type:        LINT_REQUIRED_NOT_ALLOWED,
+		Pointer:     "#",
+		Keyword:     KEY_REQUIRED,
+		Description: "m is required but additionalProperties does not allow it",
+		Details:     ErrorDetails{"property": "m"},
+	}, issues[0])
+	assert.Equal(t, "#/properties/n: pattern only applies to string values and the type is integer", issues[1].String())
+
+	issues = lintSchema(t, `{"type": "string", "enum": ["a", 1]}`)
+	if assert.Len(t, issues, 1) {
+		assert.Equal(t, "#: enum value 1 does not validate against its schema: (root): Invalid type. Expected: string, given: integer", issues[0].String())
+	}
+}
@@ -0,0 +1,443 @@
+package gojsonschema
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+)
+
+// Locale catalogs
+// A catalog maps the names of the LocaleMessages methods to their messages, the templates receiving
+// the same ErrorDetails as the English ones. The bundled translations are JSON catalogs, see localeCatalogs.go.
+
+// NewCatalogLocale returns a locale reading its messages from a catalog, by name of the LocaleMessages
+// method ( "Required", "InvalidType"... ). The messages missing from the catalog are the English ones,
+// so the locale keeps working when new messages are added.
+func NewCatalogLocale(catalog map[string]string) LocaleMessages {
+	return catalogLocale{catalog: catalog}
+}
+
+// LocaleFor returns the bundled translation of a language, given as "fr" or as a tag like "pt-BR".
+// The languages are fr, de, es, pt, ja and zh, English ( DefaultLocale ) and false being returned for others.
+func LocaleFor(language string) (LocaleMessages, bool) {
+
+	language = strings.ToLower(language)
+	if i := strings.IndexAny(language, "-_"); i >= 0 {
+		language = language[:i]
+	}
+
+	bundledLocales.Lock()
+	defer bundledLocales.Unlock()
+
+	if locale, ok := bundledLocales.locales[language]; ok {
+		return locale, true
+	}
+
+	source, ok := localeCatalogs[language]
+	if !ok {
+		return DefaultLocale{}, false
+	}
+	catalog := map[string]string{}
+	if err := json.Unmarshal([]byte(source), &catalog); err != nil {
+		return DefaultLocale{}, false
+	}
+
+	locale := NewCatalogLocale(catalog)
+	bundledLocales.locales[language] = locale
+	return locale, true
+}
+
+// parsed bundled catalogs, by language
+var bundledLocales = struct {
+	sync.Mutex
+	locales map[string]LocaleMessages
+}{locales: map[string]LocaleMessages{}}
+
+type catalogLocale struct {
+	catalog map[string]string
+}
+
+// message returns the message of a catalog, or the English one
+func (l catalogLocale) message(name string, english string) string {
+	if message, ok := l.catalog[name]; ok && message != "" {
+		return message
+	}
+	return english
+}
+
+func (l catalogLocale) Required() string {
+	return l.message("Required", DefaultLocale{}.Required())
+}
+
+func (l catalogLocale) InvalidType() string {
+	return l.message("InvalidType", DefaultLocale{}.InvalidType())
+}
+
+func (l catalogLocale) InvalidBSONType() string {
+	return l.message("InvalidBSONType", DefaultLocale{}.InvalidBSONType())
+}
+
+func (l catalogLocale) NumberAnyOf() string {
+	return l.message("NumberAnyOf", DefaultLocale{}.NumberAnyOf())
+}
+
+func (l catalogLocale) NumberOneOf() string {
+	return l.message("NumberOneOf", DefaultLocale{}.NumberOneOf())
+}
+
+func (l catalogLocale) NumberAllOf() string {
+	return l.message("NumberAllOf", DefaultLocale{}.NumberAllOf())
+}
+
+func (l catalogLocale) NumberNot() string {
+	return l.message("NumberNot", DefaultLocale{}.NumberNot())
+}
+
+func (l catalogLocale) MissingDependency() string {
+	return l.message("MissingDependency", DefaultLocale{}.MissingDependency())
+}
+
+func (l catalogLocale) Internal() string {
+	return l.message("Internal", DefaultLocale{}.Internal())
+}
+
+func (l catalogLocale) Const() string {
+	return l.message("Const", DefaultLocale{}.Const())
+}
+
+func (l catalogLocale) Enum() string {
+	return l.message("Enum", DefaultLocale{}.Enum())
+}
+
+func (l catalogLocale) ArrayNotEnoughItems() string {
+	return l.message("ArrayNotEnoughItems", DefaultLocale{}.ArrayNotEnoughItems())
+}
+
+func (l catalogLocale) ArrayNoAdditionalItems() string {
+	return l.message("ArrayNoAdditionalItems", DefaultLocale{}.ArrayNoAdditionalItems())
+}
+
+func (l catalogLocale) ArrayMinItems() string {
+	return l.message("ArrayMinItems", DefaultLocale{}.ArrayMinItems())
+}
+
+func (l catalogLocale) ArrayMaxItems() string {
+	return l.message("ArrayMaxItems", DefaultLocale{}.ArrayMaxItems())
+}
+
+func (l catalogLocale) Unique() string {
+	return l.message("Unique", DefaultLocale{}.Unique())
+}
+
+func (l catalogLocale) ArrayContains() string {
+	return l.message("ArrayContains", DefaultLocale{}.ArrayContains())
+}
+
+func (l catalogLocale) ArrayMinProperties() string {
+	return l.message("ArrayMinProperties", DefaultLocale{}.ArrayMinProperties())
+}
+
+func (l catalogLocale) ArrayMaxProperties() string {
+	return l.message("ArrayMaxProperties", DefaultLocale{}.ArrayMaxProperties())
+}
+
+func (l catalogLocale) AdditionalPropertyNotAllowed() string {
+	return l.message("AdditionalPropertyNotAllowed", DefaultLocale{}.AdditionalPropertyNotAllowed())
+}
+
+func (l catalogLocale) InvalidPropertyPattern() string {
+	return l.message("InvalidPropertyPattern", DefaultLocale{}.InvalidPropertyPattern())
+}
+
+func (l catalogLocale) InvalidPropertyName() string {
+	return l.message("InvalidPropertyName", DefaultLocale{}.InvalidPropertyName())
+}
+
+func (l catalogLocale) StringGTE() string {
+	return l.message("StringGTE", DefaultLocale{}.StringGTE())
+}
+
+func (l catalogLocale) StringLTE() string {
+	return l.message("StringLTE", DefaultLocale{}.StringLTE())
+}
+
+func (l catalogLocale) DoesNotMatchPattern() string {
+	return l.message("DoesNotMatchPattern", DefaultLocale{}.DoesNotMatchPattern())
+}
+
+func (l catalogLocale) RegexStepLimit() string {
+	return l.message("RegexStepLimit", DefaultLocale{}.RegexStepLimit())
+}
+
+func (l catalogLocale) DoesNotMatchFormat() string {
+	return l.message("DoesNotMatchFormat", DefaultLocale{}.DoesNotMatchFormat())
+}
+
+func (l catalogLocale) MultipleOf() string {
+	return l.message("MultipleOf", DefaultLocale{}.MultipleOf())
+}
+
+func (l catalogLocale) NumberGTE() string {
+	return l.message("NumberGTE", DefaultLocale{}.NumberGTE())
+}
+
+func (l catalogLocale) NumberGT() string {
+	return l.message("NumberGT", DefaultLocale{}.NumberGT())
+}
+
+func (l catalogLocale) NumberLTE() string {
+	return l.message("NumberLTE", DefaultLocale{}.NumberLTE())
+}
+
+func (l catalogLocale) NumberLT() string {
+	return l.message("NumberLT", DefaultLocale{}.NumberLT())
+}
+
+func (l catalogLocale) Discriminator() string {
+	return l.message("Discriminator", DefaultLocale{}.Discriminator())
+}
+
+// Schema validations
+func (l catalogLocale) RegexPattern() string {
+	return l.message("RegexPattern", DefaultLocale{}.RegexPattern())
+}
+
+func (l catalogLocale) GreaterThanZero() string {
+	return l.message("GreaterThanZero", DefaultLocale{}.GreaterThanZero())
+}
+
+func (l catalogLocale) MustBeOfA() string {
+	return l.message("MustBeOfA", DefaultLocale{}.MustBeOfA())
+}
+
+func (l catalogLocale) MustBeOfAn() string {
+	return l.message("MustBeOfAn", DefaultLocale{}.MustBeOfAn())
+}
+
+func (l catalogLocale) CannotBeUsedWithout() string {
+	return l.message("CannotBeUsedWithout", DefaultLocale{}.CannotBeUsedWithout())
+}
+
+func (l catalogLocale) CannotBeGT() string {
+	return l.message("CannotBeGT", DefaultLocale{}.CannotBeGT())
+}
+
+func (l catalogLocale) MustBeOfType() string {
+	return l.message("MustBeOfType", DefaultLocale{}.MustBeOfType())
+}
+
+func (l catalogLocale) MustBeValidRegex() string {
+	return l.message("MustBeValidRegex", DefaultLocale{}.MustBeValidRegex())
+}
+
+func (l catalogLocale) MustBeValidFormat() string {
+	return l.message("MustBeValidFormat", DefaultLocale{}.MustBeValidFormat())
+}
+
+func (l catalogLocale) MustBeGTEZero() string {
+	return l.message("MustBeGTEZero", DefaultLocale{}.MustBeGTEZero())
+}
+
+func (l catalogLocale) KeyCannotBeGreaterThan() string {
+	return l.message("KeyCannotBeGreaterThan", DefaultLocale{}.KeyCannotBeGreaterThan())
+}
+
+func (l catalogLocale) KeyItemsMustBeOfType() string {
+	return l.message("KeyItemsMustBeOfType", DefaultLocale{}.KeyItemsMustBeOfType())
+}
+
+func (l catalogLocale) KeyItemsMustBeUnique() string {
+	return l.message("KeyItemsMustBeUnique", DefaultLocale{}.KeyItemsMustBeUnique())
+}
+
+func (l catalogLocale) ReferenceMustBeCanonical() string {
+	return l.message("ReferenceMustBeCanonical", DefaultLocale{}.ReferenceMustBeCanonical())
+}
+
+func (l catalogLocale) NotAValidType() string {
+	return l.message("NotAValidType", DefaultLocale{}.NotAValidType())
+}
+
+func (l catalogLocale) Duplicated() string {
+	return l.message("Duplicated", DefaultLocale{}.Duplicated())
+}
+
+func (l catalogLocale) HttpBadStatus() string {
+	return l.message("HttpBadStatus", DefaultLocale{}.HttpBadStatus())
+}
+
+func (l catalogLocale) ParseError() string {
+	return l.message("ParseError", DefaultLocale{}.ParseError())
+}
+
+func (l catalogLocale) KeywordNotSupported() string {
+	return l.message("KeywordNotSupported", DefaultLocale{}.KeywordNotSupported())
+}
+
+func (l catalogLocale) CannotBeUsedWith() string {
+	return l.message("CannotBeUsedWith", DefaultLocale{}.CannotBeUsedWith())
+}
+
+// YAML conversion
+func (l catalogLocale) YAMLKeyMustBeString() string {
+	return l.message("YAMLKeyMustBeString", DefaultLocale{}.YAMLKeyMustBeString())
+}
+
+func (l catalogLocale) YAMLInvalidValue() string {
+	return l.message("YAMLInvalidValue", DefaultLocale{}.YAMLInvalidValue())
+}
+
+func (l catalogLocale) YAMLInvalidMerge() string {
+	return l.message("YAMLInvalidMerge", DefaultLocale{}.YAMLInvalidMerge())
+}
+
+func (l catalogLocale) YAMLRecursiveAlias() string {
+	return l.message("YAMLRecursiveAlias", DefaultLocale{}.YAMLRecursiveAlias())
+}
+
+func (l catalogLocale) YAMLExcessiveAliasing() string {
+	return l.message("YAMLExcessiveAliasing", DefaultLocale{}.YAMLExcessiveAliasing())
+}
+
+// BSON decoding
+func (l catalogLocale) BSONParseError() string {
+	return l.message("BSONParseError", DefaultLocale{}.BSONParseError())
+}
+
+func (l catalogLocale) ExtendedJSONParseError() string {
+	return l.message("ExtendedJSONParseError", DefaultLocale{}.ExtendedJSONParseError())
+}
+
+// Regular expressions
+func (l catalogLocale) RegexSyntaxError() string {
+	return l.message("RegexSyntaxError", DefaultLocale{}.RegexSyntaxError())
+}
+
+// Meta-schemas
+func (l catalogLocale) InvalidSchemaDocument() string {
+	return l.message("InvalidSchemaDocument", DefaultLocale{}.InvalidSchemaDocument())
+}
+
+func (l catalogLocale) UnknownMetaSchema() string {
+	return l.message("UnknownMetaSchema", DefaultLocale{}.UnknownMetaSchema())
+}
+
+// Go types reflection
+func (l catalogLocale) ReflectUnsupportedType() string {
+	return l.message("ReflectUnsupportedType", DefaultLocale{}.ReflectUnsupportedType())
+}
+
+func (l catalogLocale) ReflectInvalidTag() string {
+	return l.message("ReflectInvalidTag", DefaultLocale{}.ReflectInvalidTag())
+}
+
+// Lint
+func (l catalogLocale) LintRequiredNotAllowed() string {
+	return l.message("LintRequiredNotAllowed", DefaultLocale{}.LintRequiredNotAllowed())
+}
+
+func (l catalogLocale) LintKeywordNotApplicable() string {
+	return l.message("LintKeywordNotApplicable", DefaultLocale{}.LintKeywordNotApplicable())
+}
+
+func (l catalogLocale) LintInvalidValue() string {
+	return l.message("LintInvalidValue", DefaultLocale{}.LintInvalidValue())
+}
+
+func (l catalogLocale) LintEmptyBranch() string {
+	return l.message("LintEmptyBranch", DefaultLocale{}.LintEmptyBranch())
+}
+
+func (l catalogLocale) LintAlwaysValidBranch() string {
+	return l.message("LintAlwaysValidBranch", DefaultLocale{}.LintAlwaysValidBranch())
+}
+
+func (l catalogLocale) LintIdenticalBranches() string {
+	return l.message("LintIdenticalBranches", DefaultLocale{}.LintIdenticalBranches())
+}
+
+func (l catalogLocale) LintOverlappingBranches() string {
+	return l.message("LintOverlappingBranches", DefaultLocale{}.LintOverlappingBranches())
+}
+
+func (l catalogLocale) LintUnusedDefinition() string {
+	return l.message("LintUnusedDefinition", DefaultLocale{}.LintUnusedDefinition())
+}
+
+// Bundle
+func (l catalogLocale) BundleUnresolvedReference() string {
+	return l.message("BundleUnresolvedReference", DefaultLocale{}.BundleUnresolvedReference())
+}
+
+// Compare
+func (l catalogLocale) CompareRequiredAdded() string {
+	return l.message("CompareRequiredAdded", DefaultLocale{}.CompareRequiredAdded())
+}
+
+func (l catalogLocale) CompareRequiredRemoved() string {
+	return l.message("CompareRequiredRemoved", DefaultLocale{}.CompareRequiredRemoved())
+}
+
+func (l catalogLocale) ComparePropertyAdded() string {
+	return l.message("ComparePropertyAdded", DefaultLocale{}.ComparePropertyAdded())
+}
+
+func (l catalogLocale) ComparePropertyRemoved() string {
+	return l.message("ComparePropertyRemoved", DefaultLocale{}.ComparePropertyRemoved())
+}
+
+func (l catalogLocale) CompareTypeChanged() string {
+	return l.message("CompareTypeChanged", DefaultLocale{}.CompareTypeChanged())
+}
+
+func (l catalogLocale) CompareEnumChanged() string {
+	return l.message("CompareEnumChanged", DefaultLocale{}.CompareEnumChanged())
+}
+
+func (l catalogLocale) CompareConstraintChanged() string {
+	return l.message("CompareConstraintChanged", DefaultLocale{}.CompareConstraintChanged())
+}
+
+func (l catalogLocale) CompareReferenceChanged() string {
+	return l.message("CompareReferenceChanged", DefaultLocale{}.CompareReferenceChanged())
+}
+
+// Sample
+func (l catalogLocale) SampleNotGenerated() string {
+	return l.message("SampleNotGenerated", DefaultLocale{}.SampleNotGenerated())
+}
+
+func (l catalogLocale) SampleDepthExceeded() string {
+	return l.message("SampleDepthExceeded", DefaultLocale{}.SampleDepthExceeded())
+}
+
+// Keyword
+func (l catalogLocale) KeywordReserved() string {
+	return l.message("KeywordReserved", DefaultLocale{}.KeywordReserved())
+}
+
+func (l catalogLocale) KeywordInvalid() string {
+	return l.message("KeywordInvalid", DefaultLocale{}.KeywordInvalid())
+}
+
+// Coercion
+func (l catalogLocale) CoercionNotInPlace() string {
+	return l.message("CoercionNotInPlace", DefaultLocale{}.CoercionNotInPlace())
+}
+
+func (l catalogLocale) CoercionNoGoDocument() string {
+	return l.message("CoercionNoGoDocument", DefaultLocale{}.CoercionNoGoDocument())
+}
+
+func (l catalogLocale) ConditionThen() string {
+	return l.message("ConditionThen", DefaultLocale{}.ConditionThen())
+}
+
+func (l catalogLocale) ConditionElse() string {
+	return l.message("ConditionElse", DefaultLocale{}.ConditionElse())
+}
+
+// ErrorFormat
+func (l catalogLocale) ErrorFormat() string {
+	return l.message("ErrorFormat", DefaultLocale{}.ErrorFormat())
+}
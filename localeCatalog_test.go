@@ -0,0 +1,111 @@
+package gojsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var placeholderRegex = regexp.MustCompile(`{{\.\w+}}`)
+
+func placeholders(message string) []string {
+	found := placeholderRegex.FindAllString(message, -1)
+	sort.Strings(found)
+	return found
+}
+
+func TestLocaleCatalogs(t *testing.T) {
+	english := reflect.ValueOf(DefaultLocale{})
+
+	for language, source := range localeCatalogs {
+		catalog := map[string]string{}
+		if !assert.Nil(t, json.Unmarshal([]byte(source), &catalog), language) {
+			continue
+		}
+		for name, message := range catalog {
+			method := english.MethodByName(name)
+			if !assert.True(t, method.IsValid(), "%s: unknown message %s", language, name) {
+				continue
+			}
+			englishMessage := method.Call(nil)[0].String()
+			assert.Equal(t, placeholders(englishMessage), placeholders(message), "%s: %s", language, name)
+		}
+
+		locale, ok := LocaleFor(language)
+		assert.True(t, ok)
+		assert.Equal(t, catalog["Required"], locale.Required())
+	}
+}
+
+func TestLocaleFor(t *testing.T) {
+	locale, ok := LocaleFor("pt-BR")
+	assert.True(t, ok)
+	assert.Equal(t, "{{.property}} é obrigatório", locale.Required())
+
+	locale, ok = LocaleFor("zh_Hans")
+	assert.True(t, ok)
+	assert.Equal(t, "必须大于 {{.min}}", locale.NumberGT())
+
+	// the messages missing from a catalog are the English ones
+	locale, _ = LocaleFor("fr")
+	assert.Equal(t, DefaultLocale{}.RegexSyntaxError(), locale.RegexSyntaxError())
+
+	locale, ok = LocaleFor("xx")
+	assert.False(t, ok)
+	assert.Equal(t, DefaultLocale{}, locale)
+
+	locale = NewCatalogLocale(map[string]string{"Required": "{{.property}} missing"})
+	assert.Equal(t, "{{.property}} missing", locale.Required())
+	assert.Equal(t, DefaultLocale{}.NumberGT(), locale.NumberGT())
+}
+
+func TestValidateWithLocale(t *testing.T) {
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {"age": {"type": "integer", "minimum": 18}},
+		"required": ["name"]
+	}`))
+	if !assert.Nil(t, err) {
+		return
+	}
+	document := NewStringLoader(`{"age": 12}`)
+
+	french, _ := LocaleFor("fr")
+	result, err := schema.ValidateWithLocale(document, french)
+	if !assert.Nil(t, err) {
+		return
+	}
+	var descriptions []string
+	for _, resultError := range result.Errors() {
+		descriptions = append(descriptions, resultError.Description())
+	}
+	assert.ElementsMatch(t, []string{"name est obligatoire", "Doit être supérieur ou égal à 18"}, descriptions)
+
+	// the global locale is not changed, and a result can be rendered in another locale
+	result, err = schema.Validate(document)
+	if !assert.Nil(t, err) {
+		return
+	}
+	japanese, _ := LocaleFor("ja")
+	localized := result.Localize(japanese)
+	if assert.Len(t, localized.Errors(), 2) {
+		for i, resultError := range result.Errors() {
+			assert.Equal(t, resultError.Type(), localized.Errors()[i].Type())
+			assert.Equal(t, resultError.Field(), localized.Errors()[i].Field())
+			assert.NotEqual(t, resultError.Description(), localized.Errors()[i].Description())
+			assert.IsType(t, resultError, localized.Errors()[i])
+		}
+	}
+	assert.Contains(t, []string{"name is required", "Must be greater than or equal to 18"}, result.Errors()[0].Description())
+
+	custom := NewCatalogLocale(map[string]string{"ErrorFormat": "{{.field}} → {{.description}}", "Required": "{{.property}} manquant"})
+	localized = result.Localize(custom)
+	for _, resultError := range localized.Errors() {
+		if resultError.Type() == "required" {
+			assert.Equal(t, "name → name manquant", resultError.String())
+		}
+	}
+}
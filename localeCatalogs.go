@@ -0,0 +1,240 @@
+package gojsonschema
+
+// Bundled translations of the validation messages, as JSON catalogs by language.
+// The messages missing from a catalog, like the ones of schema parsing, are the English ones.
+var localeCatalogs = map[string]string{
+	"fr": frenchCatalog,
+	"de": germanCatalog,
+	"es": spanishCatalog,
+	"pt": portugueseCatalog,
+	"ja": japaneseCatalog,
+	"zh": chineseCatalog,
+}
+
+const frenchCatalog = `{
+	"Required": "{{.property}} est obligatoire",
+	"InvalidType": "Type invalide. Attendu : {{.expected}}, reçu : {{.given}}",
+	"InvalidBSONType": "Type BSON invalide. Attendu : {{.expected}}, reçu : {{.given}}",
+	"NumberAnyOf": "Doit être valide pour au moins un schéma (anyOf)",
+	"NumberOneOf": "Doit être valide pour un et un seul schéma (oneOf){{if .matched}}, mais valide pour les schémas {{.matched}}{{end}}{{if .failures}}, erreurs des schémas : {{.failures}}{{end}}",
+	"NumberAllOf": "Doit être valide pour tous les schémas (allOf)",
+	"NumberNot": "Ne doit pas être valide pour le schéma (not)",
+	"MissingDependency": "Dépend de {{.dependency}}",
+	"Internal": "Erreur interne {{.error}}",
+	"Const": "{{.field}} ne correspond pas à : {{.allowed}}",
+	"Enum": "{{.field}} doit être l'une des valeurs suivantes : {{.allowed}}",
+	"ArrayNoAdditionalItems": "Aucun élément supplémentaire n'est autorisé dans le tableau",
+	"ArrayNotEnoughItems": "Le tableau n'a pas assez d'éléments pour la liste positionnelle du schéma",
+	"ArrayMinItems": "Le tableau doit avoir au moins {{.min}} éléments",
+	"ArrayMaxItems": "Le tableau doit avoir au plus {{.max}} éléments",
+	"Unique": "Les éléments {{.type}} [{{.i}}] et [{{.j}}] doivent être uniques",
+	"ArrayContains": "Au moins un des éléments doit correspondre",
+	"ArrayMinProperties": "Doit avoir au moins {{.min}} propriétés",
+	"ArrayMaxProperties": "Doit avoir au plus {{.max}} propriétés",
+	"AdditionalPropertyNotAllowed": "La propriété supplémentaire {{.property}} n'est pas autorisée",
+	"InvalidPropertyPattern": "La propriété \"{{.property}}\" ne correspond pas au motif {{.pattern}}",
+	"InvalidPropertyName": "Le nom de la propriété \"{{.property}}\" ne correspond pas",
+	"StringGTE": "La longueur de la chaîne doit être supérieure ou égale à {{.min}}",
+	"StringLTE": "La longueur de la chaîne doit être inférieure ou égale à {{.max}}",
+	"DoesNotMatchPattern": "Ne correspond pas au motif '{{.pattern}}'",
+	"RegexStepLimit": "La recherche du motif '{{.pattern}}' a dépassé la limite d'étapes de retour arrière",
+	"DoesNotMatchFormat": "Ne correspond pas au format '{{.format}}'",
+	"MultipleOf": "Doit être un multiple de {{.multiple}}",
+	"NumberGTE": "Doit être supérieur ou égal à {{.min}}",
+	"NumberGT": "Doit être supérieur à {{.min}}",
+	"NumberLTE": "Doit être inférieur ou égal à {{.max}}",
+	"NumberLT": "Doit être inférieur à {{.max}}",
+	"Discriminator": "{{.property}} doit être l'une des valeurs suivantes : {{.allowed}}",
+	"ConditionThen": "Doit être valide pour \"then\" car \"if\" est valide",
+	"ConditionElse": "Doit être valide pour \"else\" car \"if\" n'est pas valide"
+}`
+
+const germanCatalog = `{
+	"Required": "{{.property}} ist erforderlich",
+	"InvalidType": "Ungültiger Typ. Erwartet: {{.expected}}, erhalten: {{.given}}",
+	"InvalidBSONType": "Ungültiger BSON-Typ. Erwartet: {{.expected}}, erhalten: {{.given}}",
+	"NumberAnyOf": "Muss mindestens einem Schema entsprechen (anyOf)",
+	"NumberOneOf": "Muss genau einem Schema entsprechen (oneOf){{if .matched}}, entspricht aber den Schemas {{.matched}}{{end}}{{if .failures}}, Fehler der Schemas: {{.failures}}{{end}}",
+	"NumberAllOf": "Muss allen Schemas entsprechen (allOf)",
+	"NumberNot": "Darf dem Schema nicht entsprechen (not)",
+	"MissingDependency": "Hat eine Abhängigkeit von {{.dependency}}",
+	"Internal": "Interner Fehler {{.error}}",
+	"Const": "{{.field}} stimmt nicht überein mit: {{.allowed}}",
+	"Enum": "{{.field}} muss einer der folgenden Werte sein: {{.allowed}}",
+	"ArrayNoAdditionalItems": "Keine weiteren Elemente im Array erlaubt",
+	"ArrayNotEnoughItems": "Nicht genügend Elemente im Array für die Positionsliste des Schemas",
+	"ArrayMinItems": "Array muss mindestens {{.min}} Elemente haben",
+	"ArrayMaxItems": "Array darf höchstens {{.max}} Elemente haben",
+	"Unique": "{{.type}} Elemente [{{.i}}] und [{{.j}}] müssen eindeutig sein",
+	"ArrayContains": "Mindestens eines der Elemente muss übereinstimmen",
+	"ArrayMinProperties": "Muss mindestens {{.min}} Eigenschaften haben",
+	"ArrayMaxProperties": "Darf höchstens {{.max}} Eigenschaften haben",
+	"AdditionalPropertyNotAllowed": "Zusätzliche Eigenschaft {{.property}} ist nicht erlaubt",
+	"InvalidPropertyPattern": "Eigenschaft \"{{.property}}\" entspricht nicht dem Muster {{.pattern}}",
+	"InvalidPropertyName": "Eigenschaftsname von \"{{.property}}\" stimmt nicht überein",
+	"StringGTE": "Zeichenkettenlänge muss größer oder gleich {{.min}} sein",
+	"StringLTE": "Zeichenkettenlänge muss kleiner oder gleich {{.max}} sein",
+	"DoesNotMatchPattern": "Entspricht nicht dem Muster '{{.pattern}}'",
+	"RegexStepLimit": "Der Abgleich mit dem Muster '{{.pattern}}' hat die Backtracking-Schrittgrenze überschritten",
+	"DoesNotMatchFormat": "Entspricht nicht dem Format '{{.format}}'",
+	"MultipleOf": "Muss ein Vielfaches von {{.multiple}} sein",
+	"NumberGTE": "Muss größer oder gleich {{.min}} sein",
+	"NumberGT": "Muss größer als {{.min}} sein",
+	"NumberLTE": "Muss kleiner oder gleich {{.max}} sein",
+	"NumberLT": "Muss kleiner als {{.max}} sein",
+	"Discriminator": "{{.property}} muss einer der folgenden Werte sein: {{.allowed}}",
+	"ConditionThen": "Muss \"then\" entsprechen, da \"if\" gültig war",
+	"ConditionElse": "Muss \"else\" entsprechen, da \"if\" nicht gültig war"
+}`
+
+const spanishCatalog = `{
+	"Required": "{{.property}} es obligatorio",
+	"InvalidType": "Tipo no válido. Esperado: {{.expected}}, recibido: {{.given}}",
+	"InvalidBSONType": "Tipo BSON no válido. Esperado: {{.expected}}, recibido: {{.given}}",
+	"NumberAnyOf": "Debe validar al menos un esquema (anyOf)",
+	"NumberOneOf": "Debe validar uno y solo un esquema (oneOf){{if .matched}}, pero valida los esquemas {{.matched}}{{end}}{{if .failures}}, errores de los esquemas: {{.failures}}{{end}}",
+	"NumberAllOf": "Debe validar todos los esquemas (allOf)",
+	"NumberNot": "No debe validar el esquema (not)",
+	"MissingDependency": "Tiene una dependencia de {{.dependency}}",
+	"Internal": "Error interno {{.error}}",
+	"Const": "{{.field}} no coincide con: {{.allowed}}",
+	"Enum": "{{.field}} debe ser uno de los siguientes: {{.allowed}}",
+	"ArrayNoAdditionalItems": "No se permiten elementos adicionales en el array",
+	"ArrayNotEnoughItems": "No hay suficientes elementos en el array para la lista posicional del esquema",
+	"ArrayMinItems": "El array debe tener al menos {{.min}} elementos",
+	"ArrayMaxItems": "El array debe tener como máximo {{.max}} elementos",
+	"Unique": "Los elementos {{.type}} [{{.i}}] y [{{.j}}] deben ser únicos",
+	"ArrayContains": "Al menos uno de los elementos debe coincidir",
+	"ArrayMinProperties": "Debe tener al menos {{.min}} propiedades",
+	"ArrayMaxProperties": "Debe tener como máximo {{.max}} propiedades",
+	"AdditionalPropertyNotAllowed": "No se permite la propiedad adicional {{.property}}",
+	"InvalidPropertyPattern": "La propiedad \"{{.property}}\" no coincide con el patrón {{.pattern}}",
+	"InvalidPropertyName": "El nombre de la propiedad \"{{.property}}\" no coincide",
+	"StringGTE": "La longitud de la cadena debe ser mayor o igual que {{.min}}",
+	"StringLTE": "La longitud de la cadena debe ser menor o igual que {{.max}}",
+	"DoesNotMatchPattern": "No coincide con el patrón '{{.pattern}}'",
+	"RegexStepLimit": "La búsqueda del patrón '{{.pattern}}' superó el límite de pasos de retroceso",
+	"DoesNotMatchFormat": "No coincide con el formato '{{.format}}'",
+	"MultipleOf": "Debe ser múltiplo de {{.multiple}}",
+	"NumberGTE": "Debe ser mayor o igual que {{.min}}",
+	"NumberGT": "Debe ser mayor que {{.min}}",
+	"NumberLTE": "Debe ser menor o igual que {{.max}}",
+	"NumberLT": "Debe ser menor que {{.max}}",
+	"Discriminator": "{{.property}} debe ser uno de los siguientes: {{.allowed}}",
+	"ConditionThen": "Debe validar \"then\" ya que \"if\" es válido",
+	"ConditionElse": "Debe validar \"else\" ya que \"if\" no es válido"
+}`
+
+const portugueseCatalog = `{
+	"Required": "{{.property}} é obrigatório",
+	"InvalidType": "Tipo inválido. Esperado: {{.expected}}, recebido: {{.given}}",
+	"InvalidBSONType": "Tipo BSON inválido. Esperado: {{.expected}}, recebido: {{.given}}",
+	"NumberAnyOf": "Deve validar pelo menos um esquema (anyOf)",
+	"NumberOneOf": "Deve validar um e apenas um esquema (oneOf){{if .matched}}, mas valida os esquemas {{.matched}}{{end}}{{if .failures}}, erros dos esquemas: {{.failures}}{{end}}",
+	"NumberAllOf": "Deve validar todos os esquemas (allOf)",
+	"NumberNot": "Não deve validar o esquema (not)",
+	"MissingDependency": "Tem uma dependência de {{.dependency}}",
+	"Internal": "Erro interno {{.error}}",
+	"Const": "{{.field}} não corresponde a: {{.allowed}}",
+	"Enum": "{{.field}} deve ser um dos seguintes: {{.allowed}}",
+	"ArrayNoAdditionalItems": "Não são permitidos itens adicionais no array",
+	"ArrayNotEnoughItems": "Itens insuficientes no array para a lista posicional do esquema",
+	"ArrayMinItems": "O array deve ter pelo menos {{.min}} itens",
+	"ArrayMaxItems": "O array deve ter no máximo {{.max}} itens",
+	"Unique": "Os itens {{.type}} [{{.i}}] e [{{.j}}] devem ser únicos",
+	"ArrayContains": "Pelo menos um dos itens deve corresponder",
+	"ArrayMinProperties": "Deve ter pelo menos {{.min}} propriedades",
+	"ArrayMaxProperties": "Deve ter no máximo {{.max}} propriedades",
+	"AdditionalPropertyNotAllowed": "A propriedade adicional {{.property}} não é permitida",
+	"InvalidPropertyPattern": "A propriedade \"{{.property}}\" não corresponde ao padrão {{.pattern}}",
+	"InvalidPropertyName": "O nome da propriedade \"{{.property}}\" não corresponde",
+	"StringGTE": "O comprimento da string deve ser maior ou igual a {{.min}}",
+	"StringLTE": "O comprimento da string deve ser menor ou igual a {{.max}}",
+	"DoesNotMatchPattern": "Não corresponde ao padrão '{{.pattern}}'",
+	"RegexStepLimit": "A busca do padrão '{{.pattern}}' excedeu o limite de passos de retrocesso",
+	"DoesNotMatchFormat": "Não corresponde ao formato '{{.format}}'",
+	"MultipleOf": "Deve ser múltiplo de {{.multiple}}",
+	"NumberGTE": "Deve ser maior ou igual a {{.min}}",
+	"NumberGT": "Deve ser maior que {{.min}}",
+	"NumberLTE": "Deve ser menor ou igual a {{.max}}",
+	"NumberLT": "Deve ser menor que {{.max}}",
+	"Discriminator": "{{.property}} deve ser um dos seguintes: {{.allowed}}",
+	"ConditionThen": "Deve validar \"then\" pois \"if\" é válido",
+	"ConditionElse": "Deve validar \"else\" pois \"if\" não é válido"
+}`
+
+const japaneseCatalog = `{
+	"Required": "{{.property}} は必須です",
+	"InvalidType": "型が不正です。期待値: {{.expected}}、実際: {{.given}}",
+	"InvalidBSONType": "BSON 型が不正です。期待値: {{.expected}}、実際: {{.given}}",
+	"NumberAnyOf": "少なくとも 1 つのスキーマに一致する必要があります (anyOf)",
+	"NumberOneOf": "ただ 1 つのスキーマに一致する必要があります (oneOf){{if .matched}}。一致したスキーマ: {{.matched}}{{end}}{{if .failures}}。各スキーマのエラー: {{.failures}}{{end}}",
+	"NumberAllOf": "すべてのスキーマに一致する必要があります (allOf)",
+	"NumberNot": "スキーマに一致してはいけません (not)",
+	"MissingDependency": "{{.dependency}} に依存しています",
+	"Internal": "内部エラー {{.error}}",
+	"Const": "{{.field}} が一致しません: {{.allowed}}",
+	"Enum": "{{.field}} は次のいずれかである必要があります: {{.allowed}}",
+	"ArrayNoAdditionalItems": "配列に追加の要素は許可されていません",
+	"ArrayNotEnoughItems": "スキーマの位置指定リストに対して配列の要素が不足しています",
+	"ArrayMinItems": "配列には少なくとも {{.min}} 個の要素が必要です",
+	"ArrayMaxItems": "配列の要素は {{.max}} 個以下である必要があります",
+	"Unique": "{{.type}} の要素 [{{.i}}] と [{{.j}}] は一意である必要があります",
+	"ArrayContains": "少なくとも 1 つの要素が一致する必要があります",
+	"ArrayMinProperties": "少なくとも {{.min}} 個のプロパティが必要です",
+	"ArrayMaxProperties": "プロパティは {{.max}} 個以下である必要があります",
+	"AdditionalPropertyNotAllowed": "追加のプロパティ {{.property}} は許可されていません",
+	"InvalidPropertyPattern": "プロパティ \"{{.property}}\" がパターン {{.pattern}} に一致しません",
+	"InvalidPropertyName": "プロパティ名 \"{{.property}}\" が一致しません",
+	"StringGTE": "文字列の長さは {{.min}} 以上である必要があります",
+	"StringLTE": "文字列の長さは {{.max}} 以下である必要があります",
+	"DoesNotMatchPattern": "パターン '{{.pattern}}' に一致しません",
+	"RegexStepLimit": "パターン '{{.pattern}}' の照合がバックトラックのステップ上限を超えました",
+	"DoesNotMatchFormat": "フォーマット '{{.format}}' に一致しません",
+	"MultipleOf": "{{.multiple}} の倍数である必要があります",
+	"NumberGTE": "{{.min}} 以上である必要があります",
+	"NumberGT": "{{.min}} より大きい必要があります",
+	"NumberLTE": "{{.max}} 以下である必要があります",
+	"NumberLT": "{{.max}} 未満である必要があります",
+	"Discriminator": "{{.property}} は次のいずれかである必要があります: {{.allowed}}",
+	"ConditionThen": "\"if\" が有効なため \"then\" に一致する必要があります",
+	"ConditionElse": "\"if\" が無効なため \"else\" に一致する必要があります"
+}`
+
+const chineseCatalog = `{
+	"Required": "{{.property}} 是必需的",
+	"InvalidType": "类型无效。期望：{{.expected}}，实际：{{.given}}",
+	"InvalidBSONType": "BSON 类型无效。期望：{{.expected}}，实际：{{.given}}",
+	"NumberAnyOf": "必须至少匹配一个模式 (anyOf)",
+	"NumberOneOf": "必须匹配且仅匹配一个模式 (oneOf){{if .matched}}，但匹配了模式 {{.matched}}{{end}}{{if .failures}}，各模式的错误：{{.failures}}{{end}}",
+	"NumberAllOf": "必须匹配所有模式 (allOf)",
+	"NumberNot": "不得匹配该模式 (not)",
+	"MissingDependency": "依赖于 {{.dependency}}",
+	"Internal": "内部错误 {{.error}}",
+	"Const": "{{.field}} 不匹配：{{.allowed}}",
+	"Enum": "{{.field}} 必须是以下值之一：{{.allowed}}",
+	"ArrayNoAdditionalItems": "数组不允许有额外的元素",
+	"ArrayNotEnoughItems": "数组元素不足以匹配模式的位置列表",
+	"ArrayMinItems": "数组至少需要 {{.min}} 个元素",
+	"ArrayMaxItems": "数组最多只能有 {{.max}} 个元素",
+	"Unique": "{{.type}} 元素 [{{.i}}] 和 [{{.j}}] 必须唯一",
+	"ArrayContains": "至少有一个元素必须匹配",
+	"ArrayMinProperties": "至少需要 {{.min}} 个属性",
+	"ArrayMaxProperties": "最多只能有 {{.max}} 个属性",
+	"AdditionalPropertyNotAllowed": "不允许额外的属性 {{.property}}",
+	"InvalidPropertyPattern": "属性 \"{{.property}}\" 不匹配模式 {{.pattern}}",
+	"InvalidPropertyName": "属性名 \"{{.property}}\" 不匹配",
+	"StringGTE": "字符串长度必须大于或等于 {{.min}}",
+	"StringLTE": "字符串长度必须小于或等于 {{.max}}",
+	"DoesNotMatchPattern": "不匹配模式 '{{.pattern}}'",
+	"RegexStepLimit": "匹配模式 '{{.pattern}}' 超出了回溯步数限制",
+	"DoesNotMatchFormat": "不匹配格式 '{{.format}}'",
+	"MultipleOf": "必须是 {{.multiple}} 的倍数",
+	"NumberGTE": "必须大于或等于 {{.min}}",
+	"NumberGT": "必须大于 {{.min}}",
+	"NumberLTE": "必须小于或等于 {{.max}}",
+	"NumberLT": "必须小于 {{.max}}",
+	"Discriminator": "{{.property}} 必须是以下之一：{{.allowed}}",
+	"ConditionThen": "由于 \"if\" 有效，必须匹配 \"then\"",
+	"ConditionElse": "由于 \"if\" 无效，必须匹配 \"else\""
+}`
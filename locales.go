@@ -26,10 +26,13 @@
 package gojsonschema
 
 type (
-	// locale is an interface for defining custom error strings
-	locale interface {
+	// LocaleMessages is the interface of the locales, defining the error strings of a language.
+	// NewCatalogLocale implements it from a catalog of messages, falling back to English for the
+	// messages it does not define, and LocaleFor returns the bundled translations.
+	LocaleMessages interface {
 		Required() string
 		InvalidType() string
+		InvalidBSONType() string
 		NumberAnyOf() string
 		NumberOneOf() string
 		NumberAllOf() string
@@ -52,12 +55,14 @@ type (
 		StringGTE() string
 		StringLTE() string
 		DoesNotMatchPattern() string
+		RegexStepLimit() string
 		DoesNotMatchFormat() string
 		MultipleOf() string
 		NumberGTE() string
 		NumberGT() string
 		NumberLTE() string
 		NumberLT() string
+		Discriminator() string
 
 		// Schema validations
 		RegexPattern() string
@@ -78,6 +83,65 @@ type (
 		Duplicated() string
 		HttpBadStatus() string
 		ParseError() string
+		KeywordNotSupported() string
+		CannotBeUsedWith() string
+
+		// YAML conversion
+		YAMLKeyMustBeString() string
+		YAMLInvalidValue() string
+		YAMLInvalidMerge() string
+		YAMLRecursiveAlias() string
+		YAMLExcessiveAliasing() string
+
+		// BSON decoding
+		BSONParseError() string
+		ExtendedJSONParseError() string
+
+		// Regular expressions
+		RegexSyntaxError() string
+
+		// Meta-schemas
+		InvalidSchemaDocument() string
+		UnknownMetaSchema() string
+
+		// Go types reflection
+		ReflectUnsupportedType() string
+		ReflectInvalidTag() string
+
+		// Lint
+		LintRequiredNotAllowed() string
+		LintKeywordNotApplicable() string
+		LintInvalidValue() string
+		LintEmptyBranch() string
+		LintAlwaysValidBranch() string
+		LintIdenticalBranches() string
+		LintOverlappingBranches() string
+		LintUnusedDefinition() string
+
+		// Bundle
+		BundleUnresolvedReference() string
+
+		// Compare
+		CompareRequiredAdded() string
+		CompareRequiredRemoved() string
+		ComparePropertyAdded() string
+		ComparePropertyRemoved() string
+		CompareTypeChanged() string
+		CompareEnumChanged() string
+		CompareConstraintChanged() string
+		CompareReferenceChanged() string
+
+		// Sample
+		SampleNotGenerated() string
+		SampleDepthExceeded() string
+
+		// Keyword
+		KeywordReserved() string
+		KeywordInvalid() string
+
+		// Coercion
+		CoercionNotInPlace() string
+		CoercionNoGoDocument() string
 
 		ConditionThen() string
 		ConditionElse() string
@@ -98,12 +162,16 @@ func (l DefaultLocale) InvalidType() string {
 	return `Invalid type. Expected: {{.expected}}, given: {{.given}}`
 }
 
+func (l DefaultLocale) InvalidBSONType() string {
+	return `Invalid BSON type. Expected: {{.expected}}, given: {{.given}}`
+}
+
 func (l DefaultLocale) NumberAnyOf() string {
 	return `Must validate at least one schema (anyOf)`
 }
 
 func (l DefaultLocale) NumberOneOf() string {
-	return `Must validate one and only one schema (oneOf)`
+	return `Must validate one and only one schema (oneOf){{if .matched}}, but validates the schemas {{.matched}}{{end}}{{if .failures}}, errors of the schemas: {{.failures}}{{end}}`
 }
 
 func (l DefaultLocale) NumberAllOf() string {
@@ -147,7 +215,7 @@ func (l DefaultLocale) ArrayMaxItems() string {
 }
 
 func (l DefaultLocale) Unique() string {
-	return `{{.type}} items must be unique`
+	return `{{.type}} items[{{.i}}] and [{{.j}}] must be unique`
 }
 
 func (l DefaultLocale) ArrayContains() string {
@@ -186,6 +254,10 @@ func (l DefaultLocale) DoesNotMatchPattern() string {
 	return `Does not match pattern '{{.pattern}}'`
 }
 
+func (l DefaultLocale) RegexStepLimit() string {
+	return `Matching pattern '{{.pattern}}' exceeded the backtracking step limit`
+}
+
 func (l DefaultLocale) DoesNotMatchFormat() string {
 	return `Does not match format '{{.format}}'`
 }
@@ -210,6 +282,10 @@ func (l DefaultLocale) NumberLT() string {
 	return `Must be less than {{.max}}`
 }
 
+func (l DefaultLocale) Discriminator() string {
+	return `{{.property}} must be one of the following: {{.allowed}}`
+}
+
 // Schema validators
 func (l DefaultLocale) RegexPattern() string {
 	return `Invalid regex pattern '{{.pattern}}'`
@@ -275,6 +351,14 @@ func (l DefaultLocale) Duplicated() string {
 	return `{{.type}} type is duplicated`
 }
 
+func (l DefaultLocale) KeywordNotSupported() string {
+	return `{{.key}} is not supported by the {{.dialect}} dialect`
+}
+
+func (l DefaultLocale) CannotBeUsedWith() string {
+	return `{{.x}} cannot be used with {{.y}}`
+}
+
 func (l DefaultLocale) HttpBadStatus() string {
 	return `Could not read schema from HTTP, response status is {{.status}}`
 }
@@ -289,6 +373,157 @@ func (l DefaultLocale) ParseError() string {
 	return `Expected: {{.expected}}, given: Invalid JSON`
 }
 
+// YAML conversion
+func (l DefaultLocale) YAMLKeyMustBeString() string {
+	return `YAML line {{.line}}, column {{.column}}: mapping key {{.key}} must be a string`
+}
+
+func (l DefaultLocale) YAMLInvalidValue() string {
+	return `YAML line {{.line}}, column {{.column}}: {{.value}} cannot be represented in JSON`
+}
+
+func (l DefaultLocale) YAMLInvalidMerge() string {
+	return `YAML line {{.line}}, column {{.column}}: merge value must be a mapping or a sequence of mappings`
+}
+
+func (l DefaultLocale) YAMLRecursiveAlias() string {
+	return `YAML line {{.line}}, column {{.column}}: alias *{{.alias}} is recursive`
+}
+
+func (l DefaultLocale) YAMLExcessiveAliasing() string {
+	return `YAML line {{.line}}, column {{.column}}: document has excessive aliasing`
+}
+
+// BSON decoding
+func (l DefaultLocale) BSONParseError() string {
+	return `Invalid BSON at offset {{.offset}}: {{.reason}}`
+}
+
+func (l DefaultLocale) ExtendedJSONParseError() string {
+	return `Invalid Extended JSON at {{.context}}: {{.reason}}`
+}
+
+// Regular expressions
+func (l DefaultLocale) RegexSyntaxError() string {
+	return `Invalid regular expression '{{.pattern}}' at position {{.position}}: {{.reason}}`
+}
+
+// Meta-schemas
+func (l DefaultLocale) InvalidSchemaDocument() string {
+	return `Schema does not match its meta-schema: {{.errors}}`
+}
+
+func (l DefaultLocale) UnknownMetaSchema() string {
+	return `Meta-schema {{.uri}} is neither a draft meta-schema nor a registered schema`
+}
+
+// Go types reflection
+func (l DefaultLocale) ReflectUnsupportedType() string {
+	return `Type {{.type}} of {{.field}} cannot be represented in JSON`
+}
+
+func (l DefaultLocale) ReflectInvalidTag() string {
+	return `Invalid jsonschema tag of {{.field}}: {{.reason}}`
+}
+
+//Lint
+func (l DefaultLocale) LintRequiredNotAllowed() string {
+	return `{{.property}} is required but additionalProperties does not allow it`
+}
+
+func (l DefaultLocale) LintKeywordNotApplicable() string {
+	return `{{.keyword}} only applies to {{.applies}} values and the type is {{.type}}`
+}
+
+func (l DefaultLocale) LintInvalidValue() string {
+	return `{{.keyword}} value {{.value}} does not validate against its schema: {{.reason}}`
+}
+
+func (l DefaultLocale) LintEmptyBranch() string {
+	return `oneOf branch {{.branch}} never validates`
+}
+
+func (l DefaultLocale) LintAlwaysValidBranch() string {
+	return `oneOf branch {{.branch}} validates any value, no other branch can validate`
+}
+
+func (l DefaultLocale) LintIdenticalBranches() string {
+	return `oneOf branches {{.i}} and {{.j}} are identical`
+}
+
+func (l DefaultLocale) LintOverlappingBranches() string {
+	return `oneOf branches {{.i}} and {{.j}} both validate {{.value}}`
+}
+
+func (l DefaultLocale) LintUnusedDefinition() string {
+	return `Definition {{.definition}} is never referenced`
+}
+
+//Bundle
+func (l DefaultLocale) BundleUnresolvedReference() string {
+	return `Reference {{.reference}} cannot be resolved`
+}
+
+//Compare
+func (l DefaultLocale) CompareRequiredAdded() string {
+	return `{{.property}} is now required`
+}
+
+func (l DefaultLocale) CompareRequiredRemoved() string {
+	return `{{.property}} is no longer required`
+}
+
+func (l DefaultLocale) ComparePropertyAdded() string {
+	return `Property {{.property}} added`
+}
+
+func (l DefaultLocale) ComparePropertyRemoved() string {
+	return `Property {{.property}} removed`
+}
+
+func (l DefaultLocale) CompareTypeChanged() string {
+	return `Type changed from {{.old}} to {{.new}}`
+}
+
+func (l DefaultLocale) CompareEnumChanged() string {
+	return `{{.keyword}} changed, added: {{.added}}, removed: {{.removed}}`
+}
+
+func (l DefaultLocale) CompareConstraintChanged() string {
+	return `{{.keyword}} changed from {{.old}} to {{.new}}`
+}
+
+func (l DefaultLocale) CompareReferenceChanged() string {
+	return `$ref changed from {{.old}} to {{.new}}`
+}
+
+//Sample
+func (l DefaultLocale) SampleNotGenerated() string {
+	return `No valid value could be generated for {{.field}}`
+}
+
+func (l DefaultLocale) SampleDepthExceeded() string {
+	return `Sample nesting too deep at {{.field}}, the schema requires infinitely nested values`
+}
+
+//Keyword
+func (l DefaultLocale) KeywordReserved() string {
+	return `{{.keyword}} is a keyword of JSON Schema and cannot be registered`
+}
+
+func (l DefaultLocale) KeywordInvalid() string {
+	return `Invalid {{.keyword}}: {{.error}}`
+}
+
+//Coercion
+func (l DefaultLocale) CoercionNotInPlace() string {
+	return `The value at "{{.pointer}}" cannot be coerced in place`
+}
+
+func (l DefaultLocale) CoercionNoGoDocument() string {
+	return `Coercing in place needs the document of a Go loader`
+}
+
 //If/Else
 func (l DefaultLocale) ConditionThen() string {
 	return `Must validate "then" as "if" was valid`
@@ -304,6 +539,7 @@ const (
 	STRING_ARRAY_OF_SCHEMAS           = "array of schemas"
 	STRING_SCHEMA                     = "valid schema"
 	STRING_SCHEMA_OR_ARRAY_OF_STRINGS = "schema or array of strings"
+	STRING_STRING_OR_OBJECT           = "string or object of strings"
 	STRING_PROPERTIES                 = "properties"
 	STRING_DEPENDENCY                 = "dependency"
 	STRING_PROPERTY                   = "property"
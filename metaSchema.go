@@ -0,0 +1,636 @@
+package gojsonschema
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/xeipuuv/gojsonreference"
+)
+
+// Meta-schemas
+// The draft-04, draft-06 and draft-07 meta-schemas are embedded, so references to them and the
+// validation of schema documents work offline.
+
+// URIs of the embedded meta-schemas, without their empty fragment
+const (
+	Draft4MetaSchemaURI = "http://json-schema.org/draft-04/schema"
+	Draft6MetaSchemaURI = "http://json-schema.org/draft-06/schema"
+	Draft7MetaSchemaURI = "http://json-schema.org/draft-07/schema"
+)
+
+var embeddedMetaSchemas = map[string]string{
+	Draft4MetaSchemaURI: draft04MetaSchema,
+	Draft6MetaSchemaURI: draft06MetaSchema,
+	Draft7MetaSchemaURI: draft07MetaSchema,
+}
+
+// compiled embedded meta-schemas, by URI
+var metaSchemas = struct {
+	sync.Mutex
+	schemas map[string]*Schema
+}{schemas: map[string]*Schema{}}
+
+// embeddedMetaSchemaURI returns the URI of an embedded meta-schema without its empty fragment, and
+// false for the other URIs
+func embeddedMetaSchemaURI(uri string) (string, bool) {
+	uri = strings.TrimSuffix(uri, "#")
+	if strings.HasPrefix(uri, "https://") {
+		uri = "http://" + strings.TrimPrefix(uri, "https://")
+	}
+	_, ok := embeddedMetaSchemas[uri]
+	return uri, ok
+}
+
+// embeddedMetaSchema returns the embedded meta-schema of an URI, with or without its empty fragment
+func embeddedMetaSchema(uri string) (string, bool) {
+	uri, ok := embeddedMetaSchemaURI(uri)
+	return embeddedMetaSchemas[uri], ok
+}
+
+// metaSchemaURI returns the URI of the meta-schema of a schema document: its $schema, or the one of the draft
+func metaSchemaURI(doc interface{}, draft Draft) (string, error) {
+
+	if m, ok := doc.(map[string]interface{}); ok && existsMapKey(m, KEY_SCHEMA) {
+		uri, ok := m[KEY_SCHEMA].(string)
+		if !ok {
+			return "", errors.New(formatErrorDescription(
+				Locale.InvalidType(),
+				ErrorDetails{"expected": TYPE_STRING, "given": KEY_SCHEMA},
+			))
+		}
+		return uri, nil
+	}
+
+	switch draft {
+	case Draft4:
+		return Draft4MetaSchemaURI, nil
+	case Draft6:
+		return Draft6MetaSchemaURI, nil
+	}
+	return Draft7MetaSchemaURI, nil
+}
+
+// compileMetaSchema compiles the meta-schema of an URI: an embedded one, compiled once, or one of the
+// documents registered with AddSchema. Other URIs are not loaded, the $schema of a document must not
+// make the validation read files or the network.
+func compileMetaSchema(uri string, documents map[string]interface{}) (*Schema, error) {
+
+	if key, ok := embeddedMetaSchemaURI(uri); ok {
+		metaSchemas.Lock()
+		defer metaSchemas.Unlock()
+
+		if schema, ok := metaSchemas.schemas[key]; ok {
+			return schema, nil
+		}
+		schema, err := NewSchema(NewStringLoader(embeddedMetaSchemas[key]))
+		if err != nil {
+			return nil, err
+		}
+		metaSchemas.schemas[key] = schema
+		return schema, nil
+	}
+
+	if ref, err := gojsonreference.NewJsonReference(uri); err == nil && ref.IsCanonical() {
+		ref.GetUrl().Fragment = ""
+		if _, ok := documents[ref.String()]; ok {
+			sl := NewSchemaLoader()
+			sl.documents = documents
+			return sl.Compile(NewReferenceLoader(ref.String()))
+		}
+	}
+
+	return nil, errors.New(formatErrorDescription(
+		Locale.UnknownMetaSchema(),
+		ErrorDetails{"uri": uri},
+	))
+}
+
+// validateSchemaDocument validates a schema document against its meta-schema, looked up in the documents
+// registered with AddSchema when it is not embedded
+func validateSchemaDocument(doc interface{}, draft Draft, documents map[string]interface{}) (*Result, error) {
+
+	uri, err := metaSchemaURI(doc, draft)
+	if err != nil {
+		return nil, err
+	}
+
+	metaSchema, err := compileMetaSchema(uri, documents)
+	if err != nil {
+		return nil, err
+	}
+
+	return metaSchema.validateDocument(doc), nil
+}
+
+// ValidateSchemaDocument validates a schema document against the meta-schema named by its $schema,
+// draft-07 by default. Only the embedded draft-04, draft-06 and draft-07 meta-schemas are known, see
+// SchemaLoader.Validate for others.
+func ValidateSchemaDocument(l JSONLoader) (*Result, error) {
+
+	doc, err := l.LoadJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	return validateSchemaDocument(convertDocumentNode(doc), Hybrid, nil)
+}
+
+// InvalidSchemaError is returned when compiling a schema document that does not match its meta-schema,
+// with all the violations
+type InvalidSchemaError struct {
+	Result *Result
+}
+
+func (e *InvalidSchemaError) Error() string {
+	var violations []string
+	for _, violation := range e.Result.Errors() {
+		violations = append(violations, violation.String())
+	}
+	return formatErrorDescription(
+		Locale.InvalidSchemaDocument(),
+		ErrorDetails{"errors": strings.Join(violations, "; ")},
+	)
+}
+
+// draft04MetaSchema is http://json-schema.org/draft-04/schema
+const draft04MetaSchema = `{
+	"id": "http://json-schema.org/draft-04/schema#",
+	"$schema": "http://json-schema.org/draft-04/schema#",
+	"description": "Core schema meta-schema",
+	"definitions": {
+		"schemaArray": {
+			"type": "array",
+			"minItems": 1,
+			"items": { "$ref": "#" }
+		},
+		"positiveInteger": {
+			"type": "integer",
+			"minimum": 0
+		},
+		"positiveIntegerDefault0": {
+			"allOf": [ { "$ref": "#/definitions/positiveInteger" }, { "default": 0 } ]
+		},
+		"simpleTypes": {
+			"enum": [ "array", "boolean", "integer", "null", "number", "object", "string" ]
+		},
+		"stringArray": {
+			"type": "array",
+			"items": { "type": "string" },
+			"minItems": 1,
+			"uniqueItems": true
+		}
+	},
+	"type": "object",
+	"properties": {
+		"id": {
+			"type": "string"
+		},
+		"$schema": {
+			"type": "string"
+		},
+		"title": {
+			"type": "string"
+		},
+		"description": {
+			"type": "string"
+		},
+		"default": {},
+		"multipleOf": {
+			"type": "number",
+			"minimum": 0,
+			"exclusiveMinimum": true
+		},
+		"maximum": {
+			"type": "number"
+		},
+		"exclusiveMaximum": {
+			"type": "boolean",
+			"default": false
+		},
+		"minimum": {
+			"type": "number"
+		},
+		"exclusiveMinimum": {
+			"type": "boolean",
+			"default": false
+		},
+		"maxLength": { "$ref": "#/definitions/positiveInteger" },
+		"minLength": { "$ref": "#/definitions/positiveIntegerDefault0" },
+		"pattern": {
+			"type": "string",
+			"format": "regex"
+		},
+		"additionalItems": {
+			"anyOf": [
+				{ "type": "boolean" },
+				{ "$ref": "#" }
+			],
+			"default": {}
+		},
+		"items": {
+			"anyOf": [
+				{ "$ref": "#" },
+				{ "$ref": "#/definitions/schemaArray" }
+			],
+			"default": {}
+		},
+		"maxItems": { "$ref": "#/definitions/positiveInteger" },
+		"minItems": { "$ref": "#/definitions/positiveIntegerDefault0" },
+		"uniqueItems": {
+			"type": "boolean",
+			"default": false
+		},
+		"maxProperties": { "$ref": "#/definitions/positiveInteger" },
+		"minProperties": { "$ref": "#/definitions/positiveIntegerDefault0" },
+		"required": { "$ref": "#/definitions/stringArray" },
+		"additionalProperties": {
+			"anyOf": [
+				{ "type": "boolean" },
+				{ "$ref": "#" }
+			],
+			"default": {}
+		},
+		"definitions": {
+			"type": "object",
+			"additionalProperties": { "$ref": "#" },
+			"default": {}
+		},
+		"properties": {
+			"type": "object",
+			"additionalProperties": { "$ref": "#" },
+			"default": {}
+		},
+		"patternProperties": {
+			"type": "object",
+			"additionalProperties": { "$ref": "#" },
+			"default": {}
+		},
+		"dependencies": {
+			"type": "object",
+			"additionalProperties": {
+				"anyOf": [
+					{ "$ref": "#" },
+					{ "$ref": "#/definitions/stringArray" }
+				]
+			}
+		},
+		"enum": {
+			"type": "array",
+			"minItems": 1,
+			"uniqueItems": true
+		},
+		"type": {
+			"anyOf": [
+				{ "$ref": "#/definitions/simpleTypes" },
+				{
+					"type": "array",
+					"items": { "$ref": "#/definitions/simpleTypes" },
+					"minItems": 1,
+					"uniqueItems": true
+				}
+			]
+		},
+		"format": { "type": "string" },
+		"allOf": { "$ref": "#/definitions/schemaArray" },
+		"anyOf": { "$ref": "#/definitions/schemaArray" },
+		"oneOf": { "$ref": "#/definitions/schemaArray" },
+		"not": { "$ref": "#" }
+	},
+	"dependencies": {
+		"exclusiveMaximum": [ "maximum" ],
+		"exclusiveMinimum": [ "minimum" ]
+	},
+	"default": {}
+}
+`
+
+// draft06MetaSchema is http://json-schema.org/draft-06/schema
+const draft06MetaSchema = `{
+	"$schema": "http://json-schema.org/draft-06/schema#",
+	"$id": "http://json-schema.org/draft-06/schema#",
+	"title": "Core schema meta-schema",
+	"definitions": {
+		"schemaArray": {
+			"type": "array",
+			"minItems": 1,
+			"items": { "$ref": "#" }
+		},
+		"nonNegativeInteger": {
+			"type": "integer",
+			"minimum": 0
+		},
+		"nonNegativeIntegerDefault0": {
+			"allOf": [
+				{ "$ref": "#/definitions/nonNegativeInteger" },
+				{ "default": 0 }
+			]
+		},
+		"simpleTypes": {
+			"enum": [
+				"array",
+				"boolean",
+				"integer",
+				"null",
+				"number",
+				"object",
+				"string"
+			]
+		},
+		"stringArray": {
+			"type": "array",
+			"items": { "type": "string" },
+			"uniqueItems": true,
+			"default": []
+		}
+	},
+	"type": ["object", "boolean"],
+	"properties": {
+		"$id": {
+			"type": "string",
+			"format": "uri-reference"
+		},
+		"$schema": {
+			"type": "string",
+			"format": "uri"
+		},
+		"$ref": {
+			"type": "string",
+			"format": "uri-reference"
+		},
+		"title": {
+			"type": "string"
+		},
+		"description": {
+			"type": "string"
+		},
+		"default": {},
+		"examples": {
+			"type": "array",
+			"items": {}
+		},
+		"multipleOf": {
+			"type": "number",
+			"exclusiveMinimum": 0
+		},
+		"maximum": {
+			"type": "number"
+		},
+		"exclusiveMaximum": {
+			"type": "number"
+		},
+		"minimum": {
+			"type": "number"
+		},
+		"exclusiveMinimum": {
+			"type": "number"
+		},
+		"maxLength": { "$ref": "#/definitions/nonNegativeInteger" },
+		"minLength": { "$ref": "#/definitions/nonNegativeIntegerDefault0" },
+		"pattern": {
+			"type": "string",
+			"format": "regex"
+		},
+		"additionalItems": { "$ref": "#" },
+		"items": {
+			"anyOf": [
+				{ "$ref": "#" },
+				{ "$ref": "#/definitions/schemaArray" }
+			],
+			"default": {}
+		},
+		"maxItems": { "$ref": "#/definitions/nonNegativeInteger" },
+		"minItems": { "$ref": "#/definitions/nonNegativeIntegerDefault0" },
+		"uniqueItems": {
+			"type": "boolean",
+			"default": false
+		},
+		"contains": { "$ref": "#" },
+		"maxProperties": { "$ref": "#/definitions/nonNegativeInteger" },
+		"minProperties": { "$ref": "#/definitions/nonNegativeIntegerDefault0" },
+		"required": { "$ref": "#/definitions/stringArray" },
+		"additionalProperties": { "$ref": "#" },
+		"definitions": {
+			"type": "object",
+			"additionalProperties": { "$ref": "#" },
+			"default": {}
+		},
+		"properties": {
+			"type": "object",
+			"additionalProperties": { "$ref": "#" },
+			"default": {}
+		},
+		"patternProperties": {
+			"type": "object",
+			"additionalProperties": { "$ref": "#" },
+			"default": {}
+		},
+		"dependencies": {
+			"type": "object",
+			"additionalProperties": {
+				"anyOf": [
+					{ "$ref": "#" },
+					{ "$ref": "#/definitions/stringArray" }
+				]
+			}
+		},
+		"propertyNames": { "$ref": "#" },
+		"const": {},
+		"enum": {
+			"type": "array",
+			"minItems": 1,
+			"uniqueItems": true
+		},
+		"type": {
+			"anyOf": [
+				{ "$ref": "#/definitions/simpleTypes" },
+				{
+					"type": "array",
+					"items": { "$ref": "#/definitions/simpleTypes" },
+					"minItems": 1,
+					"uniqueItems": true
+				}
+			]
+		},
+		"format": { "type": "string" },
+		"allOf": { "$ref": "#/definitions/schemaArray" },
+		"anyOf": { "$ref": "#/definitions/schemaArray" },
+		"oneOf": { "$ref": "#/definitions/schemaArray" },
+		"not": { "$ref": "#" }
+	},
+	"default": {}
+}
+`
+
+// draft07MetaSchema is http://json-schema.org/draft-07/schema
+const draft07MetaSchema = `{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"$id": "http://json-schema.org/draft-07/schema#",
+	"title": "Core schema meta-schema",
+	"definitions": {
+		"schemaArray": {
+			"type": "array",
+			"minItems": 1,
+			"items": { "$ref": "#" }
+		},
+		"nonNegativeInteger": {
+			"type": "integer",
+			"minimum": 0
+		},
+		"nonNegativeIntegerDefault0": {
+			"allOf": [
+				{ "$ref": "#/definitions/nonNegativeInteger" },
+				{ "default": 0 }
+			]
+		},
+		"simpleTypes": {
+			"enum": [
+				"array",
+				"boolean",
+				"integer",
+				"null",
+				"number",
+				"object",
+				"string"
+			]
+		},
+		"stringArray": {
+			"type": "array",
+			"items": { "type": "string" },
+			"uniqueItems": true,
+			"default": []
+		}
+	},
+	"type": ["object", "boolean"],
+	"properties": {
+		"$id": {
+			"type": "string",
+			"format": "uri-reference"
+		},
+		"$schema": {
+			"type": "string",
+			"format": "uri"
+		},
+		"$ref": {
+			"type": "string",
+			"format": "uri-reference"
+		},
+		"$comment": {
+			"type": "string"
+		},
+		"title": {
+			"type": "string"
+		},
+		"description": {
+			"type": "string"
+		},
+		"default": true,
+		"readOnly": {
+			"type": "boolean",
+			"default": false
+		},
+		"writeOnly": {
+			"type": "boolean",
+			"default": false
+		},
+		"examples": {
+			"type": "array",
+			"items": true
+		},
+		"multipleOf": {
+			"type": "number",
+			"exclusiveMinimum": 0
+		},
+		"maximum": {
+			"type": "number"
+		},
+		"exclusiveMaximum": {
+			"type": "number"
+		},
+		"minimum": {
+			"type": "number"
+		},
+		"exclusiveMinimum": {
+			"type": "number"
+		},
+		"maxLength": { "$ref": "#/definitions/nonNegativeInteger" },
+		"minLength": { "$ref": "#/definitions/nonNegativeIntegerDefault0" },
+		"pattern": {
+			"type": "string",
+			"format": "regex"
+		},
+		"additionalItems": { "$ref": "#" },
+		"items": {
+			"anyOf": [
+				{ "$ref": "#" },
+				{ "$ref": "#/definitions/schemaArray" }
+			],
+			"default": true
+		},
+		"maxItems": { "$ref": "#/definitions/nonNegativeInteger" },
+		"minItems": { "$ref": "#/definitions/nonNegativeIntegerDefault0" },
+		"uniqueItems": {
+			"type": "boolean",
+			"default": false
+		},
+		"contains": { "$ref": "#" },
+		"maxProperties": { "$ref": "#/definitions/nonNegativeInteger" },
+		"minProperties": { "$ref": "#/definitions/nonNegativeIntegerDefault0" },
+		"required": { "$ref": "#/definitions/stringArray" },
+		"additionalProperties": { "$ref": "#" },
+		"definitions": {
+			"type": "object",
+			"additionalProperties": { "$ref": "#" },
+			"default": {}
+		},
+		"properties": {
+			"type": "object",
+			"additionalProperties": { "$ref": "#" },
+			"default": {}
+		},
+		"patternProperties": {
+			"type": "object",
+			"additionalProperties": { "$ref": "#" },
+			"propertyNames": { "format": "regex" },
+			"default": {}
+		},
+		"dependencies": {
+			"type": "object",
+			"additionalProperties": {
+				"anyOf": [
+					{ "$ref": "#" },
+					{ "$ref": "#/definitions/stringArray" }
+				]
+			}
+		},
+		"propertyNames": { "$ref": "#" },
+		"const": true,
+		"enum": {
+			"type": "array",
+			"items": true
+		},
+		"type": {
+			"anyOf": [
+				{ "$ref": "#/definitions/simpleTypes" },
+				{
+					"type": "array",
+					"items": { "$ref": "#/definitions/simpleTypes" },
+					"minItems": 1,
+					"uniqueItems": true
+				}
+			]
+		},
+		"format": { "type": "string" },
+		"contentMediaType": { "type": "string" },
+		"contentEncoding": { "type": "string" },
+		"if": { "$ref": "#" },
+		"then": { "$ref": "#" },
+		"else": { "$ref": "#" },
+		"allOf": { "$ref": "#/definitions/schemaArray" },
+		"anyOf": { "$ref": "#/definitions/schemaArray" },
+		"oneOf": { "$ref": "#/definitions/schemaArray" },
+		"not": { "$ref": "#" }
+	},
+	"default": true
+}
+`
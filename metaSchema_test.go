@@ -0,0 +1,131 @@
+package gojsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSchemaDocument(t *testing.T) {
+	result, err := ValidateSchemaDocument(NewStringLoader(`{
+		"type": "object",
+		"properties": {"name": {"type": "string", "minLength": 1}},
+		"required": ["name"]
+	}`))
+	if assert.Nil(t, err) {
+		assert.True(t, result.Valid(), "%v", result.Errors())
+	}
+
+	// all the violations are reported
+	result, err = ValidateSchemaDocument(NewStringLoader(`{
+		"allOf": [{"properties": "name"}],
+		"minLength": -1,
+		"type": "text",
+		"required": "name"
+	}`))
+	if assert.Nil(t, err) {
+		var contexts []string
+		for _, e := range result.Errors() {
+			contexts = append(contexts, e.Context().String())
+		}
+		assert.Subset(t, contexts, []string{"(root).allOf.0.properties", "(root).minLength", "(root).type", "(root).required"})
+	}
+
+	// the meta-schema of $schema
+	draft4 := `{"$schema": "http://json-schema.org/draft-04/schema#", "minimum": 1, "exclusiveMinimum": 1}`
+	result, err = ValidateSchemaDocument(NewStringLoader(draft4))
+	if assert.Nil(t, err) {
+		assert.False(t, result.Valid())
+		assert.Equal(t, "(root).exclusiveMinimum", result.Errors()[0].Context().String())
+	}
+	result, err = ValidateSchemaDocument(NewStringLoader(`{"$schema": "https://json-schema.org/draft-06/schema", "minimum": 1, "exclusiveMinimum": 1}`))
+	if assert.Nil(t, err) {
+		assert.True(t, result.Valid(), "%v", result.Errors())
+	}
+
+	_, err = ValidateSchemaDocument(NewStringLoader(`{"$schema": 7}`))
+	assert.NotNil(t, err)
+	_, err = ValidateSchemaDocument(NewStringLoader(`{`))
+	assert.NotNil(t, err)
+}
+
+func TestMetaSchemasAreValid(t *testing.T) {
+	for uri, source := range embeddedMetaSchemas {
+		result, err := ValidateSchemaDocument(NewStringLoader(source))
+		if assert.Nil(t, err, uri) {
+			assert.True(t, result.Valid(), "%s: %v", uri, result.Errors())
+		}
+	}
+}
+
+func TestMetaSchemaReference(t *testing.T) {
+	// the meta-schemas are not downloaded
+	schema, err := NewSchema(NewStringLoader(`{"$ref": "http://json-schema.org/draft-07/schema#"}`))
+	if !assert.Nil(t, err) {
+		return
+	}
+	result, err := schema.Validate(NewStringLoader(`{"type": "object"}`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+	result, err = schema.Validate(NewStringLoader(`{"type": 1}`))
+	assert.Nil(t, err)
+	assert.False(t, result.Valid())
+}
+
+func TestSchemaLoaderValidate(t *testing.T) {
+	document := `{"properties": {"a": {"type": "string", "maxLength": "10"}, "b": {"items": 1}}}`
+
+	sl := NewSchemaLoader()
+	_, err := sl.Compile(NewStringLoader(`{"properties": {"b": {"items": 1}}}`))
+	assert.NotNil(t, err)
+
+	sl.Validate = true
+	_, err = sl.Compile(NewStringLoader(document))
+	if assert.IsType(t, &InvalidSchemaError{}, err) {
+		result := err.(*InvalidSchemaError).Result
+		var contexts []string
+		for _, e := range result.Errors() {
+			contexts = append(contexts, e.Context().String())
+		}
+		assert.Subset(t, contexts, []string{"(root).properties.maxLength", "(root).properties.items"})
+		assert.Contains(t, err.Error(), "Schema does not match its meta-schema: ")
+	}
+
+	schema, err := sl.Compile(NewStringLoader(`{"type": "string", "maxLength": 10}`))
+	if assert.Nil(t, err) {
+		result, err := schema.Validate(NewStringLoader(`"abc"`))
+		assert.Nil(t, err)
+		assert.True(t, result.Valid())
+	}
+
+	// the draft selects the meta-schema of the documents without $schema
+	sl.Draft = Draft4
+	_, err = sl.Compile(NewStringLoader(`{"minimum": 1, "exclusiveMinimum": 1}`))
+	assert.IsType(t, &InvalidSchemaError{}, err)
+}
+
+func TestUnknownMetaSchema(t *testing.T) {
+	// the other meta-schemas are not loaded
+	_, err := ValidateSchemaDocument(NewStringLoader(`{"$schema": "file:///etc/passwd"}`))
+	assert.EqualError(t, err, "Meta-schema file:///etc/passwd is neither a draft meta-schema nor a registered schema")
+
+	sl := NewSchemaLoader()
+	sl.Validate = true
+	_, err = sl.Compile(NewStringLoader(`{"$schema": "http://example.com/meta", "minimum": "1"}`))
+	assert.EqualError(t, err, "Meta-schema http://example.com/meta is neither a draft meta-schema nor a registered schema")
+
+	// unless they are registered
+	err = sl.AddSchema("http://example.com/meta", NewStringLoader(`{
+		"allOf": [{"$ref": "http://json-schema.org/draft-07/schema#"}],
+		"required": ["title"]
+	}`))
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, err = sl.Compile(NewStringLoader(`{"$schema": "http://example.com/meta#", "type": "string"}`))
+	if assert.IsType(t, &InvalidSchemaError{}, err) {
+		assert.Equal(t, "title", err.(*InvalidSchemaError).Result.Errors()[0].Details()["property"])
+	}
+	_, err = sl.Compile(NewStringLoader(`{"$schema": "http://example.com/meta#", "title": "Name", "type": "string"}`))
+	assert.Nil(t, err)
+}
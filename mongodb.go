@@ -0,0 +1,118 @@
+package gojsonschema
+
+import (
+	"errors"
+	"reflect"
+)
+
+const (
+	KEY_BSON_TYPE   = "bsonType"
+	KEY_JSON_SCHEMA = "$jsonSchema"
+)
+
+// Keywords accepted by the $jsonSchema query operator and collection validators of MongoDB.
+// The server rejects every other keyword, including $ref, $schema, definitions, format, default and id.
+var mongoDBKeywords = []string{
+	KEY_ADDITIONAL_ITEMS,
+	KEY_ADDITIONAL_PROPERTIES,
+	KEY_ALL_OF,
+	KEY_ANY_OF,
+	KEY_BSON_TYPE,
+	KEY_DEPENDENCIES,
+	KEY_DESCRIPTION,
+	KEY_ENUM,
+	KEY_EXCLUSIVE_MAXIMUM,
+	KEY_EXCLUSIVE_MINIMUM,
+	KEY_ITEMS,
+	KEY_MAXIMUM,
+	KEY_MAX_ITEMS,
+	KEY_MAX_LENGTH,
+	KEY_MAX_PROPERTIES,
+	KEY_MINIMUM,
+	KEY_MIN_ITEMS,
+	KEY_MIN_LENGTH,
+	KEY_MIN_PROPERTIES,
+	KEY_MULTIPLE_OF,
+	KEY_NOT,
+	KEY_ONE_OF,
+	KEY_PATTERN,
+	KEY_PATTERN_PROPERTIES,
+	KEY_PROPERTIES,
+	KEY_REQUIRED,
+	KEY_TITLE,
+	KEY_TYPE,
+	KEY_UNIQUE_ITEMS,
+}
+
+// unwrapMongoDBValidator accepts a whole validator document, i.e. {"$jsonSchema": {...}}
+func unwrapMongoDBValidator(document interface{}) interface{} {
+	if m, ok := document.(map[string]interface{}); ok && len(m) == 1 {
+		if jsonSchema, ok := m[KEY_JSON_SCHEMA]; ok {
+			return jsonSchema
+		}
+	}
+	return document
+}
+
+// checkMongoDBKeywords rejects the schemas the MongoDB server would refuse
+func checkMongoDBKeywords(m map[string]interface{}) error {
+
+	for k := range m {
+		if !isStringInSlice(mongoDBKeywords, k) {
+			return errors.New(formatErrorDescription(
+				Locale.KeywordNotSupported(),
+				ErrorDetails{"key": k, "dialect": "MongoDB $jsonSchema"},
+			))
+		}
+	}
+
+	if existsMapKey(m, KEY_TYPE) && existsMapKey(m, KEY_BSON_TYPE) {
+		return errors.New(formatErrorDescription(
+			Locale.CannotBeUsedWith(),
+			ErrorDetails{"x": KEY_TYPE, "y": KEY_BSON_TYPE},
+		))
+	}
+
+	// draft-06 numeric exclusive bounds are not supported
+	for _, k := range []string{KEY_EXCLUSIVE_MINIMUM, KEY_EXCLUSIVE_MAXIMUM} {
+		if existsMapKey(m, k) && !isKind(m[k], reflect.Bool) {
+			return errors.New(formatErrorDescription(
+				Locale.MustBeOfA(),
+				ErrorDetails{"x": k, "y": TYPE_BOOLEAN},
+			))
+		}
+	}
+
+	return nil
+}
+
+// parseBSONType parses the bsonType keyword, a string or an array of strings
+func parseBSONType(value interface{}, currentSchema *subSchema) error {
+
+	if isKind(value, reflect.String) {
+		return currentSchema.bsonTypes.Add(value.(string))
+	}
+
+	if isKind(value, reflect.Slice) {
+		for _, typeInArray := range value.([]interface{}) {
+			if !isKind(typeInArray, reflect.String) {
+				break
+			}
+			err := currentSchema.bsonTypes.Add(typeInArray.(string))
+			if err != nil {
+				return err
+			}
+		}
+		if len(currentSchema.bsonTypes.types) == len(value.([]interface{})) {
+			return nil
+		}
+	}
+
+	return errors.New(formatErrorDescription(
+		Locale.InvalidType(),
+		ErrorDetails{
+			"expected": TYPE_STRING + "/" + STRING_ARRAY_OF_STRINGS,
+			"given":    KEY_BSON_TYPE,
+		},
+	))
+}
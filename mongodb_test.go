@@ -0,0 +1,153 @@
+package gojsonschema
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func compileMongoDB(t *testing.T, schema string) *Schema {
+	sl := NewSchemaLoader()
+	sl.Dialect = DialectMongoDB
+	s, err := sl.Compile(NewStringLoader(schema))
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	return s
+}
+
+func TestMongoDBRejectedKeywords(t *testing.T) {
+	sl := NewSchemaLoader()
+	sl.Dialect = DialectMongoDB
+
+	schemas := []string{
+		`{"$ref": "#/definitions/a", "definitions": {"a": {}}}`,
+		`{"properties": {"a": {"format": "email"}}}`,
+		`{"properties": {"a": {"default": 1}}}`,
+		`{"$schema": "http://json-schema.org/draft-04/schema#"}`,
+		`{"id": "http://example.com/schema"}`,
+		`{"const": 1}`,
+		`{"type": "integer"}`,
+		`{"type": "object", "bsonType": "object"}`,
+		`{"minimum": 0, "exclusiveMinimum": 1}`,
+		`{"bsonType": "uuid"}`,
+	}
+
+	for _, schema := range schemas {
+		_, err := sl.Compile(NewStringLoader(schema))
+		assert.NotNil(t, err, "schema: %s", schema)
+	}
+
+	// the same schemas are fine as plain JSON Schema
+	_, err := NewSchema(NewStringLoader(`{"properties": {"a": {"format": "email", "default": "a@b.c"}}}`))
+	assert.Nil(t, err)
+}
+
+func TestMongoDBValidatorDocument(t *testing.T) {
+	schema := compileMongoDB(t, `{
+		"$jsonSchema": {
+			"bsonType": "object",
+			"required": ["_id", "name", "created"],
+			"properties": {
+				"_id": {"bsonType": "objectId"},
+				"name": {"bsonType": "string", "minLength": 1},
+				"created": {"bsonType": "date"},
+				"count": {"bsonType": ["int", "long"], "minimum": 0},
+				"price": {"bsonType": "decimal", "maximum": 100},
+				"score": {"bsonType": "number"},
+				"tags": {"bsonType": "array", "items": {"bsonType": "string"}}
+			}
+		}
+	}`)
+
+	id, err := ObjectIDFromHex("5a934e000102030405000000")
+	assert.Nil(t, err)
+
+	result, err := schema.Validate(NewRawLoader(map[string]interface{}{
+		"_id":     id,
+		"name":    "widget",
+		"created": NewDateTimeFromTime(time.Date(2018, 2, 26, 0, 0, 0, 0, time.UTC)),
+		"count":   int64(3),
+		"price":   NewDecimal128(uint64(6176-2)<<49, 1999),
+		"score":   1.5,
+		"tags":    []interface{}{"a", "b"},
+	}))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid(), "%v", result.Errors())
+
+	result, err = schema.Validate(NewRawLoader(map[string]interface{}{
+		"_id":     "5a934e000102030405000000",
+		"name":    "widget",
+		"created": time.Date(2018, 2, 26, 0, 0, 0, 0, time.UTC),
+		"count":   int32(-1),
+		"price":   NewDecimal128(uint64(6176)<<49, 1999),
+		"score":   "high",
+	}))
+	assert.Nil(t, err)
+
+	types := map[string]string{}
+	for _, e := range result.Errors() {
+		types[e.Field()] = e.Type()
+	}
+	assert.Equal(t, map[string]string{
+		"_id":   "invalid_bson_type",
+		"count": "number_gte",
+		"price": "number_lte",
+		"score": "invalid_bson_type",
+	}, types)
+}
+
+func TestMongoDBJSONTypes(t *testing.T) {
+	schema := compileMongoDB(t, `{
+		"properties": {
+			"a": {"type": "number"},
+			"b": {"type": "string"},
+			"c": {"bsonType": "int"},
+			"d": {"bsonType": "long"},
+			"e": {"bsonType": "double"}
+		}
+	}`)
+
+	result, err := schema.Validate(NewRawLoader(map[string]interface{}{
+		"a": int32(1),
+		"b": "x",
+		"c": json.Number("42"),
+		"d": json.Number("4294967296"),
+		"e": json.Number("1.0"),
+	}))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid(), "%v", result.Errors())
+
+	// an ObjectId is not a JSON string, nor a long an int
+	result, err = schema.Validate(NewRawLoader(map[string]interface{}{
+		"b": ObjectID{},
+		"c": int64(1),
+	}))
+	assert.Nil(t, err)
+	assert.Len(t, result.Errors(), 2)
+}
+
+func TestDecimal128String(t *testing.T) {
+	testCases := []struct {
+		h, l     uint64
+		expected string
+	}{
+		{0x3040000000000000, 0, "0"},
+		{0x3040000000000000, 1, "1"},
+		{0xB040000000000000, 1, "-1"},
+		{uint64(6176-2) << 49, 1999, "19.99"},
+		{uint64(6176-5) << 49, 12, "0.00012"},
+		{uint64(6176-12) << 49, 1, "1E-12"},
+		{uint64(6176+3) << 49, 1, "1E+3"},
+		{uint64(6176+3) << 49, 12, "1.2E+4"},
+		{0x7C00000000000000, 0, "NaN"},
+		{0x7800000000000000, 0, "Infinity"},
+		{0xF800000000000000, 0, "-Infinity"},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, NewDecimal128(testCase.h, testCase.l).String())
+	}
+}
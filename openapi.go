@@ -0,0 +1,363 @@
+package gojsonschema
+
+import (
+	"encoding/base64"
+	"errors"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/xeipuuv/gojsonreference"
+)
+
+const (
+	KEY_NULLABLE      = "nullable"
+	KEY_DISCRIMINATOR = "discriminator"
+
+	openAPIDialectName    = "OpenAPI 3.0"
+	openAPISchemasPointer = "#/components/schemas/"
+)
+
+// Keywords of the Schema Object of OpenAPI 3.0, the other ones being only allowed as x- extensions
+var openAPIKeywords = []string{
+	KEY_REF,
+	KEY_TITLE,
+	KEY_DESCRIPTION,
+	KEY_TYPE,
+	KEY_FORMAT,
+	KEY_ENUM,
+	KEY_MULTIPLE_OF,
+	KEY_MINIMUM,
+	KEY_MAXIMUM,
+	KEY_EXCLUSIVE_MINIMUM,
+	KEY_EXCLUSIVE_MAXIMUM,
+	KEY_MIN_LENGTH,
+	KEY_MAX_LENGTH,
+	KEY_PATTERN,
+	KEY_ITEMS,
+	KEY_MIN_ITEMS,
+	KEY_MAX_ITEMS,
+	KEY_UNIQUE_ITEMS,
+	KEY_PROPERTIES,
+	KEY_ADDITIONAL_PROPERTIES,
+	KEY_MIN_PROPERTIES,
+	KEY_MAX_PROPERTIES,
+	KEY_REQUIRED,
+	KEY_ALL_OF,
+	KEY_ANY_OF,
+	KEY_ONE_OF,
+	KEY_NOT,
+	KEY_NULLABLE,
+	KEY_DISCRIMINATOR,
+	"default",
+	"example",
+	"readOnly",
+	"writeOnly",
+	"deprecated",
+	"xml",
+	"externalDocs",
+}
+
+// discriminator selects the branch of oneOf or anyOf validating an object by the value of one of
+// its properties, the name of a schema of the components or a key of the mapping. Without oneOf
+// nor anyOf, as for a base schema its subtypes extend with allOf, it only checks the value
+type discriminator struct {
+	propertyName string
+	// references of the schemas by value of the property
+	mapping map[string]string
+	// names of the schemas of the components of the document
+	schemaNames []string
+}
+
+// checkOpenAPIKeywords rejects the keywords of JSON Schema the Schema Object does not support
+func (d *Schema) checkOpenAPIKeywords(m map[string]interface{}) error {
+
+	for k := range m {
+		if !isStringInSlice(openAPIKeywords, k) && !strings.HasPrefix(k, "x-") && d.keywords[k] == nil {
+			return errors.New(formatErrorDescription(
+				Locale.KeywordNotSupported(),
+				ErrorDetails{"key": k, "dialect": openAPIDialectName},
+			))
+		}
+	}
+
+	// a single type, nullable allowing null
+	if existsMapKey(m, KEY_TYPE) && !isKind(m[KEY_TYPE], reflect.String) {
+		return errors.New(formatErrorDescription(
+			Locale.MustBeOfA(),
+			ErrorDetails{"x": KEY_TYPE, "y": TYPE_STRING},
+		))
+	}
+
+	// the bounds are made exclusive by a boolean
+	for _, k := range []string{KEY_EXCLUSIVE_MINIMUM, KEY_EXCLUSIVE_MAXIMUM} {
+		if existsMapKey(m, k) && !isKind(m[k], reflect.Bool) {
+			return errors.New(formatErrorDescription(
+				Locale.MustBeOfA(),
+				ErrorDetails{"x": k, "y": TYPE_BOOLEAN},
+			))
+		}
+	}
+
+	return nil
+}
+
+// parseOpenAPI parses the nullable and discriminator keywords and selects the formats of the dialect
+func (d *Schema) parseOpenAPI(m map[string]interface{}, currentSchema *subSchema) error {
+
+	currentSchema.dialectFormatCheckers = &OpenAPIFormatCheckers
+
+	if existsMapKey(m, KEY_NULLABLE) {
+		nullable, ok := m[KEY_NULLABLE].(bool)
+		if !ok {
+			return errors.New(formatErrorDescription(
+				Locale.MustBeOfA(),
+				ErrorDetails{"x": KEY_NULLABLE, "y": TYPE_BOOLEAN},
+			))
+		}
+		currentSchema.nullable = nullable
+	}
+
+	if existsMapKey(m, KEY_DISCRIMINATOR) {
+		invalid := errors.New(formatErrorDescription(
+			Locale.MustBeOfType(),
+			ErrorDetails{"key": KEY_DISCRIMINATOR, "type": TYPE_OBJECT},
+		))
+
+		node, ok := m[KEY_DISCRIMINATOR].(map[string]interface{})
+		if !ok {
+			return invalid
+		}
+		propertyName, ok := node["propertyName"].(string)
+		if !ok {
+			return invalid
+		}
+		currentSchema.discriminator = &discriminator{
+			propertyName: propertyName,
+			mapping:      map[string]string{},
+			schemaNames:  d.openAPISchemaNames(currentSchema),
+		}
+
+		if existsMapKey(node, "mapping") {
+			mapping, ok := node["mapping"].(map[string]interface{})
+			if !ok {
+				return invalid
+			}
+			for value, target := range mapping {
+				reference, ok := target.(string)
+				if !ok {
+					return invalid
+				}
+				// a mapping to a name stands for the schema of the components
+				if !strings.Contains(reference, "/") {
+					reference = openAPISchemasPointer + reference
+				}
+				ref, err := gojsonreference.NewJsonReference(reference)
+				if err != nil {
+					return err
+				}
+				inherited, err := currentSchema.id.Inherits(ref)
+				if err != nil {
+					return err
+				}
+				currentSchema.discriminator.mapping[value] = referenceString(inherited)
+			}
+		}
+	}
+
+	return nil
+}
+
+// openAPISchemaNames returns the names of the schemas of the components of the document of a subschema
+func (d *Schema) openAPISchemaNames(currentSchema *subSchema) []string {
+
+	document := d.pool.GetStandaloneDocument()
+	if currentSchema.id != nil {
+		u := *currentSchema.id.GetUrl()
+		u.Fragment = ""
+		if ref, err := gojsonreference.NewJsonReference(u.String()); err == nil && ref.IsCanonical() {
+			if spd, err := d.pool.GetDocument(ref); err == nil {
+				document = spd.Document
+			}
+		}
+	}
+
+	root, _ := document.(map[string]interface{})
+	components, _ := root["components"].(map[string]interface{})
+	schemas, _ := components["schemas"].(map[string]interface{})
+	return sortedMapKeys(schemas)
+}
+
+// validateDiscriminator validates an object against the branch of oneOf or anyOf its discriminator
+// selects, and reports whether it did
+func (v *subSchema) validateDiscriminator(currentSubSchema *subSchema, currentNode interface{}, result *Result, context *JsonContext) bool {
+
+	object, ok := currentNode.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	discriminator := currentSubSchema.discriminator
+
+	property, exists := object[discriminator.propertyName]
+	if !exists && isStringInSlice(currentSubSchema.required, discriminator.propertyName) {
+		// reported by required
+		return true
+	}
+	if !exists {
+		result.addInternalError(
+			new(RequiredError),
+			context,
+			currentNode,
+			ErrorDetails{"property": discriminator.propertyName},
+		)
+		return true
+	}
+
+	value, _ := property.(string)
+	branches := append(append([]*subSchema{}, currentSubSchema.oneOf...), currentSubSchema.anyOf...)
+	if len(branches) == 0 {
+		return v.validateDiscriminatorValue(currentSubSchema, property, result, context)
+	}
+	var allowed []string
+	for _, branch := range branches {
+		if branch.ref == nil || branch.refSchema == nil {
+			continue
+		}
+		reference := referenceString(branch.ref)
+		name := reference[strings.LastIndex(reference, "/")+1:]
+
+		target, mapped := discriminator.mapping[value]
+		if isKind(property, reflect.String) && (mapped && target == reference || !mapped && name == value) {
+			result.mergeErrors(branch.subValidateWithContext(currentNode, context))
+			return true
+		}
+		allowed = append(allowed, `"`+name+`"`)
+	}
+	for value := range discriminator.mapping {
+		if !isStringInSlice(allowed, `"`+value+`"`) {
+			allowed = append(allowed, `"`+value+`"`)
+		}
+	}
+	sort.Strings(allowed)
+
+	result.addInternalError(
+		new(DiscriminatorError),
+		context,
+		property,
+		ErrorDetails{"property": discriminator.propertyName, "allowed": strings.Join(allowed, ", ")},
+	)
+	return true
+}
+
+// validateDiscriminatorValue checks the value of the property of a discriminator without branches
+// is a key of its mapping or the name of a schema of the components
+func (v *subSchema) validateDiscriminatorValue(currentSubSchema *subSchema, property interface{}, result *Result, context *JsonContext) bool {
+
+	discriminator := currentSubSchema.discriminator
+	value, isString := property.(string)
+	if _, mapped := discriminator.mapping[value]; isString && (mapped || isStringInSlice(discriminator.schemaNames, value)) {
+		return true
+	}
+
+	var allowed []string
+	for _, name := range append(sortedMapKeys(discriminator.mapping), discriminator.schemaNames...) {
+		if !isStringInSlice(allowed, `"`+name+`"`) {
+			allowed = append(allowed, `"`+name+`"`)
+		}
+	}
+	sort.Strings(allowed)
+
+	result.addInternalError(
+		new(DiscriminatorError),
+		context,
+		property,
+		ErrorDetails{"property": discriminator.propertyName, "allowed": strings.Join(allowed, ", ")},
+	)
+	return true
+}
+
+// referenceString returns the text of a reference from its URL and its pointer, as loading the
+// document of a reference clears the fragment of its URL
+func referenceString(ref *gojsonreference.JsonReference) string {
+	u := *ref.GetUrl()
+	u.Fragment = ""
+	return u.String() + "#" + ref.GetPointer().String()
+}
+
+// Formats of OpenAPI 3.0, the numeric ones checking the numbers and the other ones the strings
+type (
+	// Int32FormatChecker validates an integer is a signed 32 bits one
+	Int32FormatChecker struct{}
+
+	// Int64FormatChecker validates an integer is a signed 64 bits one
+	Int64FormatChecker struct{}
+
+	// FloatFormatChecker validates a number is within the range of a 32 bits float
+	FloatFormatChecker struct{}
+
+	// DoubleFormatChecker validates a number is within the range of a 64 bits float
+	DoubleFormatChecker struct{}
+
+	// ByteFormatChecker validates a string is base64 encoded
+	ByteFormatChecker struct{}
+
+	// BinaryFormatChecker accepts any string, of octets
+	BinaryFormatChecker struct{}
+)
+
+// OpenAPIFormatCheckers holds the formats of the OpenAPI dialect, checked before FormatCheckers
+// by its schemas only, JSON Schema accepting the values of unknown formats
+var OpenAPIFormatCheckers = FormatCheckerChain{
+	formatters: map[string]FormatChecker{
+		"int32":  Int32FormatChecker{},
+		"int64":  Int64FormatChecker{},
+		"float":  FloatFormatChecker{},
+		"double": DoubleFormatChecker{},
+		"byte":   ByteFormatChecker{},
+		"binary": BinaryFormatChecker{},
+	},
+}
+
+// numberInRange reports whether a number is within bounds, the values that are not numbers
+// being accepted
+func numberInRange(input interface{}, integer bool, min *big.Float, max *big.Float) bool {
+	number, ok := input.(*big.Float)
+	if !ok {
+		return true
+	}
+	if integer && !number.IsInt() {
+		return false
+	}
+	return number.Cmp(min) >= 0 && number.Cmp(max) <= 0
+}
+
+func (f Int32FormatChecker) IsFormat(input interface{}) bool {
+	return numberInRange(input, true, big.NewFloat(math.MinInt32), big.NewFloat(math.MaxInt32))
+}
+
+func (f Int64FormatChecker) IsFormat(input interface{}) bool {
+	return numberInRange(input, true, new(big.Float).SetInt64(math.MinInt64), new(big.Float).SetInt64(math.MaxInt64))
+}
+
+func (f FloatFormatChecker) IsFormat(input interface{}) bool {
+	return numberInRange(input, false, big.NewFloat(-math.MaxFloat32), big.NewFloat(math.MaxFloat32))
+}
+
+func (f DoubleFormatChecker) IsFormat(input interface{}) bool {
+	return numberInRange(input, false, big.NewFloat(-math.MaxFloat64), big.NewFloat(math.MaxFloat64))
+}
+
+func (f ByteFormatChecker) IsFormat(input interface{}) bool {
+	asString, ok := input.(string)
+	if !ok {
+		return true
+	}
+	_, err := base64.StdEncoding.DecodeString(asString)
+	return err == nil
+}
+
+func (f BinaryFormatChecker) IsFormat(input interface{}) bool {
+	return true
+}
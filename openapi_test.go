@@ -0,0 +1,190 @@
+package gojsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const openAPIDocument = `
+openapi: 3.0.3
+info: {title: Pets, version: "1.0"}
+paths: {}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [petType]
+      properties:
+        petType: {type: string}
+        name: {type: string, nullable: true}
+        age: {type: integer, format: int32, minimum: 0, exclusiveMinimum: true}
+        photo: {type: string, format: byte}
+        weight: {type: number, format: float}
+        x-internal: true
+      discriminator:
+        propertyName: petType
+        mapping:
+          kitty: '#/components/schemas/Cat'
+      oneOf:
+        - $ref: '#/components/schemas/Cat'
+        - $ref: '#/components/schemas/Dog'
+      example: {petType: Cat, name: Tom}
+    Cat:
+      type: object
+      properties:
+        meows: {type: boolean}
+      required: [meows]
+    Dog:
+      type: object
+      properties:
+        barks: {type: boolean}
+      required: [barks]
+`
+
+func compileOpenAPI(t *testing.T, pointer string) *Schema {
+	sl := NewSchemaLoader()
+	sl.Dialect = DialectOpenAPI30
+	s, err := sl.CompileAt(NewYAMLLoader(openAPIDocument), pointer)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	return s
+}
+
+func TestOpenAPISchemaObject(t *testing.T) {
+	schema := compileOpenAPI(t, "#/components/schemas/Pet")
+
+	for document, errorTypes := range map[string][]string{
+		`{"petType": "Cat", "meows": true, "name": null, "age": 3, "photo": "aGVsbG8=", "weight": 4.5}`: nil,
+		`{"petType": "kitty", "meows": true}`:                           nil,
+		`{"petType": "Dog", "barks": false}`:                            nil,
+		`{"petType": "Dog", "meows": true}`:                             {"required"},
+		`{"meows": true}`:                                               {"required"},
+		`{"petType": "Bird"}`:                                           {"discriminator"},
+		`{"petType": 1}`:                                                {"discriminator", "invalid_type"},
+		`{"petType": "Cat", "meows": true, "age": 0}`:                   {"number_gt"},
+		`{"petType": "Cat", "meows": true, "age": 2147483648}`:          {"format"},
+		`{"petType": "Cat", "meows": true, "photo": "not base64!"}`:     {"format"},
+		`{"petType": "Cat", "meows": true, "weight": 1e39}`:             {"format"},
+		`{"petType": "Cat", "meows": true, "petType2": 1, "age": null}`: {"invalid_type"},
+	} {
+		result, err := schema.Validate(NewStringLoader(document))
+		if !assert.Nil(t, err) {
+			continue
+		}
+		var types []string
+		for _, resultError := range result.Errors() {
+			types = append(types, resultError.Type())
+		}
+		assert.Equal(t, errorTypes, types, document)
+	}
+
+	result, _ := schema.Validate(NewStringLoader(`{"petType": "Bird"}`))
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, "petType", result.Errors()[0].Field())
+		assert.Equal(t, `petType must be one of the following: "Cat", "Dog", "kitty"`, result.Errors()[0].Description())
+	}
+
+	// the references are resolved in the whole document
+	cat := compileOpenAPI(t, "/components/schemas/Cat")
+	result, _ = cat.Validate(NewStringLoader(`{"meows": "yes"}`))
+	assert.False(t, result.Valid())
+}
+
+func TestOpenAPIDiscriminatorInheritance(t *testing.T) {
+	// the subtypes extend the base schema with allOf, the discriminator has no branches
+	document := `
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [petType]
+      properties:
+        petType: {type: string}
+      discriminator:
+        propertyName: petType
+        mapping:
+          dog: Dog
+    Dog:
+      allOf:
+        - $ref: '#/components/schemas/Pet'
+        - properties:
+            bark: {type: boolean}
+`
+	sl := NewSchemaLoader()
+	sl.Dialect = DialectOpenAPI30
+	for _, pointer := range []string{"#/components/schemas/Pet", "#/components/schemas/Dog"} {
+		schema, err := sl.CompileAt(NewYAMLLoader(document), pointer)
+		if !assert.Nil(t, err) {
+			continue
+		}
+
+		for _, valid := range []string{`{"petType": "dog"}`, `{"petType": "Dog", "bark": true}`, `{"petType": "Pet"}`} {
+			result, err := schema.Validate(NewStringLoader(valid))
+			assert.Nil(t, err)
+			assert.True(t, result.Valid(), "%s %s %v", pointer, valid, result.Errors())
+		}
+
+		result, err := schema.Validate(NewStringLoader(`{"petType": "cat"}`))
+		assert.Nil(t, err)
+		descriptions := map[string]string{}
+		for _, resultError := range result.Errors() {
+			descriptions[resultError.Type()] = resultError.Description()
+		}
+		assert.Equal(t, `petType must be one of the following: "Dog", "Pet", "dog"`, descriptions["discriminator"], pointer)
+	}
+}
+
+func TestOpenAPIRejectedKeywords(t *testing.T) {
+	sl := NewSchemaLoader()
+	sl.Dialect = DialectOpenAPI30
+
+	for _, schema := range []string{
+		`{"type": ["string", "null"]}`,
+		`{"minimum": 0, "exclusiveMinimum": 1}`,
+		`{"const": 1}`,
+		`{"properties": {"a": {"if": {}}}}`,
+		`{"definitions": {}}`,
+		`{"nullable": "yes"}`,
+		`{"discriminator": "type"}`,
+		`{"discriminator": {"propertyName": "type", "mapping": {"a": 1}}}`,
+	} {
+		_, err := sl.Compile(NewStringLoader(schema))
+		assert.NotNil(t, err, "schema: %s", schema)
+	}
+
+	// nullable and discriminator are unknown keywords of JSON Schema
+	schema, err := NewSchema(NewStringLoader(`{"type": "string", "nullable": true}`))
+	if assert.Nil(t, err) {
+		result, _ := schema.Validate(NewStringLoader(`null`))
+		assert.False(t, result.Valid())
+	}
+}
+
+func TestOpenAPIFormatsDialect(t *testing.T) {
+	// JSON Schema does not know the formats of OpenAPI, and accepts any value
+	for schema, document := range map[string]string{
+		`{"type": "string", "format": "byte"}`:   `"not base64!"`,
+		`{"format": "float"}`:                    `1e39`,
+		`{"type": "integer", "format": "int32"}`: `2147483648`,
+	} {
+		s, err := NewSchema(NewStringLoader(schema))
+		if !assert.Nil(t, err) {
+			continue
+		}
+		result, err := s.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		assert.True(t, result.Valid(), "%s %v", schema, result.Errors())
+
+		sl := NewSchemaLoader()
+		sl.Dialect = DialectOpenAPI30
+		s, err = sl.Compile(NewStringLoader(schema))
+		if !assert.Nil(t, err) {
+			continue
+		}
+		result, err = s.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		assert.False(t, result.Valid(), schema)
+	}
+}
@@ -0,0 +1,204 @@
+package gojsonschema
+
+import (
+	"strconv"
+)
+
+// Removal of additional properties
+// With the RemoveAdditional option of ValidateWithOptions, the properties of the objects the schema
+// does not declare are removed from the validated document instead of being rejected. The subschemas
+// an object is validated against are considered together : $ref, allOf, the branches of anyOf and
+// oneOf validating the object once pruned, then or else. A property declared by any of them is kept.
+
+// RemoveAdditional selects the properties ValidateWithOptions removes from the document
+type RemoveAdditional int
+
+const (
+	// RemoveAdditionalNone keeps all the properties
+	RemoveAdditionalNone RemoveAdditional = iota
+	// RemoveAdditionalFalse removes the properties of the objects validated by an
+	// additionalProperties false that neither properties, patternProperties nor an
+	// additionalProperties schema or true declares
+	RemoveAdditionalFalse
+	// RemoveAdditionalAll removes the properties of the objects validated by properties,
+	// patternProperties or an additionalProperties false that neither properties nor
+	// patternProperties declares, whatever additionalProperties
+	RemoveAdditionalAll
+)
+
+type pruner struct {
+	mode RemoveAdditional
+	// branches memoizes whether an anyOf or oneOf branch validates the value at a JSON pointer,
+	// the subtree being pruned once per branch instead of once per enclosing decision
+	branches map[prunedBranch]bool
+}
+
+type prunedBranch struct {
+	schema  *subSchema
+	pointer string
+}
+
+// pruneValue returns the value without the additional properties of a set of subschemas, the value
+// being copied when pruned, and the JSON pointers to the removed properties
+func (p *pruner) pruneValue(schemas []*subSchema, value interface{}, pointer string) (interface{}, []string) {
+
+	var removed []string
+	schemas = p.applicableSchemas(schemas, value, pointer)
+
+	switch node := value.(type) {
+
+	case map[string]interface{}:
+		var copied map[string]interface{}
+		prune := p.prunes(schemas)
+		for _, key := range sortedMapKeys(node) {
+			var childSchemas []*subSchema
+			declared := false
+			for _, s := range schemas {
+				childSchemas = append(childSchemas, propertySchemas(s, key)...)
+				declared = declared || p.declares(s, key)
+			}
+
+			child, childRemoved := node[key], []string(nil)
+			if declared || !prune {
+				child, childRemoved = p.pruneValue(childSchemas, node[key], pointer+"/"+jsonPointerToken(key))
+				if len(childRemoved) == 0 {
+					continue
+				}
+			}
+
+			if copied == nil {
+				copied = map[string]interface{}{}
+				for k, v := range node {
+					copied[k] = v
+				}
+			}
+			if declared || !prune {
+				copied[key] = child
+				removed = append(removed, childRemoved...)
+			} else {
+				delete(copied, key)
+				removed = append(removed, pointer+"/"+jsonPointerToken(key))
+			}
+		}
+		if copied != nil {
+			return copied, removed
+		}
+
+	case []interface{}:
+		var copied []interface{}
+		for i, item := range node {
+			var itemSchemas []*subSchema
+			for _, s := range schemas {
+				if s.itemsChildrenIsSingleSchema {
+					itemSchemas = append(itemSchemas, s.itemsChildren[0])
+				} else if i < len(s.itemsChildren) {
+					itemSchemas = append(itemSchemas, s.itemsChildren[i])
+				} else if additionalItems, ok := s.additionalItems.(*subSchema); ok {
+					itemSchemas = append(itemSchemas, additionalItems)
+				}
+			}
+			pruned, itemRemoved := p.pruneValue(itemSchemas, item, pointer+"/"+strconv.Itoa(i))
+			if len(itemRemoved) > 0 {
+				if copied == nil {
+					copied = append([]interface{}{}, node...)
+				}
+				copied[i] = pruned
+				removed = append(removed, itemRemoved...)
+			}
+		}
+		if copied != nil {
+			return copied, removed
+		}
+	}
+
+	return value, removed
+}
+
+// applicableSchemas returns the subschemas a value is validated against, with the ones they
+// reference and the branches the value validates
+func (p *pruner) applicableSchemas(schemas []*subSchema, value interface{}, pointer string) []*subSchema {
+
+	var applicable []*subSchema
+	for _, s := range schemas {
+		for s.refSchema != nil {
+			s = s.refSchema
+		}
+		applicable = append(applicable, s)
+		applicable = append(applicable, p.applicableSchemas(s.allOf, value, pointer)...)
+
+		for _, branches := range [][]*subSchema{s.anyOf, s.oneOf} {
+			for _, branch := range branches {
+				if p.validatesBranch(branch, value, pointer) {
+					applicable = append(applicable, p.applicableSchemas([]*subSchema{branch}, value, pointer)...)
+				}
+			}
+		}
+
+		if s._if != nil {
+			if s._if.subValidateWithContext(value, NewJsonContext(STRING_CONTEXT_ROOT, nil)).Valid() {
+				if s._then != nil {
+					applicable = append(applicable, p.applicableSchemas([]*subSchema{s._then}, value, pointer)...)
+				}
+			} else if s._else != nil {
+				applicable = append(applicable, p.applicableSchemas([]*subSchema{s._else}, value, pointer)...)
+			}
+		}
+	}
+	return applicable
+}
+
+// validatesBranch reports whether the value at a JSON pointer, once pruned, validates an anyOf or
+// oneOf branch. The value at a pointer being always the one of the original document, the answer
+// is computed once per branch and pointer
+func (p *pruner) validatesBranch(branch *subSchema, value interface{}, pointer string) bool {
+
+	key := prunedBranch{schema: branch, pointer: pointer}
+	if valid, ok := p.branches[key]; ok {
+		return valid
+	}
+
+	pruned, _ := p.pruneValue([]*subSchema{branch}, value, pointer)
+	valid := branch.subValidateWithContext(pruned, NewJsonContext(STRING_CONTEXT_ROOT, nil)).Valid()
+	if p.branches == nil {
+		p.branches = map[prunedBranch]bool{}
+	}
+	p.branches[key] = valid
+	return valid
+}
+
+// prunes reports whether the properties of an object validated by subschemas are removed
+func (p *pruner) prunes(schemas []*subSchema) bool {
+	for _, s := range schemas {
+		if allowed, ok := s.additionalProperties.(bool); ok && !allowed {
+			return true
+		}
+		if p.mode == RemoveAdditionalAll && (len(s.propertiesChildren) > 0 || len(s.patternProperties) > 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// declares reports whether a subschema declares a property
+func (p *pruner) declares(s *subSchema, key string) bool {
+
+	for _, property := range s.propertiesChildren {
+		if property.property == key {
+			return true
+		}
+	}
+	for pattern := range s.patternProperties {
+		if s.patternMatchers[pattern].MatchString(key) {
+			return true
+		}
+	}
+	if p.mode == RemoveAdditionalFalse {
+		switch additionalProperties := s.additionalProperties.(type) {
+		case bool:
+			return additionalProperties
+		case *subSchema:
+			return true
+		}
+	}
+	return false
+}
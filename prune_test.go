@@ -0,0 +1,134 @@
+package gojsonschema
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func pruneString(t *testing.T, schema string, document string, mode RemoveAdditional) *Result {
+	s, err := NewSchema(NewStringLoader(schema))
+	if !assert.Nil(t, err) {
+		return nil
+	}
+	result, err := s.ValidateWithOptions(NewStringLoader(document), ValidateOptions{RemoveAdditional: mode})
+	if !assert.Nil(t, err) {
+		return nil
+	}
+	return result
+}
+
+func TestRemoveAdditional(t *testing.T) {
+	schema := `{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"address": {
+				"type": "object",
+				"properties": {"city": {"type": "string"}},
+				"additionalProperties": false
+			},
+			"items": {
+				"type": "array",
+				"items": {"properties": {"sku": {"type": "string"}}, "patternProperties": {"^x-": {}}, "additionalProperties": false}
+			},
+			"labels": {"type": "object", "additionalProperties": {"type": "string"}}
+		},
+		"additionalProperties": false
+	}`
+	document := `{
+		"name": "Jane",
+		"debug": true,
+		"address": {"city": "Paris", "zip": "75001"},
+		"items": [{"sku": "a", "x-note": "keep", "price": 1}, {"sku": "b"}],
+		"labels": {"env": "prod"}
+	}`
+
+	result := pruneString(t, schema, document, RemoveAdditionalFalse)
+	assert.True(t, result.Valid(), "%v", result.Errors())
+	assert.Equal(t, map[string]interface{}{
+		"name":    "Jane",
+		"address": map[string]interface{}{"city": "Paris"},
+		"items": []interface{}{
+			map[string]interface{}{"sku": "a", "x-note": "keep"},
+			map[string]interface{}{"sku": "b"},
+		},
+		"labels": map[string]interface{}{"env": "prod"},
+	}, result.Document())
+	assert.Equal(t, []string{"/address/zip", "/debug", "/items/0/price"}, result.RemovedProperties())
+
+	// the aggressive mode ignores additionalProperties schemas
+	result = pruneString(t, `{
+		"properties": {"a": {}},
+		"additionalProperties": {"type": "string"}
+	}`, `{"a": 1, "b": "c"}`, RemoveAdditionalAll)
+	assert.Equal(t, map[string]interface{}{"a": json.Number("1")}, result.Document())
+	assert.Equal(t, []string{"/b"}, result.RemovedProperties())
+
+	// without properties, nothing is removed
+	result = pruneString(t, `{"additionalProperties": {"type": "string"}}`, `{"b": "c"}`, RemoveAdditionalAll)
+	assert.Empty(t, result.RemovedProperties())
+
+	// without the option, the additional properties are rejected
+	result = pruneString(t, schema, document, RemoveAdditionalNone)
+	assert.False(t, result.Valid())
+	assert.Empty(t, result.RemovedProperties())
+}
+
+func TestRemoveAdditionalCombinators(t *testing.T) {
+	schema := `{
+		"definitions": {
+			"base": {"properties": {"id": {"type": "integer"}}, "additionalProperties": false}
+		},
+		"allOf": [
+			{"$ref": "#/definitions/base"},
+			{"properties": {"name": {"type": "string"}}}
+		],
+		"anyOf": [
+			{"properties": {"card": {"type": "string"}}, "required": ["card"], "additionalProperties": false},
+			{"properties": {"iban": {"type": "string"}}, "required": ["iban"], "additionalProperties": false}
+		]
+	}`
+
+	// the properties of every allOf branch and of the validating anyOf branch are kept
+	result := pruneString(t, schema, `{"id": 1, "name": "Jane", "iban": "FR76", "card": 4, "extra": true}`, RemoveAdditionalFalse)
+	assert.Equal(t, []string{"/card", "/extra"}, result.RemovedProperties())
+	assert.Contains(t, result.Document(), "iban")
+	assert.Contains(t, result.Document(), "name")
+
+	// allOf still rejects the properties its other branches declare
+	assert.False(t, result.Valid())
+
+	result = pruneString(t, `{
+		"if": {"properties": {"kind": {"const": "a"}}},
+		"then": {"properties": {"kind": {}, "a": {}}, "additionalProperties": false},
+		"else": {"properties": {"kind": {}, "b": {}}, "additionalProperties": false}
+	}`, `{"kind": "a", "a": 1, "b": 2}`, RemoveAdditionalFalse)
+	assert.True(t, result.Valid())
+	assert.Equal(t, []string{"/b"}, result.RemovedProperties())
+}
+
+func TestRemoveAdditionalDeepCombinators(t *testing.T) {
+	// every level is an object in a two branches anyOf, deciding which branches pass must not
+	// prune the subtree again for each of them
+	definitions, document := `"l0": {"type": "string"}`, `"leaf"`
+	for i := 1; i <= 12; i++ {
+		child := `{"$ref": "#/definitions/l` + strconv.Itoa(i-1) + `"}`
+		definitions += `, "l` + strconv.Itoa(i) + `": {"anyOf": [
+			{"properties": {"a": {"type": "integer"}, "child": ` + child + `}, "required": ["a"], "additionalProperties": false},
+			{"properties": {"b": {"type": "integer"}, "child": ` + child + `}, "required": ["b"], "additionalProperties": false}
+		]}`
+		document = `{"a": 1, "extra": true, "child": ` + document + `}`
+	}
+	schema := `{"definitions": {` + definitions + `}, "$ref": "#/definitions/l12"}`
+
+	start := time.Now()
+	result := pruneString(t, schema, document, RemoveAdditionalFalse)
+	assert.True(t, time.Since(start) < 5*time.Second, "pruning took %v", time.Since(start))
+	assert.True(t, result.Valid(), "%v", result.Errors())
+	assert.Len(t, result.RemovedProperties(), 12)
+	assert.Equal(t, "/child/child/child/child/child/child/child/child/child/child/child/extra", result.RemovedProperties()[0])
+}
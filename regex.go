@@ -0,0 +1,48 @@
+package gojsonschema
+
+import "regexp"
+
+// RegexEngine compiles the regular expressions of the pattern and patternProperties keywords
+// and of the regex format
+type RegexEngine interface {
+	Compile(pattern string) (RegexMatcher, error)
+}
+
+// RegexMatcher is a compiled regular expression, *regexp.Regexp implements it
+type RegexMatcher interface {
+	// MatchString reports whether the string contains any match of the expression
+	MatchString(s string) bool
+	// String returns the source of the expression
+	String() string
+}
+
+// GoRegexEngine compiles regular expressions with the RE2 syntax of the regexp package,
+// which is faster than ECMA 262 but does not support lookarounds nor backreferences
+type GoRegexEngine struct{}
+
+func (GoRegexEngine) Compile(pattern string) (RegexMatcher, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return re, nil
+}
+
+// The engine used when none is configured, ECMARegexEngine implementing the syntax of the
+// JSON Schema specification on demand
+var defaultRegexEngine RegexEngine = GoRegexEngine{}
+
+// boundedRegexMatcher is implemented by the matchers that may abort a match, like the ones of
+// ECMARegexEngine past their step limit
+type boundedRegexMatcher interface {
+	matchStringBounded(s string) (matched bool, aborted bool)
+}
+
+// regexMatch reports whether the string contains any match of the expression, and whether the
+// matcher aborted before knowing it
+func regexMatch(matcher RegexMatcher, s string) (matched bool, aborted bool) {
+	if bounded, ok := matcher.(boundedRegexMatcher); ok {
+		return bounded.matchStringBounded(s)
+	}
+	return matcher.MatchString(s), false
+}
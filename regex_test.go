@@ -0,0 +1,211 @@
+package gojsonschema
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestECMARegexMatch(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		input   string
+		matches bool
+	}{
+		{`a`, "cat", true},
+		{`^a`, "cat", false},
+		{`^\d+$`, "123", true},
+		{`^\d+$`, "١٢٣", false},
+		{`^\w+$`, "héllo", false},
+		{`^\s$`, " ", true},
+		{`^.$`, "\n", false},
+		{`^.$`, "😀", true},
+		{`^[^]$`, "\n", true},
+		{`^[]$`, "a", false},
+		{`^[a-c-]+$`, "ab-c", true},
+		{`^[\w.]+@`, "john.doe@example.com", true},
+		{`^(?=.*\d)(?=.*[a-z]).{6,}$`, "abc123", true},
+		{`^(?=.*\d)(?=.*[a-z]).{6,}$`, "abcdef", false},
+		{`^(?!foo)\w+$`, "foobar", false},
+		{`^(?!foo)\w+$`, "barfoo", true},
+		{`(?<=\$)\d+`, "cost: $42", true},
+		{`(?<!\$)\b\d+`, "$42", false},
+		{`^(\w)\w*\1$`, "abca", true},
+		{`^(\w)\w*\1$`, "abcd", false},
+		{`^(?<quote>['"]).*\k<quote>$`, `"text"`, true},
+		{`^(?<quote>['"]).*\k<quote>$`, `"text'`, false},
+		{`^(a)|\1b$`, "b", true},
+		{`^(?:(a)|b)+\1$`, "ab", true},
+		{`^a{2,3}$`, "aaaa", false},
+		{`^a{2,}?$`, "aaaa", true},
+		{`^(a*)*$`, "aaa", true},
+		{`^\p{Lu}\p{Ll}+$`, "Émile", true},
+		{`^\p{Script=Greek}+$`, "αβγ", true},
+		{`^\P{L}+$`, "123", true},
+		{`^\u{1F600}$`, "😀", true},
+		{`^😀$`, "😀", true},
+		{`^\x41\cJ$`, "A\n", true},
+		{`\bcat\b`, "concat", false},
+		{`\Bcat\b`, "concat", true},
+	}
+
+	for _, testCase := range testCases {
+		re, err := ECMARegexEngine{}.Compile(testCase.pattern)
+		if assert.Nil(t, err, "pattern: %s", testCase.pattern) {
+			assert.Equal(t, testCase.matches, re.MatchString(testCase.input), "pattern: %s, input: %s", testCase.pattern, testCase.input)
+			assert.Equal(t, testCase.pattern, re.String())
+		}
+	}
+}
+
+func TestECMARegexSyntax(t *testing.T) {
+	invalid := []string{
+		`^\S(|(.|\n)*\S)\Z`,
+		`(abc`,
+		`abc)`,
+		`[abc`,
+		`a**`,
+		`*a`,
+		`a{2,1}`,
+		`a{`,
+		`}`,
+		`]`,
+		`^*`,
+		`(?=a)*`,
+		`\1(a)(b)\3`,
+		`\k<name>`,
+		`(?<a>x)(?<a>y)`,
+		`[z-a]`,
+		`[\d-z]`,
+		`\p{Latin}`,
+		`\p{Unknown_Property}`,
+		`\u{110000}`,
+		`\c1`,
+		`\`,
+		`(?x)`,
+	}
+
+	for _, pattern := range invalid {
+		_, err := ECMARegexEngine{}.Compile(pattern)
+		assert.NotNil(t, err, "pattern: %s", pattern)
+	}
+
+	// backreferences may come before their group
+	_, err := ECMARegexEngine{}.Compile(`\1(a)`)
+	assert.Nil(t, err)
+}
+
+func TestECMARegexStepLimit(t *testing.T) {
+	re, err := ECMARegexEngine{StepLimit: 10000}.Compile(`^(a+)+$`)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.True(t, re.MatchString(strings.Repeat("a", 30)))
+	// catastrophic backtracking is aborted and reported as not matching
+	assert.False(t, re.MatchString(strings.Repeat("a", 30)+"b"))
+	matched, aborted := regexMatch(re, strings.Repeat("a", 30)+"b")
+	assert.False(t, matched)
+	assert.True(t, aborted)
+
+	re, err = ECMARegexEngine{StepLimit: 10000}.Compile(`(?!(a+)+b)a`)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.False(t, re.MatchString(strings.Repeat("a", 30)))
+
+	// matching characters and trying start positions do not count, linear matches are not aborted
+	for pattern, input := range map[string]string{
+		`^[a-z]*$`:         strings.Repeat("a", 600000),
+		`z`:                strings.Repeat("a", 1200000) + "z",
+		`^(ab|c)+$`:        strings.Repeat("ab", 5000),
+		`^a[a-z]*?$`:       strings.Repeat("a", 600000),
+		`^[^,]*,[0-9]{3}$`: strings.Repeat("x", 600000) + ",123",
+	} {
+		re, err = ECMARegexEngine{}.Compile(pattern)
+		if !assert.Nil(t, err) {
+			return
+		}
+		matched, aborted = regexMatch(re, input)
+		assert.True(t, matched, pattern)
+		assert.False(t, aborted, pattern)
+	}
+
+	// repeated characters do not nest, a long input does not exhaust the stack
+	re, err = ECMARegexEngine{}.Compile(`^[a-z]*$`)
+	if assert.Nil(t, err) {
+		start := time.Now()
+		assert.True(t, re.MatchString(strings.Repeat("a", 10000000)))
+		assert.True(t, time.Since(start) < 5*time.Second, "took %s", time.Since(start))
+	}
+
+	// repeated groups nest, too many iterations abort the match
+	re, err = ECMARegexEngine{}.Compile(`^(ab|c)+$`)
+	if assert.Nil(t, err) {
+		matched, aborted = regexMatch(re, strings.Repeat("ab", 1000000))
+		assert.False(t, matched)
+		assert.True(t, aborted)
+	}
+}
+
+func TestRegexStepLimitError(t *testing.T) {
+	sl := NewSchemaLoader()
+	sl.RegexEngine = ECMARegexEngine{StepLimit: 10000}
+	s, err := sl.Compile(NewStringLoader(`{
+		"properties": {"name": {"pattern": "^(a+)+$"}},
+		"patternProperties": {"^(b+)+$": {"type": "string"}},
+		"additionalProperties": false
+	}`))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	key := strings.Repeat("b", 30) + "c"
+	result, err := s.Validate(NewGoLoader(map[string]interface{}{"name": strings.Repeat("a", 30) + "b", key: 1}))
+	assert.Nil(t, err)
+	fields := map[string]string{}
+	for _, e := range result.Errors() {
+		fields[e.Field()] = e.Type()
+	}
+	// the key whose match was aborted is not an additional property
+	assert.Equal(t, map[string]string{"name": "regex_step_limit", key: "regex_step_limit"}, fields)
+}
+
+func TestRegexEngineOption(t *testing.T) {
+	schema := `{"pattern": "^(?=a)", "patternProperties": {"^(?!x)": {"type": "string"}}}`
+
+	// RE2 does not support lookaheads
+	_, err := NewSchema(NewStringLoader(schema))
+	assert.NotNil(t, err)
+
+	sl := NewSchemaLoader()
+	sl.RegexEngine = ECMARegexEngine{}
+	s, err := sl.Compile(NewStringLoader(schema))
+	if !assert.Nil(t, err) {
+		return
+	}
+	result, err := s.Validate(NewStringLoader(`{"a": 1}`))
+	assert.Nil(t, err)
+	assert.False(t, result.Valid())
+	result, err = s.Validate(NewStringLoader(`"abc"`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	// the schemas compiling with RE2 keep compiling by default
+	for _, pattern := range []string{`(?i)^abc$`, `\Aabc\z`, `[[:alpha:]]`, `\p{Greek}`, `a{,3}`} {
+		_, err := NewSchema(NewGoLoader(map[string]interface{}{"pattern": pattern}))
+		assert.Nil(t, err, pattern)
+	}
+
+	assert.False(t, RegexFormatChecker{}.IsFormat(`(?<=a)b`))
+	assert.True(t, RegexFormatChecker{Engine: ECMARegexEngine{}}.IsFormat(`(?<=a)b`))
+}
+
+func TestECMAScriptRegexSuite(t *testing.T) {
+	sl := NewSchemaLoader()
+	sl.RegexEngine = ECMARegexEngine{}
+	testSuiteFile(t, sl, filepath.Join("testdata", "draft7", "optional", "ecmascript-regex.json"))
+}
@@ -27,6 +27,7 @@ package gojsonschema
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 )
 
@@ -63,6 +64,33 @@ type (
 		descriptionFormat string       // A format for human readable error message
 		value             interface{}  // Value given by the JSON file that is the source of the error
 		details           ErrorDetails
+		locale            LocaleMessages // Locale of the description, the global Locale when nil
+		branches          []ErrorBranch  // Validation of the branches of anyOf, oneOf, allOf, not, then and else
+	}
+
+	// ErrorBranch is the validation of the value against a branch of the keyword raising an error :
+	// a subschema of anyOf, oneOf or allOf, or the subschema of not, then or else
+	ErrorBranch struct {
+		// Index of the subschema in anyOf, oneOf and allOf, 0 for not, then and else
+		Index int
+		Valid bool
+		// Score of the validation, higher the more of the subschema the value matches
+		Score  int
+		Errors []ResultError
+	}
+
+	// ErrorTree is an error with the errors of its branches
+	ErrorTree struct {
+		Error    ResultError
+		Branches []ErrorTreeBranch
+	}
+
+	// ErrorTreeBranch is a branch of an ErrorTree
+	ErrorTreeBranch struct {
+		Index  int
+		Valid  bool
+		Score  int
+		Errors []*ErrorTree
 	}
 
 	Result struct {
@@ -70,6 +98,11 @@ type (
 		// Scores how well the validation matched. Useful in generating
 		// better error messages for anyOf and oneOf.
 		score int
+		// Document validated by ValidateWithOptions, the coercions of its values and the pointers
+		// to its removed properties
+		document  interface{}
+		coercions []Coercion
+		removed   []string
 	}
 )
 
@@ -133,6 +166,17 @@ func (v *ResultErrorFields) Details() ErrorDetails {
 	return v.details
 }
 
+// SetBranches sets the validation of the branches of the keyword raising the error
+func (v *ResultErrorFields) SetBranches(branches []ErrorBranch) {
+	v.branches = branches
+}
+
+// Branches returns the validation of the branches of anyOf, oneOf, allOf, not, then and else
+// for their errors, nil for the other errors
+func (v *ResultErrorFields) Branches() []ErrorBranch {
+	return v.branches
+}
+
 func (v ResultErrorFields) String() string {
 	// as a fallback, the value is displayed go style
 	valueString := fmt.Sprintf("%v", v.value)
@@ -150,7 +194,12 @@ func (v ResultErrorFields) String() string {
 		}
 	}
 
-	return formatErrorDescription(Locale.ErrorFormat(), ErrorDetails{
+	locale := v.locale
+	if locale == nil {
+		locale = Locale
+	}
+
+	return formatErrorDescription(locale.ErrorFormat(), ErrorDetails{
 		"context":     v.context.String(),
 		"description": v.description,
 		"value":       valueString,
@@ -165,6 +214,23 @@ func (v *Result) Valid() bool {
 func (v *Result) Errors() []ResultError {
 	return v.errors
 }
+
+// Document returns the document validated by ValidateWithOptions, with its values coerced
+// when the Coerce option is set and without the properties removed by RemoveAdditional
+func (v *Result) Document() interface{} {
+	return v.document
+}
+
+// Coercions returns the values of the document coerced by ValidateWithOptions
+func (v *Result) Coercions() []Coercion {
+	return v.coercions
+}
+
+// RemovedProperties returns the JSON pointers to the properties ValidateWithOptions removed
+// from the document
+func (v *Result) RemovedProperties() []string {
+	return v.removed
+}
 // Add a fully filled error to the error set
 // SetDescription() will be called with the result of the parsed err.DescriptionFormat()
 func (v *Result) AddError(err ResultError, details ErrorDetails) {
@@ -192,3 +258,123 @@ func (v *Result) mergeErrors(otherResult *Result) {
 func (v *Result) incrementScore() {
 	v.score++
 }
+
+// branch returns the validation of a branch from its result
+func (v *Result) branch(index int) ErrorBranch {
+	return ErrorBranch{Index: index, Valid: v.Valid(), Score: v.score, Errors: v.errors}
+}
+
+// Errors carrying the validation of their branches
+type branchedError interface {
+	Branches() []ErrorBranch
+}
+
+// Tree returns the errors with the errors of their branches nested in them, where Errors lists
+// the errors of the best branches of anyOf and oneOf and of allOf, then and else next to their own
+func (v *Result) Tree() []*ErrorTree {
+	return errorTrees(v.errors)
+}
+
+// errorTrees returns the trees of errors, leaving out the ones in the branches of others
+func errorTrees(errs []ResultError) []*ErrorTree {
+
+	nested := map[ResultError]bool{}
+	var mark func(errs []ResultError)
+	mark = func(errs []ResultError) {
+		for _, err := range errs {
+			if branched, ok := err.(branchedError); ok {
+				for _, branch := range branched.Branches() {
+					for _, branchError := range branch.Errors {
+						nested[branchError] = true
+					}
+					mark(branch.Errors)
+				}
+			}
+		}
+	}
+	mark(errs)
+
+	var trees []*ErrorTree
+	for _, err := range errs {
+		if nested[err] {
+			continue
+		}
+		tree := &ErrorTree{Error: err}
+		if branched, ok := err.(branchedError); ok {
+			for _, branch := range branched.Branches() {
+				tree.Branches = append(tree.Branches, ErrorTreeBranch{
+					Index:  branch.Index,
+					Valid:  branch.Valid,
+					Score:  branch.Score,
+					Errors: errorTrees(branch.Errors),
+				})
+			}
+		}
+		trees = append(trees, tree)
+	}
+	return trees
+}
+
+// Localize returns a copy of the result whose errors are described in another locale.
+// The errors added with AddError and the messages of errorMessage keep their description.
+func (v *Result) Localize(locale LocaleMessages) *Result {
+	localized := &Result{score: v.score, document: v.document, coercions: v.coercions, removed: v.removed}
+	copies := map[ResultError]ResultError{}
+	for _, err := range v.errors {
+		localized.errors = append(localized.errors, localizeError(err, locale, copies))
+	}
+	return localized
+}
+
+// localizeError returns a copy of an error of the validation described in a locale, copies holding
+// the errors already copied so the errors of the branches stay the ones of the result
+func localizeError(err ResultError, locale LocaleMessages, copies map[ResultError]ResultError) ResultError {
+
+	if localized, ok := copies[err]; ok {
+		return localized
+	}
+	copies[err] = err
+
+	t, format := errorTypeFormat(err, locale)
+	if t == "" {
+		return err
+	}
+
+	value := reflect.ValueOf(err)
+	if value.Kind() != reflect.Ptr {
+		return err
+	}
+	copied := reflect.New(value.Elem().Type())
+	copied.Elem().Set(value.Elem())
+	localized := copied.Interface().(ResultError)
+
+	details := ErrorDetails{}
+	for k, v := range err.Details() {
+		details[k] = v
+	}
+	if fields, ok := copied.Elem().FieldByName("ResultErrorFields").Addr().Interface().(*ResultErrorFields); ok {
+		fields.locale = locale
+
+		var branches []ErrorBranch
+		for _, branch := range fields.branches {
+			localizedBranch := branch
+			localizedBranch.Errors = nil
+			for _, branchError := range branch.Errors {
+				localizedBranch.Errors = append(localizedBranch.Errors, localizeError(branchError, locale, copies))
+			}
+			branches = append(branches, localizedBranch)
+		}
+		fields.branches = branches
+	}
+
+	// the errors of the schemas are in the description of oneOf
+	if oneOf, ok := localized.(*NumberOneOfError); ok && details["failures"] != nil {
+		details["failures"] = oneOfDetails(oneOf.branches, oneOf.titles)["failures"]
+	}
+	localized.SetDetails(details)
+	localized.SetDescriptionFormat(format)
+	localized.SetDescription(formatErrorDescription(format, details))
+
+	copies[err] = localized
+	return localized
+}
@@ -0,0 +1,154 @@
+package gojsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func validateString(t *testing.T, schema string, document string) *Result {
+	s, err := NewSchema(NewStringLoader(schema))
+	if !assert.Nil(t, err) {
+		return nil
+	}
+	result, err := s.Validate(NewStringLoader(document))
+	if !assert.Nil(t, err) {
+		return nil
+	}
+	return result
+}
+
+func TestResultTree(t *testing.T) {
+	result := validateString(t, `{
+		"properties": {
+			"payment": {
+				"anyOf": [
+					{"properties": {"card": {"type": "string", "pattern": "^[0-9]{16}$"}}, "required": ["card"]},
+					{"properties": {"iban": {"type": "string"}}, "required": ["iban"]},
+					{"type": "null"}
+				]
+			}
+		}
+	}`, `{"payment": {"card": "1234"}}`)
+	if result == nil {
+		return
+	}
+
+	// the errors stay flat, with the ones of the best branch
+	var types []string
+	for _, err := range result.Errors() {
+		types = append(types, err.Type())
+	}
+	assert.Equal(t, []string{"number_any_of", "pattern"}, types)
+
+	tree := result.Tree()
+	if !assert.Len(t, tree, 1) {
+		return
+	}
+	assert.Equal(t, "number_any_of", tree[0].Error.Type())
+	assert.Equal(t, "payment", tree[0].Error.Field())
+	if !assert.Len(t, tree[0].Branches, 3) {
+		return
+	}
+
+	card, iban, null := tree[0].Branches[0], tree[0].Branches[1], tree[0].Branches[2]
+	assert.Equal(t, 0, card.Index)
+	assert.False(t, card.Valid)
+	if assert.Len(t, card.Errors, 1) {
+		assert.Equal(t, "pattern", card.Errors[0].Error.Type())
+		assert.Equal(t, "payment.card", card.Errors[0].Error.Field())
+	}
+	if assert.Len(t, iban.Errors, 1) {
+		assert.Equal(t, "required", iban.Errors[0].Error.Type())
+	}
+	if assert.Len(t, null.Errors, 1) {
+		assert.Equal(t, "invalid_type", null.Errors[0].Error.Type())
+	}
+	assert.True(t, card.Score > iban.Score)
+
+	branches := result.Errors()[0].(*NumberAnyOfError).Branches()
+	assert.Len(t, branches, 3)
+	assert.Nil(t, result.Errors()[1].(*DoesNotMatchPatternError).Branches())
+}
+
+func TestResultTreeBranches(t *testing.T) {
+	// oneOf matching several branches
+	result := validateString(t, `{"oneOf": [{"type": "integer"}, {"minimum": 0}, {"type": "string"}]}`, `1`)
+	tree := result.Tree()
+	if assert.Len(t, tree, 1) && assert.Len(t, tree[0].Branches, 3) {
+		assert.True(t, tree[0].Branches[0].Valid)
+		assert.True(t, tree[0].Branches[1].Valid)
+		assert.False(t, tree[0].Branches[2].Valid)
+	}
+
+	// allOf, nested in then
+	result = validateString(t, `{
+		"if": {"required": ["a"]},
+		"then": {"allOf": [{"required": ["b"]}, {"required": ["c"]}]}
+	}`, `{"a": 1, "b": 2}`)
+	tree = result.Tree()
+	if !assert.Len(t, tree, 1) {
+		return
+	}
+	assert.Equal(t, "condition_then", tree[0].Error.Type())
+	then := tree[0].Branches
+	if assert.Len(t, then, 1) && assert.Len(t, then[0].Errors, 1) {
+		allOf := then[0].Errors[0]
+		assert.Equal(t, "number_all_of", allOf.Error.Type())
+		if assert.Len(t, allOf.Branches, 2) {
+			assert.True(t, allOf.Branches[0].Valid)
+			assert.Equal(t, "c is required", allOf.Branches[1].Errors[0].Error.Description())
+		}
+	}
+	assert.Len(t, result.Errors(), 3)
+
+	// not
+	result = validateString(t, `{"not": {"type": "string"}}`, `"a"`)
+	tree = result.Tree()
+	if assert.Len(t, tree, 1) && assert.Len(t, tree[0].Branches, 1) {
+		assert.True(t, tree[0].Branches[0].Valid)
+	}
+
+	// localized results keep the tree
+	french, _ := LocaleFor("fr")
+	tree = validateString(t, `{"anyOf": [{"required": ["a"]}, {"required": ["b"]}]}`, `{}`).Localize(french).Tree()
+	if assert.Len(t, tree, 1) && assert.Len(t, tree[0].Branches, 2) {
+		assert.Equal(t, "a est obligatoire", tree[0].Branches[0].Errors[0].Error.Description())
+	}
+}
+
+func TestOneOfDetails(t *testing.T) {
+	schema := `{
+		"oneOf": [
+			{"title": "Cat", "properties": {"meow": {"type": "boolean"}}, "required": ["meow"]},
+			{"title": "Dog", "properties": {"bark": {"type": "boolean"}}, "required": ["bark"]},
+			{"type": "object", "maxProperties": 1}
+		]
+	}`
+
+	result := validateString(t, schema, `{"meow": true, "bark": true}`)
+	if assert.Len(t, result.Errors(), 1) {
+		err := result.Errors()[0]
+		assert.Equal(t, `0 "Cat", 1 "Dog"`, err.Details()["matched"])
+		assert.Equal(t, []int{0, 1}, err.Details()["indices"])
+		assert.Equal(t, `Must validate one and only one schema (oneOf), but validates the schemas 0 "Cat", 1 "Dog"`, err.Description())
+	}
+
+	result = validateString(t, schema, `{"meow": 1, "purr": true}`)
+	if assert.Len(t, result.Errors(), 2) {
+		err := result.Errors()[0]
+		assert.Equal(t, "number_one_of", err.Type())
+		assert.Equal(t, `0 "Cat" (meow: Invalid type. Expected: boolean, given: integer); `+
+			`1 "Dog" (bark: bark is required); `+
+			`2 ((root): Must have at most 1 properties)`, err.Details()["failures"])
+
+		french, _ := LocaleFor("fr")
+		localized := result.Localize(french).Errors()[0]
+		assert.Equal(t, `Doit être valide pour un et un seul schéma (oneOf), erreurs des schémas : `+
+			`0 "Cat" (meow: Type invalide. Attendu : boolean, reçu : integer); `+
+			`1 "Dog" (bark: bark est obligatoire); `+
+			`2 ((root): Doit avoir au plus 1 propriétés)`, localized.Description())
+	}
+
+	assert.True(t, validateString(t, schema, `{"bark": false, "name": "Rex"}`).Valid())
+}
@@ -0,0 +1,1036 @@
+package gojsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"math/rand"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Sample generation
+// GenerateSample produces a random document valid against a schema. The subschemas a value must
+// satisfy are gathered ( $ref, allOf, a branch of anyOf and oneOf, then with if ) and a candidate is
+// built from their keywords, then validated: the constraints the candidate does not honor, like a
+// not or a pattern the generator cannot follow, are retried with other random choices.
+// GenerateInvalidSamples mutates a valid sample once per keyword, each mutation being kept when
+// Validate reports the error the keyword is expected to.
+
+// SampleOptions tunes the sample generation
+type SampleOptions struct {
+	// Seed of the random choices, the same seed giving the same samples
+	Seed int64
+	// MaxDepth of the optional properties and items, deeper values only have the required ones. 5 by default
+	MaxDepth int
+}
+
+// InvalidSample is a document violating a single keyword of a schema
+type InvalidSample struct {
+	Document interface{}
+	// Pointer is the JSON pointer of the subschema whose keyword is violated
+	Pointer string
+	Keyword string
+	// Field and Type of the error Validate reports
+	Field string
+	Type  string
+}
+
+const (
+	defaultSampleMaxDepth = 5
+	// random candidates tried for a value
+	sampleAttempts = 20
+	// nesting beyond MaxDepth after which the required values are given up
+	sampleDepthMargin = 20
+	// length of the strings, arrays and ranges of numbers without bounds
+	sampleRange = 8
+	// values of ECMA 262 patterns the regexp/syntax package cannot parse
+	sampleAlphabet = "abcdefghijklmnopqrstuvwxyz"
+)
+
+// Values of the formats, the formats of FormatCheckers and of the drafts
+var sampleFormats = map[string][]string{
+	"date-time":             {"2019-10-18T12:30:00Z", "2021-02-28T08:15:30.5+01:00"},
+	"date":                  {"2019-10-18", "2021-02-28"},
+	"time":                  {"12:30:00Z", "08:15:30+01:00"},
+	"email":                 {"jane@example.com", "john.doe@example.org"},
+	"idn-email":             {"jane@example.com"},
+	"hostname":              {"example.com", "api.example.org"},
+	"idn-hostname":          {"example.com"},
+	"ipv4":                  {"192.168.0.1", "10.0.0.254"},
+	"ipv6":                  {"2001:db8::1", "fe80::1:2"},
+	"uri":                   {"https://example.com/path", "urn:isbn:0451450523"},
+	"uri-reference":         {"/path?query=1", "https://example.com/"},
+	"iri":                   {"https://example.com/path"},
+	"iri-reference":         {"/path"},
+	"uri-template":          {"https://example.com/{id}"},
+	"json-pointer":          {"/a/b", ""},
+	"relative-json-pointer": {"0/a", "1"},
+	"regex":                 {"^[a-z]+$", "\\d{3}"},
+}
+
+// GenerateSample returns a random document valid against a schema
+func GenerateSample(schema *Schema, options SampleOptions) (interface{}, error) {
+	return newSampler(schema, options).generate([]*subSchema{schema.rootSchema}, STRING_CONTEXT_ROOT, 0)
+}
+
+type sampler struct {
+	schema   *Schema
+	rand     *rand.Rand
+	maxDepth int
+}
+
+func newSampler(schema *Schema, options SampleOptions) *sampler {
+	maxDepth := options.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = defaultSampleMaxDepth
+	}
+	return &sampler{schema: schema, rand: rand.New(rand.NewSource(options.Seed)), maxDepth: maxDepth}
+}
+
+// generate returns a value valid against all the subschemas, field being its location for the errors
+func (g *sampler) generate(schemas []*subSchema, field string, depth int) (interface{}, error) {
+
+	if depth > g.maxDepth+sampleDepthMargin {
+		return nil, errors.New(formatErrorDescription(Locale.SampleDepthExceeded(), ErrorDetails{"field": field}))
+	}
+
+	for attempt := 0; attempt < sampleAttempts; attempt++ {
+		value, err := g.candidate(schemas, field, depth)
+		if err != nil {
+			return nil, err
+		}
+		if validatesAll(schemas, value) {
+			return value, nil
+		}
+	}
+
+	return nil, errors.New(formatErrorDescription(Locale.SampleNotGenerated(), ErrorDetails{"field": field}))
+}
+
+func validatesAll(schemas []*subSchema, value interface{}) bool {
+	for _, s := range schemas {
+		if !s.subValidateWithContext(value, NewJsonContext(STRING_CONTEXT_ROOT, nil)).Valid() {
+			return false
+		}
+	}
+	return true
+}
+
+// gather returns the subschemas a value of s must satisfy, choosing the branches of anyOf, oneOf and if
+func (g *sampler) gather(s *subSchema, parts []*subSchema) []*subSchema {
+
+	for i := 0; s.refSchema != nil && i < sampleAttempts; i++ {
+		s = s.refSchema
+	}
+	if s.refSchema != nil {
+		return parts
+	}
+
+	parts = append(parts, s)
+	for _, branch := range s.allOf {
+		parts = g.gather(branch, parts)
+	}
+	for _, branches := range [][]*subSchema{s.anyOf, s.oneOf} {
+		if len(branches) > 0 {
+			parts = g.gather(branches[g.rand.Intn(len(branches))], parts)
+		}
+	}
+	if s._if != nil && (s._then != nil || s._else != nil) {
+		if s._else == nil || s._then != nil && g.rand.Intn(2) == 0 {
+			parts = g.gather(s._if, parts)
+			if s._then != nil {
+				parts = g.gather(s._then, parts)
+			}
+		} else {
+			parts = g.gather(s._else, parts)
+		}
+	}
+
+	return parts
+}
+
+// candidate returns a random value built from the keywords of the subschemas
+func (g *sampler) candidate(schemas []*subSchema, field string, depth int) (interface{}, error) {
+
+	var parts []*subSchema
+	for _, s := range schemas {
+		parts = g.gather(s, parts)
+	}
+
+	for _, s := range parts {
+		if values, _ := allowedValues(s); values != nil {
+			return decodeJsonValue(values[g.rand.Intn(len(values))]), nil
+		}
+	}
+
+	switch g.chooseType(parts) {
+	case TYPE_NULL:
+		return nil, nil
+	case TYPE_BOOLEAN:
+		return g.rand.Intn(2) == 0, nil
+	case TYPE_INTEGER:
+		return g.number(parts, true), nil
+	case TYPE_NUMBER:
+		return g.number(parts, false), nil
+	case TYPE_STRING:
+		return g.string(parts), nil
+	case TYPE_ARRAY:
+		return g.array(parts, field, depth)
+	}
+	return g.object(parts, field, depth)
+}
+
+// Keywords suggesting the type of an untyped schema
+var sampleTypeHints = map[string]func(s *subSchema) bool{
+	TYPE_OBJECT: func(s *subSchema) bool {
+		return len(s.propertiesChildren) > 0 || len(s.required) > 0 || s.patternProperties != nil || s.minProperties != nil
+	},
+	TYPE_ARRAY: func(s *subSchema) bool {
+		return len(s.itemsChildren) > 0 || s.minItems != nil || s.maxItems != nil || s.contains != nil
+	},
+	TYPE_STRING: func(s *subSchema) bool {
+		return s.minLength != nil || s.maxLength != nil || s.pattern != nil || s.format != ""
+	},
+	TYPE_NUMBER: func(s *subSchema) bool {
+		return s.minimum != nil || s.maximum != nil || s.multipleOf != nil
+	},
+}
+
+// chooseType returns a type all the subschemas allow, preferring the ones their keywords are about
+func (g *sampler) chooseType(parts []*subSchema) string {
+
+	var allowed, hinted []string
+	for _, t := range JSON_TYPES {
+		allowedByAll := true
+		for _, s := range parts {
+			allowedByAll = allowedByAll && allowsType(s.types, t)
+		}
+		if !allowedByAll {
+			continue
+		}
+		allowed = append(allowed, t)
+
+		hint := t
+		if t == TYPE_INTEGER {
+			hint = TYPE_NUMBER
+		}
+		for _, s := range parts {
+			if s.types.IsTyped() || sampleTypeHints[hint] != nil && sampleTypeHints[hint](s) {
+				hinted = append(hinted, t)
+				break
+			}
+		}
+	}
+
+	if len(hinted) > 0 {
+		return hinted[g.rand.Intn(len(hinted))]
+	}
+	if len(allowed) > 0 {
+		return allowed[g.rand.Intn(len(allowed))]
+	}
+	// no type is allowed by all, validation will fail
+	return TYPE_NULL
+}
+
+// bounds returns the tightest bounds of the subschemas, and their multipleOf. The numbers too large
+// to expand, like 1e1000000, are left out.
+func numberBounds(parts []*subSchema) (lower *big.Rat, lowerExclusive bool, upper *big.Rat, upperExclusive bool, multipleOf *big.Rat) {
+	for _, s := range parts {
+		if minimum, ok := sampleRat(s.minimum); ok && (lower == nil || minimum.Cmp(lower) > 0 || minimum.Cmp(lower) == 0 && s.exclusiveMinimum) {
+			lower, lowerExclusive = minimum, s.exclusiveMinimum
+		}
+		if maximum, ok := sampleRat(s.maximum); ok && (upper == nil || maximum.Cmp(upper) < 0 || maximum.Cmp(upper) == 0 && s.exclusiveMaximum) {
+			upper, upperExclusive = maximum, s.exclusiveMaximum
+		}
+		if value, ok := sampleRat(s.multipleOf); ok && multipleOf == nil {
+			multipleOf = value
+		}
+	}
+	return
+}
+
+// sampleRat returns the exact value of a keyword, false when it is not set or too large to expand
+func sampleRat(n *jsonNumberValue) (*big.Rat, bool) {
+	if n == nil {
+		return nil, false
+	}
+	return n.Rat()
+}
+
+// ceilRat and floorRat round a number to an integer
+func ceilRat(r *big.Rat) *big.Int {
+	q, m := new(big.Int).DivMod(r.Num(), r.Denom(), new(big.Int))
+	if m.Sign() != 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return q
+}
+
+func floorRat(r *big.Rat) *big.Int {
+	return new(big.Int).Div(r.Num(), r.Denom())
+}
+
+// integerRange returns the integers k, with k * unit within bounds
+func integerRange(lower *big.Rat, lowerExclusive bool, upper *big.Rat, upperExclusive bool, unit *big.Rat) (*big.Int, *big.Int) {
+
+	var lo, hi *big.Int
+	if lower != nil {
+		q := new(big.Rat).Quo(lower, unit)
+		lo = ceilRat(q)
+		if lowerExclusive && q.IsInt() {
+			lo.Add(lo, big.NewInt(1))
+		}
+	}
+	if upper != nil {
+		q := new(big.Rat).Quo(upper, unit)
+		hi = floorRat(q)
+		if upperExclusive && q.IsInt() {
+			hi.Sub(hi, big.NewInt(1))
+		}
+	}
+
+	switch {
+	case lo == nil && hi == nil:
+		lo, hi = big.NewInt(0), big.NewInt(sampleRange*sampleRange)
+	case lo == nil:
+		lo = new(big.Int).Sub(hi, big.NewInt(sampleRange*sampleRange))
+	case hi == nil:
+		hi = new(big.Int).Add(lo, big.NewInt(sampleRange*sampleRange))
+	}
+	return lo, hi
+}
+
+// randomInt returns an integer between lo and hi, that are not too far apart
+func (g *sampler) randomInt(lo *big.Int, hi *big.Int) *big.Int {
+	width := new(big.Int).Sub(hi, lo)
+	if width.Cmp(big.NewInt(sampleRange*sampleRange)) > 0 {
+		width.SetInt64(sampleRange * sampleRange)
+	}
+	return new(big.Int).Add(lo, big.NewInt(g.rand.Int63n(width.Int64()+1)))
+}
+
+func (g *sampler) number(parts []*subSchema, integer bool) interface{} {
+
+	lower, lowerExclusive, upper, upperExclusive, multipleOf := numberBounds(parts)
+
+	unit := multipleOf
+	if unit == nil {
+		unit = big.NewRat(1, 1)
+	}
+	lo, hi := integerRange(lower, lowerExclusive, upper, upperExclusive, unit)
+
+	if lo.Cmp(hi) <= 0 {
+		value := new(big.Rat).Mul(new(big.Rat).SetInt(g.randomInt(lo, hi)), unit)
+		if !integer && multipleOf == nil && g.rand.Intn(2) == 0 {
+			// a decimal within the bounds
+			decimal := new(big.Rat).Add(value, big.NewRat(1, 2))
+			if upper == nil || decimal.Cmp(upper) < 0 {
+				value = decimal
+			}
+		}
+		return formatRat(value)
+	}
+
+	// no multiple of the unit within the bounds, the middle of the range
+	if lower != nil && upper != nil {
+		middle := new(big.Rat).Add(lower, upper)
+		middle.Quo(middle, big.NewRat(2, 1))
+		return json.Number(strings.TrimRight(strings.TrimRight(middle.FloatString(sampleRange*2), "0"), "."))
+	}
+	return formatRat(big.NewRat(0, 1))
+}
+
+func (g *sampler) string(parts []*subSchema) interface{} {
+
+	minLength, maxLength := 0, -1
+	pattern, format := "", ""
+	for _, s := range parts {
+		if s.minLength != nil && *s.minLength > minLength {
+			minLength = *s.minLength
+		}
+		if s.maxLength != nil && (maxLength < 0 || *s.maxLength < maxLength) {
+			maxLength = *s.maxLength
+		}
+		if s.pattern != nil && pattern == "" {
+			pattern = s.pattern.String()
+		}
+		if s.format != "" && format == "" {
+			format = s.format
+		}
+	}
+
+	if format == "uuid" {
+		return g.uuid()
+	}
+	if values, ok := sampleFormats[format]; ok {
+		return values[g.rand.Intn(len(values))]
+	}
+
+	if pattern != "" {
+		if re, err := syntax.Parse(pattern, syntax.Perl); err == nil {
+			var b strings.Builder
+			if g.regexString(re.Simplify(), &b) {
+				return b.String()
+			}
+		}
+	}
+
+	if maxLength < 0 || maxLength > minLength+sampleRange {
+		maxLength = minLength + sampleRange
+	}
+	length := minLength
+	if maxLength > minLength {
+		length += g.rand.Intn(maxLength - minLength + 1)
+	}
+	runes := make([]rune, length)
+	for i := range runes {
+		runes[i] = rune(sampleAlphabet[g.rand.Intn(len(sampleAlphabet))])
+	}
+	return string(runes)
+}
+
+func (g *sampler) uuid() string {
+	const hex = "0123456789abcdef"
+	b := []byte("xxxxxxxx-xxxx-4xxx-axxx-xxxxxxxxxxxx")
+	for i := range b {
+		if b[i] == 'x' {
+			b[i] = hex[g.rand.Intn(len(hex))]
+		}
+	}
+	return string(b)
+}
+
+// regexString writes a random string matching a parsed regular expression, false when there is none
+func (g *sampler) regexString(re *syntax.Regexp, b *strings.Builder) bool {
+
+	repeat := func(min int, max int) bool {
+		if max < 0 || max > min+sampleRange/2 {
+			max = min + sampleRange/2
+		}
+		n := min + g.rand.Intn(max-min+1)
+		for i := 0; i < n; i++ {
+			if !g.regexString(re.Sub[0], b) {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch re.Op {
+	case syntax.OpNoMatch:
+		return false
+	case syntax.OpLiteral:
+		b.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		r, ok := g.classRune(re.Rune)
+		if !ok {
+			return false
+		}
+		b.WriteRune(r)
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteByte(sampleAlphabet[g.rand.Intn(len(sampleAlphabet))])
+	case syntax.OpCapture:
+		return g.regexString(re.Sub[0], b)
+	case syntax.OpStar:
+		return repeat(0, -1)
+	case syntax.OpPlus:
+		return repeat(1, -1)
+	case syntax.OpQuest:
+		return repeat(0, 1)
+	case syntax.OpRepeat:
+		return repeat(re.Min, re.Max)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if !g.regexString(sub, b) {
+				return false
+			}
+		}
+	case syntax.OpAlternate:
+		return g.regexString(re.Sub[g.rand.Intn(len(re.Sub))], b)
+	}
+	// assertions and empty matches do not write anything
+	return true
+}
+
+// classRune returns a random rune of a character class, printable ASCII when possible
+func (g *sampler) classRune(ranges []rune) (rune, bool) {
+
+	var printable []rune
+	for i := 0; i+1 < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		if lo < ' ' {
+			lo = ' '
+		}
+		if hi > '~' {
+			hi = '~'
+		}
+		if lo <= hi {
+			printable = append(printable, lo, hi)
+		}
+	}
+	if len(printable) > 0 {
+		ranges = printable
+	}
+	if len(ranges) < 2 {
+		return 0, false
+	}
+
+	i := 2 * g.rand.Intn(len(ranges)/2)
+	lo, hi := ranges[i], ranges[i+1]
+	if hi-lo > utf8.MaxRune/2 {
+		hi = lo + sampleRange*sampleRange
+	}
+	r := lo + rune(g.rand.Intn(int(hi-lo)+1))
+	if !utf8.ValidRune(r) {
+		return lo, true
+	}
+	return r, true
+}
+
+func (g *sampler) array(parts []*subSchema, field string, depth int) (interface{}, error) {
+
+	minItems, maxItems := 0, -1
+	unique := false
+	var tuple []*subSchema
+	var items, additionalItems, contains []*subSchema
+	noAdditionalItems := false
+	for _, s := range parts {
+		if s.minItems != nil && *s.minItems > minItems {
+			minItems = *s.minItems
+		}
+		if s.maxItems != nil && (maxItems < 0 || *s.maxItems < maxItems) {
+			maxItems = *s.maxItems
+		}
+		unique = unique || s.uniqueItems
+		if s.itemsChildrenIsSingleSchema {
+			items = append(items, s.itemsChildren...)
+		} else if len(s.itemsChildren) > 0 && tuple == nil {
+			tuple = s.itemsChildren
+			switch additional := s.additionalItems.(type) {
+			case bool:
+				noAdditionalItems = !additional
+			case *subSchema:
+				additionalItems = append(additionalItems, additional)
+			}
+		}
+		if s.contains != nil {
+			contains = append(contains, s.contains)
+		}
+	}
+
+	length := minItems
+	if depth < g.maxDepth {
+		extra := sampleRange / 2
+		if maxItems >= 0 && maxItems-minItems < extra {
+			extra = maxItems - minItems
+		}
+		if extra > 0 {
+			length += g.rand.Intn(extra + 1)
+		}
+	}
+	if len(contains) > 0 && length == 0 {
+		length = 1
+	}
+	if noAdditionalItems && length > len(tuple) {
+		length = len(tuple)
+	}
+
+	array := make([]interface{}, 0, length)
+	for i := 0; i < length; i++ {
+		var schemas []*subSchema
+		if i < len(tuple) {
+			schemas = append(schemas, tuple[i])
+		} else if tuple != nil {
+			schemas = append(schemas, additionalItems...)
+		}
+		schemas = append(schemas, items...)
+		if i == 0 {
+			schemas = append(schemas, contains...)
+		}
+
+		itemField := field + "." + strconv.Itoa(i)
+		var value interface{}
+		var err error
+		for attempt := 0; attempt < sampleAttempts; attempt++ {
+			value, err = g.generate(schemas, itemField, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			if !unique || !containsJsonValue(array, value) {
+				break
+			}
+		}
+		array = append(array, value)
+	}
+
+	return array, nil
+}
+
+// containsJsonValue reports whether a value is in an array, compared as JSON
+func containsJsonValue(array []interface{}, value interface{}) bool {
+	v, err := newJsonValue(value)
+	if err != nil {
+		return false
+	}
+	for _, item := range array {
+		if other, err := newJsonValue(item); err == nil && v.equals(other) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *sampler) object(parts []*subSchema, field string, depth int) (interface{}, error) {
+
+	minProperties, maxProperties := 0, -1
+	properties := map[string][]*subSchema{}
+	required := map[string]bool{}
+	noAdditionalProperties := false
+	var additional []*subSchema
+	for _, s := range parts {
+		if s.minProperties != nil && *s.minProperties > minProperties {
+			minProperties = *s.minProperties
+		}
+		if s.maxProperties != nil && (maxProperties < 0 || *s.maxProperties < maxProperties) {
+			maxProperties = *s.maxProperties
+		}
+		for _, property := range s.propertiesChildren {
+			properties[property.property] = append(properties[property.property], property)
+		}
+		for _, name := range s.required {
+			required[name] = true
+		}
+		switch a := s.additionalProperties.(type) {
+		case bool:
+			noAdditionalProperties = noAdditionalProperties || !a
+		case *subSchema:
+			additional = append(additional, a)
+		}
+	}
+
+	// the required properties, then optional ones at random
+	var names []string
+	for _, name := range sortedMapKeys(required) {
+		names = append(names, name)
+	}
+	var optional []string
+	for _, name := range sortedMapKeys(properties) {
+		if !required[name] {
+			optional = append(optional, name)
+		}
+	}
+	g.rand.Shuffle(len(optional), func(i, j int) { optional[i], optional[j] = optional[j], optional[i] })
+	for _, name := range optional {
+		if len(names) >= minProperties && (depth >= g.maxDepth || maxProperties >= 0 && len(names) >= maxProperties || g.rand.Intn(2) == 0) {
+			continue
+		}
+		names = append(names, name)
+	}
+	for i := 1; len(names) < minProperties && !noAdditionalProperties; i++ {
+		names = append(names, "property"+strconv.Itoa(i))
+	}
+
+	object := make(map[string]interface{}, len(names))
+	for i := 0; i < len(names); i++ {
+		name := names[i]
+		schemas := properties[name]
+		for _, s := range parts {
+			for _, k := range sortedMapKeys(s.patternProperties) {
+				if s.patternMatchers[k].MatchString(name) {
+					schemas = append(schemas, s.patternProperties[k])
+				}
+			}
+		}
+		if _, declared := properties[name]; !declared && len(schemas) == 0 {
+			schemas = additional
+		}
+
+		// the properties the property depends on are added
+		for _, s := range parts {
+			if dependency, ok := s.dependencies[name].([]string); ok {
+				for _, dependent := range dependency {
+					if !isStringInSlice(names, dependent) {
+						names = append(names, dependent)
+					}
+				}
+			}
+		}
+
+		value, err := g.generate(schemas, field+"."+name, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		object[name] = value
+	}
+
+	return object, nil
+}
+
+// GenerateInvalidSamples returns variants of a valid sample, each violating a keyword of the schema.
+// The keywords of the subschemas the sample reaches outside of anyOf, oneOf, not and if are violated,
+// the subschemas of other documents than the schema one being left out.
+func GenerateInvalidSamples(schema *Schema, options SampleOptions) ([]InvalidSample, error) {
+
+	g := newSampler(schema, options)
+	document, err := g.generate([]*subSchema{schema.rootSchema}, STRING_CONTEXT_ROOT, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &mutator{
+		sampler:  g,
+		schema:   schema,
+		document: document,
+		pointers: map[*subSchema]string{},
+		done:     map[string]bool{},
+	}
+	m.index(schema.rootSchema, schema.pool.GetStandaloneDocument(), "#")
+	m.walk(schema.rootSchema, document, nil)
+
+	return m.samples, nil
+}
+
+type mutator struct {
+	sampler  *sampler
+	schema   *Schema
+	document interface{}
+	// subschemas of the schema document and their pointers
+	pointers map[*subSchema]string
+	// pointer and keyword of the samples
+	done    map[string]bool
+	samples []InvalidSample
+}
+
+func (m *mutator) index(s *subSchema, node interface{}, pointer string) {
+	if _, ok := m.pointers[s]; ok {
+		return
+	}
+	m.pointers[s] = pointer
+	walkSubSchemas(s, node, func(child *subSchema, childNode interface{}, tokens ...string) {
+		childPointer := pointer
+		for _, token := range tokens {
+			childPointer += "/" + jsonPointerToken(token)
+		}
+		m.index(child, childNode, childPointer)
+	})
+}
+
+// walk mutates the value at path against s, then the values of its properties and items
+func (m *mutator) walk(s *subSchema, value interface{}, path []interface{}) {
+
+	for i := 0; s.refSchema != nil && i < sampleAttempts; i++ {
+		s = s.refSchema
+	}
+
+	if pointer, ok := m.pointers[s]; ok {
+		m.mutate(s, pointer, value, path)
+	}
+	for _, branch := range s.allOf {
+		m.walk(branch, value, path)
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, name := range sortedMapKeys(v) {
+			matched := false
+			for _, property := range s.propertiesChildren {
+				if property.property == name {
+					m.walk(property, v[name], appendPath(path, name))
+					matched = true
+				}
+			}
+			for _, k := range sortedMapKeys(s.patternProperties) {
+				if s.patternMatchers[k].MatchString(name) {
+					m.walk(s.patternProperties[k], v[name], appendPath(path, name))
+					matched = true
+				}
+			}
+			if additional, ok := s.additionalProperties.(*subSchema); ok && !matched {
+				m.walk(additional, v[name], appendPath(path, name))
+			}
+		}
+		if len(path) < m.sampler.maxDepth {
+			m.walkOptional(s, v, path)
+		}
+	case []interface{}:
+		for i, item := range v {
+			if s.itemsChildrenIsSingleSchema {
+				m.walk(s.itemsChildren[0], item, appendPath(path, i))
+			} else if i < len(s.itemsChildren) {
+				m.walk(s.itemsChildren[i], item, appendPath(path, i))
+			} else if additional, ok := s.additionalItems.(*subSchema); ok {
+				m.walk(additional, item, appendPath(path, i))
+			}
+		}
+	}
+}
+
+// walkOptional walks the properties of s missing from the value, added to the document when it stays valid
+func (m *mutator) walkOptional(s *subSchema, v map[string]interface{}, path []interface{}) {
+
+	properties := map[string]*subSchema{}
+	for _, property := range s.propertiesChildren {
+		properties[property.property] = property
+	}
+
+	for _, name := range sortedMapKeys(properties) {
+		if _, ok := v[name]; ok {
+			continue
+		}
+		value, err := m.sampler.generate([]*subSchema{properties[name]}, STRING_CONTEXT_ROOT, len(path)+1)
+		if err != nil {
+			continue
+		}
+		document := replaceAtPath(m.document, appendPath(path, name), value)
+		if !m.schema.validateDocument(document).Valid() {
+			continue
+		}
+
+		valid := m.document
+		m.document = document
+		m.walk(properties[name], value, appendPath(path, name))
+		m.document = valid
+	}
+}
+
+func appendPath(path []interface{}, token interface{}) []interface{} {
+	return append(append([]interface{}{}, path...), token)
+}
+
+// mutate adds the samples violating the keywords of s with the value at path
+func (m *mutator) mutate(s *subSchema, pointer string, value interface{}, path []interface{}) {
+
+	try := func(keyword string, errorType string, mutated interface{}) {
+		key := pointer + " " + keyword
+		if m.done[key] {
+			return
+		}
+		document := replaceAtPath(m.document, path, mutated)
+		for _, err := range m.schema.validateDocument(document).Errors() {
+			if err.Type() == errorType {
+				m.done[key] = true
+				m.samples = append(m.samples, InvalidSample{
+					Document: document,
+					Pointer:  pointer,
+					Keyword:  keyword,
+					Field:    err.Field(),
+					Type:     errorType,
+				})
+				return
+			}
+		}
+	}
+
+	if s.types.IsTyped() {
+		for _, other := range []interface{}{nil, false, json.Number("0.5"), json.Number("1"), "", []interface{}{}, map[string]interface{}{}} {
+			if !s.types.Contains(jsonTypeOf(other)) && !(jsonTypeOf(other) == TYPE_INTEGER && s.types.Contains(TYPE_NUMBER)) {
+				try(KEY_TYPE, "invalid_type", other)
+				break
+			}
+		}
+	}
+
+	if values, keyword := allowedValues(s); values != nil {
+		for _, other := range []interface{}{nil, false, json.Number("-1"), "", "value", []interface{}{}, map[string]interface{}{}} {
+			if s._const != nil && !s._const.equals(mustJsonValue(other)) || s._const == nil && s.enum.find(mustJsonValue(other)) == nil {
+				try(keyword, keyword, other)
+				break
+			}
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		m.mutateString(s, v, try)
+	case json.Number:
+		m.mutateNumber(s, v, try)
+	case []interface{}:
+		m.mutateArray(s, v, try)
+	case map[string]interface{}:
+		m.mutateObject(s, v, try)
+	}
+}
+
+func (m *mutator) mutateString(s *subSchema, v string, try func(string, string, interface{})) {
+
+	runes := []rune(v)
+	if s.minLength != nil && *s.minLength > 0 {
+		try(KEY_MIN_LENGTH, "string_gte", string(runes[:*s.minLength-1]))
+	}
+	if s.maxLength != nil {
+		try(KEY_MAX_LENGTH, "string_lte", v+strings.Repeat("a", *s.maxLength+1-len(runes)))
+	}
+	if s.pattern != nil {
+		for _, other := range []string{"", " ", "!", "0", "a", "A", "_"} {
+			if !s.pattern.MatchString(other) {
+				try(KEY_PATTERN, "pattern", other)
+				break
+			}
+		}
+	}
+	if s.format != "" && s.formatCheckers().Has(s.format) {
+		for _, other := range []string{"not a " + s.format, "", "!"} {
+			if !s.formatCheckers().IsFormat(s.format, other) {
+				try(KEY_FORMAT, "format", other)
+				break
+			}
+		}
+	}
+}
+
+func (m *mutator) mutateNumber(s *subSchema, v json.Number, try func(string, string, interface{})) {
+
+	if minimum, ok := sampleRat(s.minimum); ok {
+		if s.exclusiveMinimum {
+			try(KEY_EXCLUSIVE_MINIMUM, "number_gt", formatRat(minimum))
+		} else {
+			try(KEY_MINIMUM, "number_gte", formatRat(new(big.Rat).Sub(minimum, big.NewRat(1, 1))))
+		}
+	}
+	if maximum, ok := sampleRat(s.maximum); ok {
+		if s.exclusiveMaximum {
+			try(KEY_EXCLUSIVE_MAXIMUM, "number_lt", formatRat(maximum))
+		} else {
+			try(KEY_MAXIMUM, "number_lte", formatRat(new(big.Rat).Add(maximum, big.NewRat(1, 1))))
+		}
+	}
+	if multipleOf, ok := sampleRat(s.multipleOf); ok {
+		if value, ok := parseJsonNumber(v); ok {
+			if r, ok := value.Rat(); ok {
+				// the value moved by half the divisor
+				try(KEY_MULTIPLE_OF, "multiple_of", formatRat(r.Add(r, new(big.Rat).Quo(multipleOf, big.NewRat(2, 1)))))
+			}
+		}
+	}
+}
+
+func (m *mutator) mutateArray(s *subSchema, v []interface{}, try func(string, string, interface{})) {
+
+	if s.minItems != nil && *s.minItems > 0 && len(v) >= *s.minItems {
+		try(KEY_MIN_ITEMS, "array_min_items", v[:*s.minItems-1])
+	}
+	if s.maxItems != nil {
+		try(KEY_MAX_ITEMS, "array_max_items", padArray(v, *s.maxItems+1))
+	}
+	if s.uniqueItems {
+		duplicated := padArray(v, 1)
+		try(KEY_UNIQUE_ITEMS, "unique", append(append([]interface{}{}, duplicated...), duplicated[0]))
+	}
+	if s.contains != nil {
+		try(KEY_CONTAINS, "contains", []interface{}{})
+	}
+	if additional, ok := s.additionalItems.(bool); ok && !additional && !s.itemsChildrenIsSingleSchema {
+		try(KEY_ADDITIONAL_ITEMS, "array_no_additional_items", padArray(v, len(s.itemsChildren)+1))
+	}
+}
+
+// padArray returns a copy of an array repeating its last item up to a length, null when it is empty
+func padArray(v []interface{}, length int) []interface{} {
+	padded := append([]interface{}{}, v...)
+	for len(padded) < length {
+		var last interface{}
+		if len(padded) > 0 {
+			last = convertDocumentNode(padded[len(padded)-1])
+		}
+		padded = append(padded, last)
+	}
+	return padded
+}
+
+func (m *mutator) mutateObject(s *subSchema, v map[string]interface{}, try func(string, string, interface{})) {
+
+	without := func(names ...string) map[string]interface{} {
+		object := map[string]interface{}{}
+		for k, value := range v {
+			if !isStringInSlice(names, k) {
+				object[k] = value
+			}
+		}
+		return object
+	}
+	with := func(count int) map[string]interface{} {
+		object := without()
+		for i := 1; len(object) < count; i++ {
+			object["property"+strconv.Itoa(i)] = nil
+		}
+		return object
+	}
+
+	for _, name := range s.required {
+		if _, ok := v[name]; ok {
+			try(KEY_REQUIRED, "required", without(name))
+			break
+		}
+	}
+	if s.minProperties != nil && *s.minProperties > 0 {
+		names := sortedMapKeys(v)
+		if len(names) >= *s.minProperties {
+			try(KEY_MIN_PROPERTIES, "array_min_properties", without(names[*s.minProperties-1:]...))
+		}
+	}
+	if s.maxProperties != nil {
+		try(KEY_MAX_PROPERTIES, "array_max_properties", with(*s.maxProperties+1))
+	}
+	if additional, ok := s.additionalProperties.(bool); ok && !additional {
+		try(KEY_ADDITIONAL_PROPERTIES, "additional_property_not_allowed", with(len(v)+1))
+	}
+	for _, name := range sortedMapKeys(s.dependencies) {
+		if dependency, ok := s.dependencies[name].([]string); ok && len(dependency) > 0 {
+			if _, ok := v[name]; ok {
+				try(KEY_DEPENDENCIES, "missing_dependency", without(dependency[0]))
+				break
+			}
+		}
+	}
+}
+
+// replaceAtPath returns a copy of a document with the value at path replaced
+func replaceAtPath(document interface{}, path []interface{}, value interface{}) interface{} {
+	if len(path) == 0 {
+		return value
+	}
+	switch node := document.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(node))
+		for k, v := range node {
+			copied[k] = v
+		}
+		key := path[0].(string)
+		copied[key] = replaceAtPath(node[key], path[1:], value)
+		return copied
+	case []interface{}:
+		copied := append([]interface{}{}, node...)
+		i := path[0].(int)
+		copied[i] = replaceAtPath(node[i], path[1:], value)
+		return copied
+	}
+	return document
+}
+
+// jsonTypeOf returns the JSON type of a sample value, numbers being json.Number
+func jsonTypeOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return TYPE_NULL
+	case bool:
+		return TYPE_BOOLEAN
+	case json.Number:
+		if checkJsonInteger(v) {
+			return TYPE_INTEGER
+		}
+		return TYPE_NUMBER
+	case string:
+		return TYPE_STRING
+	case []interface{}:
+		return TYPE_ARRAY
+	}
+	return TYPE_OBJECT
+}
+
+func mustJsonValue(value interface{}) *jsonValue {
+	v, _ := newJsonValue(value)
+	return v
+}
@@ -0,0 +1,138 @@
+package gojsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const sampleSchema = `{
+	"definitions": {
+		"tag": {"type": "string", "pattern": "^[a-z]{2,6}-\\d{3}$"},
+		"node": {
+			"type": "object",
+			"properties": {
+				"value": {"type": "integer"},
+				"children": {"type": "array", "items": {"$ref": "#/definitions/node"}, "maxItems": 2}
+			},
+			"required": ["value"]
+		}
+	},
+	"type": "object",
+	"properties": {
+		"id": {"type": "string", "format": "uuid"},
+		"email": {"type": "string", "format": "email"},
+		"name": {"type": "string", "minLength": 2, "maxLength": 10},
+		"age": {"type": "integer", "minimum": 18, "exclusiveMaximum": 120},
+		"price": {"type": "number", "multipleOf": 0.25, "minimum": 1},
+		"status": {"enum": ["active", "inactive"]},
+		"tags": {"type": "array", "items": {"$ref": "#/definitions/tag"}, "minItems": 1, "uniqueItems": true},
+		"contact": {"oneOf": [{"type": "string", "format": "hostname"}, {"type": "integer", "maximum": 0}]},
+		"tree": {"$ref": "#/definitions/node"}
+	},
+	"required": ["id", "name", "age", "status", "tags", "tree"],
+	"additionalProperties": false
+}`
+
+func TestGenerateSample(t *testing.T) {
+	schema, err := NewSchema(NewStringLoader(sampleSchema))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	for seed := int64(0); seed < 20; seed++ {
+		document, err := GenerateSample(schema, SampleOptions{Seed: seed})
+		if !assert.Nil(t, err) {
+			return
+		}
+		result, err := schema.Validate(NewGoLoader(document))
+		assert.Nil(t, err)
+		assert.True(t, result.Valid(), "seed %d: %v", seed, result.Errors())
+	}
+
+	// the same seed gives the same sample
+	first, _ := GenerateSample(schema, SampleOptions{Seed: 42})
+	second, _ := GenerateSample(schema, SampleOptions{Seed: 42})
+	assert.Equal(t, first, second)
+}
+
+func TestGenerateSampleDepth(t *testing.T) {
+	schema, err := NewSchema(NewStringLoader(`{
+		"type": "object",
+		"properties": {"next": {"$ref": "#"}}
+	}`))
+	if !assert.Nil(t, err) {
+		return
+	}
+	document, err := GenerateSample(schema, SampleOptions{Seed: 1, MaxDepth: 2})
+	assert.Nil(t, err)
+	depth := 0
+	for node, ok := document.(map[string]interface{}); ok; node, ok = node["next"].(map[string]interface{}) {
+		depth++
+	}
+	assert.True(t, depth <= 3)
+
+	// values required at every level cannot be generated
+	schema, err = NewSchema(NewStringLoader(`{
+		"type": "object",
+		"properties": {"next": {"$ref": "#"}},
+		"required": ["next"]
+	}`))
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, err = GenerateSample(schema, SampleOptions{})
+	assert.NotNil(t, err)
+
+	schema, err = NewSchema(NewStringLoader(`{"type": "string", "not": {"type": "string"}}`))
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, err = GenerateSample(schema, SampleOptions{})
+	assert.EqualError(t, err, "No valid value could be generated for (root)")
+}
+
+func TestGenerateInvalidSamples(t *testing.T) {
+	schema, err := NewSchema(NewStringLoader(sampleSchema))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	samples, err := GenerateInvalidSamples(schema, SampleOptions{Seed: 3})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	labels := map[string]string{}
+	for _, sample := range samples {
+		labels[sample.Pointer+" "+sample.Keyword] = sample.Type
+
+		result, err := schema.Validate(NewGoLoader(sample.Document))
+		assert.Nil(t, err)
+		found := false
+		for _, resultError := range result.Errors() {
+			found = found || resultError.Type() == sample.Type && resultError.Field() == sample.Field
+		}
+		assert.True(t, found, "%s %s: %v", sample.Pointer, sample.Keyword, result.Errors())
+	}
+
+	for label, errorType := range map[string]string{
+		"# type":                                   "invalid_type",
+		"# required":                               "required",
+		"# additionalProperties":                   "additional_property_not_allowed",
+		"#/properties/id format":                   "format",
+		"#/properties/name minLength":              "string_gte",
+		"#/properties/name maxLength":              "string_lte",
+		"#/properties/age minimum":                 "number_gte",
+		"#/properties/age exclusiveMaximum":        "number_lt",
+		"#/properties/status enum":                 "enum",
+		"#/properties/price multipleOf":            "multiple_of",
+		"#/properties/tags minItems":               "array_min_items",
+		"#/properties/tags uniqueItems":            "unique",
+		"#/definitions/tag pattern":                "pattern",
+		"#/definitions/node required":              "required",
+		"#/definitions/node/properties/value type": "invalid_type",
+	} {
+		assert.Equal(t, errorType, labels[label], label)
+	}
+}
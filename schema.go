@@ -28,9 +28,7 @@ package gojsonschema
 
 import (
 	"errors"
-	"math/big"
 	"reflect"
-	"regexp"
 	"text/template"
 
 	"github.com/xeipuuv/gojsonreference"
@@ -39,61 +37,22 @@ import (
 var (
 	// Locale is the default locale to use
 	// Library users can overwrite with their own implementation
-	Locale locale = DefaultLocale{}
+	// ( see also ValidateWithLocale and Result.Localize to use another locale for a validation )
+	Locale LocaleMessages = DefaultLocale{}
 
 	// ErrorTemplateFuncs allows you to define custom template funcs for use in localization.
 	ErrorTemplateFuncs template.FuncMap
 )
 
-func NewSchema(l JSONLoader) (*Schema, error) {
-	ref, err := l.JsonReference()
-	if err != nil {
-		return nil, err
-	}
-
-	d := Schema{}
-	d.pool = newSchemaPool(l.LoaderFactory())
-	d.documentReference = ref
-	d.referencePool = newSchemaReferencePool()
-
-	var spd *schemaPoolDocument
-	var doc interface{}
-	if ref.String() != "" {
-		// Get document from schema pool
-		spd, err = d.pool.GetDocument(d.documentReference)
-		if err != nil {
-			return nil, err
-		}
-		doc = spd.Document
-
-		// Deal with fragment pointers
-		jsonPointer := ref.GetPointer()
-		doc, _, err = jsonPointer.Get(doc)
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		// Load JSON directly
-		doc, err = l.LoadJSON()
-		if err != nil {
-			return nil, err
-		}
-	}
-	d.pool.SetStandaloneDocument(doc)
-
-	err = d.parse(doc)
-	if err != nil {
-		return nil, err
-	}
-
-	return &d, nil
-}
-
 type Schema struct {
 	documentReference gojsonreference.JsonReference
 	rootSchema        *subSchema
 	pool              *schemaPool
 	referencePool     *schemaReferencePool
+	dialect           Dialect
+	draft             Draft
+	regexEngine       RegexEngine
+	keywords          map[string]Keyword
 }
 
 func (d *Schema) parse(document interface{}) error {
@@ -113,6 +72,11 @@ func (d *Schema) SetRootSchemaName(name string) {
 //
 func (d *Schema) parseSchema(documentNode interface{}, currentSchema *subSchema) error {
 
+	documentNode, err := checkDraftKeywords(documentNode, d.draft)
+	if err != nil {
+		return err
+	}
+
 	// As of draft 6 "true" is equivalent to an empty schema "{}" and false equals "{"not":{}}"
 	if isKind(documentNode, reflect.Bool) {
 		b := documentNode.(bool)
@@ -134,6 +98,19 @@ func (d *Schema) parseSchema(documentNode interface{}, currentSchema *subSchema)
 
 	m := documentNode.(map[string]interface{})
 
+	if d.dialect == DialectMongoDB {
+		err := checkMongoDBKeywords(m)
+		if err != nil {
+			return err
+		}
+	}
+	if d.dialect == DialectOpenAPI30 {
+		err := d.checkOpenAPIKeywords(m)
+		if err != nil {
+			return err
+		}
+	}
+
 	if currentSchema.parent == nil {
 		currentSchema.ref = &d.documentReference
 		currentSchema.id = &d.documentReference
@@ -345,6 +322,30 @@ func (d *Schema) parseSchema(documentNode interface{}, currentSchema *subSchema)
 		}
 	}
 
+	// bsonType
+	if d.dialect == DialectMongoDB {
+		if currentSchema.types.Contains(TYPE_INTEGER) {
+			return errors.New(formatErrorDescription(
+				Locale.KeywordNotSupported(),
+				ErrorDetails{"key": KEY_TYPE + " " + TYPE_INTEGER, "dialect": "MongoDB $jsonSchema"},
+			))
+		}
+		if existsMapKey(m, KEY_BSON_TYPE) {
+			err := parseBSONType(m[KEY_BSON_TYPE], currentSchema)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// nullable, discriminator & formats
+	if d.dialect == DialectOpenAPI30 {
+		err := d.parseOpenAPI(m, currentSchema)
+		if err != nil {
+			return err
+		}
+	}
+
 	// properties
 	if existsMapKey(m, KEY_PROPERTIES) {
 		err := d.parseProperties(m[KEY_PROPERTIES], currentSchema)
@@ -381,8 +382,9 @@ func (d *Schema) parseSchema(documentNode interface{}, currentSchema *subSchema)
 			patternPropertiesMap := m[KEY_PATTERN_PROPERTIES].(map[string]interface{})
 			if len(patternPropertiesMap) > 0 {
 				currentSchema.patternProperties = make(map[string]*subSchema)
+				currentSchema.patternMatchers = make(map[string]RegexMatcher)
 				for k, v := range patternPropertiesMap {
-					_, err := regexp.MatchString(k, "")
+					matcher, err := d.regexEngine.Compile(k)
 					if err != nil {
 						return errors.New(formatErrorDescription(
 							Locale.RegexPattern(),
@@ -395,6 +397,7 @@ func (d *Schema) parseSchema(documentNode interface{}, currentSchema *subSchema)
 						return errors.New(err.Error())
 					}
 					currentSchema.patternProperties[k] = newSchema
+					currentSchema.patternMatchers[k] = matcher
 				}
 			}
 		} else {
@@ -514,7 +517,7 @@ func (d *Schema) parseSchema(documentNode interface{}, currentSchema *subSchema)
 				},
 			))
 		}
-		if multipleOfValue.Cmp(big.NewFloat(0)) <= 0 {
+		if multipleOfValue.Sign() <= 0 {
 			return errors.New(formatErrorDescription(
 				Locale.GreaterThanZero(),
 				ErrorDetails{"number": KEY_MULTIPLE_OF},
@@ -546,7 +549,12 @@ func (d *Schema) parseSchema(documentNode interface{}, currentSchema *subSchema)
 			currentSchema.exclusiveMinimum = exclusiveMinimumValue
 		} else if isJsonNumber(m[KEY_EXCLUSIVE_MINIMUM]) {
 			minimumValue := mustBeNumber(m[KEY_EXCLUSIVE_MINIMUM])
-
+			if minimumValue == nil {
+				return errors.New(formatErrorDescription(
+					Locale.MustBeOfA(),
+					ErrorDetails{"x": KEY_EXCLUSIVE_MINIMUM, "y": STRING_NUMBER},
+				))
+			}
 			currentSchema.minimum = minimumValue
 			currentSchema.exclusiveMinimum = true
 		} else {
@@ -580,7 +588,12 @@ func (d *Schema) parseSchema(documentNode interface{}, currentSchema *subSchema)
 			currentSchema.exclusiveMaximum = exclusiveMaximumValue
 		} else if isJsonNumber(m[KEY_EXCLUSIVE_MAXIMUM]) {
 			maximumValue := mustBeNumber(m[KEY_EXCLUSIVE_MAXIMUM])
-
+			if maximumValue == nil {
+				return errors.New(formatErrorDescription(
+					Locale.MustBeOfA(),
+					ErrorDetails{"x": KEY_EXCLUSIVE_MAXIMUM, "y": STRING_NUMBER},
+				))
+			}
 			currentSchema.maximum = maximumValue
 			currentSchema.exclusiveMaximum = true
 		} else {
@@ -647,7 +660,7 @@ func (d *Schema) parseSchema(documentNode interface{}, currentSchema *subSchema)
 
 	if existsMapKey(m, KEY_PATTERN) {
 		if isKind(m[KEY_PATTERN], reflect.String) {
-			regexpObject, err := regexp.Compile(m[KEY_PATTERN].(string))
+			regexpObject, err := d.regexEngine.Compile(m[KEY_PATTERN].(string))
 			if err != nil {
 				return errors.New(formatErrorDescription(
 					Locale.MustBeValidRegex(),
@@ -665,8 +678,11 @@ func (d *Schema) parseSchema(documentNode interface{}, currentSchema *subSchema)
 
 	if existsMapKey(m, KEY_FORMAT) {
 		formatString, ok := m[KEY_FORMAT].(string)
-		if ok && FormatCheckers.Has(formatString) {
+		if ok {
 			currentSchema.format = formatString
+			if !currentSchema.formatCheckers().Has(formatString) {
+				currentSchema.format = ""
+			}
 		}
 	}
 
@@ -940,7 +956,12 @@ func (d *Schema) parseSchema(documentNode interface{}, currentSchema *subSchema)
 		}
 	}
 
-	return nil
+	err = d.parseErrorMessage(m, currentSchema)
+	if err != nil {
+		return err
+	}
+
+	return d.parseKeywords(m, currentSchema)
 }
 
 func (d *Schema) parseReference(documentNode interface{}, currentSchema *subSchema) error {
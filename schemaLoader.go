@@ -0,0 +1,231 @@
+package gojsonschema
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/xeipuuv/gojsonreference"
+)
+
+// Dialect selects the flavour of JSON Schema a schema is compiled as
+type Dialect int
+
+const (
+	// DialectJSONSchema is plain JSON Schema ( draft-04, draft-06 and draft-07 ).
+	DialectJSONSchema Dialect = iota
+	// DialectMongoDB is the $jsonSchema dialect of MongoDB collection validators.
+	// It adds the bsonType keyword and rejects the keywords the server does not support.
+	DialectMongoDB
+	// DialectOpenAPI30 is the Schema Object of OpenAPI 3.0. It adds the nullable and discriminator
+	// keywords, makes the bounds exclusive by a boolean, and rejects type arrays and the keywords
+	// the Schema Object does not support, but x- extensions.
+	DialectOpenAPI30
+)
+
+// URL of the documents of CompileAt without one
+const embeddedDocumentURL = "gojsonschema://document"
+
+// SchemaLoader holds the options used to compile schemas
+type SchemaLoader struct {
+	// Dialect of the compiled schemas, defaults to DialectJSONSchema
+	Dialect Dialect
+	// Draft of the compiled schemas, defaults to Hybrid
+	Draft Draft
+	// RegexEngine compiles pattern and patternProperties, defaults to GoRegexEngine
+	RegexEngine RegexEngine
+	// Validate the root schema document against its meta-schema before compiling it,
+	// an *InvalidSchemaError listing all the violations being returned if it does not match.
+	// The meta-schema is an embedded draft one or a document registered with AddSchema.
+	Validate bool
+
+	// documents registered with AddSchema, by URL
+	documents map[string]interface{}
+	// custom keywords registered with AddKeyword, by name
+	keywords map[string]Keyword
+}
+
+// NewSchemaLoader creates a SchemaLoader with the default options
+func NewSchemaLoader() *SchemaLoader {
+	return &SchemaLoader{}
+}
+
+// NewSchema compiles a schema using the default options
+func NewSchema(l JSONLoader) (*Schema, error) {
+	return NewSchemaLoader().Compile(l)
+}
+
+// AddSchema registers a schema document, so the compiled schemas can reference it by url without loading it.
+// An empty url stands for the $id ( or id ) of the document, or else the location of the loader.
+func (sl *SchemaLoader) AddSchema(url string, loader JSONLoader) error {
+
+	doc, err := loader.LoadJSON()
+	if err != nil {
+		return err
+	}
+	doc = convertDocumentNode(doc)
+
+	if url == "" {
+		url = documentID(doc)
+	}
+	if url == "" {
+		ref, err := loader.JsonReference()
+		if err != nil {
+			return err
+		}
+		url = ref.String()
+	}
+
+	ref, err := gojsonreference.NewJsonReference(url)
+	if err != nil {
+		return err
+	}
+	if !ref.IsCanonical() {
+		return errors.New(formatErrorDescription(
+			Locale.ReferenceMustBeCanonical(),
+			ErrorDetails{"reference": url},
+		))
+	}
+	ref.GetUrl().Fragment = ""
+
+	if sl.documents == nil {
+		sl.documents = make(map[string]interface{})
+	}
+	sl.documents[ref.String()] = doc
+
+	return nil
+}
+
+// documentID returns the $id ( or id ) of a schema document
+func documentID(doc interface{}) string {
+	if m, ok := doc.(map[string]interface{}); ok {
+		for _, k := range []string{KEY_ID_NEW, KEY_ID} {
+			if id, ok := m[k].(string); ok {
+				return id
+			}
+		}
+	}
+	return ""
+}
+
+// Compile loads and parses the root schema using the options of the SchemaLoader
+func (sl *SchemaLoader) Compile(rootSchema JSONLoader) (*Schema, error) {
+	ref, err := rootSchema.JsonReference()
+	if err != nil {
+		return nil, err
+	}
+
+	d := Schema{}
+	d.pool = sl.newSchemaPool(rootSchema.LoaderFactory())
+	d.documentReference = ref
+	d.referencePool = newSchemaReferencePool()
+	d.dialect = sl.Dialect
+	d.draft = sl.Draft
+	d.regexEngine = sl.RegexEngine
+	if d.regexEngine == nil {
+		d.regexEngine = defaultRegexEngine
+	}
+	d.keywords = make(map[string]Keyword, len(sl.keywords))
+	for name, keyword := range sl.keywords {
+		d.keywords[name] = keyword
+	}
+
+	doc, err := loadRootDocument(d.pool, ref, rootSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.dialect == DialectMongoDB {
+		doc = unwrapMongoDBValidator(doc)
+	} else if sl.Validate {
+		result, err := validateSchemaDocument(doc, sl.Draft, sl.documents)
+		if err != nil {
+			return nil, err
+		}
+		if !result.Valid() {
+			return nil, &InvalidSchemaError{Result: result}
+		}
+	}
+
+	d.pool.SetStandaloneDocument(doc)
+
+	// the root document can be referenced by its $id, wherever it was loaded from
+	if id, err := gojsonreference.NewJsonReference(documentID(doc)); err == nil && id.IsCanonical() {
+		id.GetUrl().Fragment = ""
+		if _, exists := d.pool.schemaPoolDocuments[id.String()]; !exists {
+			d.pool.schemaPoolDocuments[id.String()] = &schemaPoolDocument{Document: doc}
+		}
+	}
+
+	err = d.parse(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// CompileAt compiles the schema at a JSON pointer of a document, like "#/components/schemas/Pet" in
+// an OpenAPI document, its references being resolved within the whole document
+func (sl *SchemaLoader) CompileAt(document JSONLoader, pointer string) (*Schema, error) {
+
+	ref, err := document.JsonReference()
+	if err != nil {
+		return nil, err
+	}
+	url := embeddedDocumentURL
+	if ref.IsCanonical() {
+		ref.GetUrl().Fragment = ""
+		url = ref.String()
+	}
+
+	loader := *sl
+	loader.documents = map[string]interface{}{}
+	for u, doc := range sl.documents {
+		loader.documents[u] = doc
+	}
+	if err := loader.AddSchema(url, document); err != nil {
+		return nil, err
+	}
+
+	return loader.Compile(NewReferenceLoader(url + "#" + strings.TrimPrefix(pointer, "#")))
+}
+
+// newSchemaPool returns a pool holding the documents registered with AddSchema
+func (sl *SchemaLoader) newSchemaPool(f JSONLoaderFactory) *schemaPool {
+	pool := newSchemaPool(f)
+	for url, doc := range sl.documents {
+		pool.schemaPoolDocuments[url] = &schemaPoolDocument{Document: doc}
+	}
+	return pool
+}
+
+// loadRootDocument loads the root schema of a loader, through the pool when it has a reference
+func loadRootDocument(pool *schemaPool, ref gojsonreference.JsonReference, rootSchema JSONLoader) (interface{}, error) {
+
+	var doc interface{}
+	if ref.String() != "" {
+		// Get document from schema pool
+		spd, err := pool.GetDocument(ref)
+		if err != nil {
+			return nil, err
+		}
+		doc = spd.Document
+
+		// Deal with fragment pointers
+		jsonPointer := ref.GetPointer()
+		doc, _, err = jsonPointer.Get(doc)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// Load JSON directly
+		var err error
+		doc, err = rootSchema.LoadJSON()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Schemas decoded from YAML, BSON or Go values may use native Go numbers
+	return convertDocumentNode(doc), nil
+}
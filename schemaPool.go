@@ -89,8 +89,14 @@ func (p *schemaPool) GetDocument(reference gojsonreference.JsonReference) (*sche
 		return spd, nil
 	}
 
-	jsonReferenceLoader := p.jsonLoaderFactory.New(reference.String())
-	document, err := jsonReferenceLoader.LoadJSON()
+	var document interface{}
+	if source, ok := embeddedMetaSchema(refToUrl.String()); ok {
+		// meta-schemas are not downloaded
+		document, err = NewStringLoader(source).LoadJSON()
+	} else {
+		jsonReferenceLoader := p.jsonLoaderFactory.New(reference.String())
+		document, err = jsonReferenceLoader.LoadJSON()
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -27,6 +27,7 @@ package gojsonschema
 
 import (
 	"fmt"
+	"strings"
 )
 
 type schemaReferencePool struct {
@@ -41,8 +42,15 @@ func newSchemaReferencePool() *schemaReferencePool {
 	return p
 }
 
+// An empty fragment references the whole document, http://x/y.json# being http://x/y.json
+func normalizeReference(ref string) string {
+	return strings.TrimSuffix(ref, "#")
+}
+
 func (p *schemaReferencePool) Get(ref string) (r *subSchema, o bool) {
 
+	ref = normalizeReference(ref)
+
 	if internalLogEnabled {
 		internalLog(fmt.Sprintf("Schema Reference ( %s )", ref))
 	}
@@ -59,6 +67,8 @@ func (p *schemaReferencePool) Get(ref string) (r *subSchema, o bool) {
 
 func (p *schemaReferencePool) Add(ref string, sch *subSchema) {
 
+	ref = normalizeReference(ref)
+
 	if internalLogEnabled {
 		internalLog(fmt.Sprintf("Add Schema Reference %s to pool", ref))
 	}
@@ -27,9 +27,12 @@ package gojsonschema
 
 import (
 	"bytes"
+	"encoding/json"
 	"io"
 	"io/ioutil"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -181,3 +184,86 @@ func TestLoadersWithInvalidPattern(t *testing.T) {
 		assert.NotNil(t, err, "expected error loading invalid pattern: %T", l)
 	}
 }
+
+func TestBignumSuite(t *testing.T) {
+	testOptionalSuite(t, "bignum.json")
+}
+
+func TestExactDecimalKeywords(t *testing.T) {
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {
+			"price": {"multipleOf": 0.01, "maximum": 19.99},
+			"rate": {"multipleOf": 0.1, "exclusiveMinimum": 0.3}
+		}
+	}`))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	result, err := schema.Validate(NewStringLoader(`{"price": 19.99, "rate": 0.7}`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid(), "%v", result.Errors())
+
+	result, err = schema.Validate(NewStringLoader(`{"price": 19.991, "rate": 0.30000000000000000001}`))
+	assert.Nil(t, err)
+
+	details := map[string]interface{}{}
+	for _, e := range result.Errors() {
+		for k, v := range e.Details() {
+			if k != "field" && k != "context" {
+				details[e.Field()+"."+e.Type()+"."+k] = v
+			}
+		}
+	}
+	assert.Equal(t, map[string]interface{}{
+		"price.multiple_of.multiple": json.Number("0.01"),
+		"price.number_lte.max":       json.Number("19.99"),
+		"rate.multiple_of.multiple":  json.Number("0.1"),
+	}, details)
+}
+
+func TestHugeExponentKeywords(t *testing.T) {
+	// the exponents are not expanded, the bounds and multiples are still checked exactly
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {
+			"max": {"type": "number", "maximum": 10},
+			"min": {"type": "number", "exclusiveMinimum": 0},
+			"int": {"type": "integer", "multipleOf": 2},
+			"three": {"multipleOf": 3},
+			"huge": {"maximum": 1e1000002, "exclusiveMinimum": -1e2000000, "multipleOf": 1e1000000}
+		}
+	}`))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	result, err := schema.Validate(NewStringLoader(`{"max": 1e2000000, "min": -1e-2000000, "int": 1e2000000, "three": 1e1000001, "huge": 2e1000002}`))
+	assert.Nil(t, err)
+	types := map[string]string{}
+	for _, e := range result.Errors() {
+		types[e.Field()] = e.Type()
+	}
+	assert.Equal(t, map[string]string{"max": "number_lte", "min": "number_gt", "three": "multiple_of", "huge": "number_lte"}, types)
+
+	result, err = schema.Validate(NewStringLoader(`{"max": -1e2000000, "min": 1e-2000000, "three": 3e1000001, "huge": 5e1000001}`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid(), "%v", result.Errors())
+
+	result, err = schema.Validate(NewStringLoader(`{"huge": 1e999999}`))
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, "multiple_of", result.Errors()[0].Type())
+		assert.Equal(t, json.Number("1e1000000"), result.Errors()[0].Details()["multiple"])
+	}
+
+	// such numbers are not expanded, a document full of them is validated quickly
+	document := "[" + strings.TrimSuffix(strings.Repeat("1e999999,", 100), ",") + "]"
+	schema, err = NewSchema(NewStringLoader(`{"items": {"type": "number", "maximum": 1e1000000, "multipleOf": 3}}`))
+	if assert.Nil(t, err) {
+		start := time.Now()
+		result, err = schema.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		assert.Len(t, result.Errors(), 100)
+		assert.True(t, time.Since(start) < time.Second, "took %s", time.Since(start))
+	}
+}
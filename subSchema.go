@@ -28,8 +28,9 @@ package gojsonschema
 
 import (
 	"errors"
-	"math/big"
-	"regexp"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/xeipuuv/gojsonreference"
@@ -89,6 +90,12 @@ type subSchema struct {
 
 	// Types associated with the subSchema
 	types jsonSchemaType
+	// BSON types of the MongoDB dialect
+	bsonTypes bsonSchemaType
+	// null allowed by the nullable keyword of the OpenAPI dialect
+	nullable bool
+	// discriminator of oneOf and anyOf of the OpenAPI dialect
+	discriminator *discriminator
 
 	// Reference url
 	ref *gojsonreference.JsonReference
@@ -104,17 +111,19 @@ type subSchema struct {
 	propertiesChildren          []*subSchema
 
 	// validation : number / integer
-	multipleOf       *big.Float
-	maximum          *big.Float
+	multipleOf       *jsonNumberValue
+	maximum          *jsonNumberValue
 	exclusiveMaximum bool
-	minimum          *big.Float
+	minimum          *jsonNumberValue
 	exclusiveMinimum bool
 
 	// validation : string
 	minLength *int
 	maxLength *int
-	pattern   *regexp.Regexp
+	pattern   RegexMatcher
 	format    string
+	// formats of the dialect, checked before FormatCheckers
+	dialectFormatCheckers *FormatCheckerChain
 
 	// validation : object
 	minProperties *int
@@ -124,6 +133,7 @@ type subSchema struct {
 	dependencies         map[string]interface{}
 	additionalProperties interface{}
 	patternProperties    map[string]*subSchema
+	patternMatchers      map[string]RegexMatcher
 	propertyNames        *subSchema
 
 	// validation : array
@@ -135,8 +145,8 @@ type subSchema struct {
 	additionalItems interface{}
 
 	// validation : all
-	_const *string //const is a golang keyword
-	enum   []string
+	_const *jsonValue //const is a golang keyword
+	enum   jsonValueSet
 
 	// validation : subSchema
 	oneOf []*subSchema
@@ -146,45 +156,57 @@ type subSchema struct {
 	_if   *subSchema // if/else are golang keywords
 	_then *subSchema
 	_else *subSchema
+
+	// messages replacing the errors
+	errorMessage *errorMessage
+
+	// custom keywords
+	keywords []customKeyword
 }
 
 func (s *subSchema) AddConst(i interface{}) error {
 
-	is, err := marshalWithoutNumber(i)
+	v, err := newJsonValue(i)
+	if err != nil {
+		return err
+	}
+	v.text, err = jsonValueText(i)
 	if err != nil {
 		return err
 	}
-	s._const = is
+	s._const = v
 	return nil
 }
 
 func (s *subSchema) AddEnum(i interface{}) error {
 
-	is, err := marshalWithoutNumber(i)
+	v, err := newJsonValue(i)
+	if err != nil {
+		return err
+	}
+	v.text, err = jsonValueText(i)
 	if err != nil {
 		return err
 	}
 
-	if isStringInSlice(s.enum, *is) {
+	if s.enum.add(v) != nil {
 		return errors.New(formatErrorDescription(
 			Locale.KeyItemsMustBeUnique(),
 			ErrorDetails{"key": KEY_ENUM},
 		))
 	}
 
-	s.enum = append(s.enum, *is)
-
 	return nil
 }
 
 func (s *subSchema) ContainsEnum(i interface{}) (bool, error) {
 
-	is, err := marshalWithoutNumber(i)
+	v, err := newJsonValue(i)
 	if err != nil {
 		return false, err
 	}
 
-	return isStringInSlice(s.enum, *is), nil
+	return s.enum.find(v) != nil, nil
 }
 
 func (s *subSchema) AddOneOf(subSchema *subSchema) {
@@ -259,3 +281,120 @@ func (s *subSchema) PatternPropertiesString() string {
 	return "[" + strings.Join(patternPropertiesKeySlice, ",") + "]"
 
 }
+
+// walkSubSchemas calls f with the children of a subschema and their nodes in the schema document,
+// the pointer tokens leading to a child being given relative to the subschema
+func walkSubSchemas(s *subSchema, node interface{}, f func(child *subSchema, childNode interface{}, tokens ...string)) {
+
+	m, ok := node.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	member := func(keyword string, key string) (interface{}, bool) {
+		if members, ok := m[keyword].(map[string]interface{}); ok {
+			v, ok := members[key]
+			return v, ok
+		}
+		return nil, false
+	}
+	element := func(keyword string, i int) (interface{}, bool) {
+		if elements, ok := m[keyword].([]interface{}); ok && i < len(elements) {
+			return elements[i], true
+		}
+		return nil, false
+	}
+
+	for _, k := range sortedMapKeys(s.definitions) {
+		if v, ok := member(KEY_DEFINITIONS, k); ok {
+			f(s.definitions[k], v, KEY_DEFINITIONS, k)
+		}
+	}
+
+	for _, property := range s.propertiesChildren {
+		if v, ok := member(KEY_PROPERTIES, property.property); ok {
+			f(property, v, KEY_PROPERTIES, property.property)
+		}
+	}
+
+	for _, k := range sortedMapKeys(s.patternProperties) {
+		if v, ok := member(KEY_PATTERN_PROPERTIES, k); ok {
+			f(s.patternProperties[k], v, KEY_PATTERN_PROPERTIES, k)
+		}
+	}
+
+	for _, k := range sortedMapKeys(s.dependencies) {
+		if dependency, ok := s.dependencies[k].(*subSchema); ok {
+			if v, ok := member(KEY_DEPENDENCIES, k); ok {
+				f(dependency, v, KEY_DEPENDENCIES, k)
+			}
+		}
+	}
+
+	if s.itemsChildrenIsSingleSchema {
+		if v, ok := m[KEY_ITEMS]; ok && len(s.itemsChildren) == 1 {
+			f(s.itemsChildren[0], v, KEY_ITEMS)
+		}
+	} else {
+		for i, item := range s.itemsChildren {
+			if v, ok := element(KEY_ITEMS, i); ok {
+				f(item, v, KEY_ITEMS, strconv.Itoa(i))
+			}
+		}
+	}
+
+	singles := []struct {
+		keyword string
+		schema  interface{}
+	}{
+		{KEY_ADDITIONAL_PROPERTIES, s.additionalProperties},
+		{KEY_ADDITIONAL_ITEMS, s.additionalItems},
+		{KEY_PROPERTY_NAMES, s.propertyNames},
+		{KEY_CONTAINS, s.contains},
+		{KEY_NOT, s.not},
+		{KEY_IF, s._if},
+		{KEY_THEN, s._then},
+		{KEY_ELSE, s._else},
+	}
+	for _, single := range singles {
+		if child, ok := single.schema.(*subSchema); ok && child != nil {
+			if v, ok := m[single.keyword]; ok {
+				f(child, v, single.keyword)
+			}
+		}
+	}
+
+	branches := []struct {
+		keyword string
+		schemas []*subSchema
+	}{
+		{KEY_ALL_OF, s.allOf},
+		{KEY_ANY_OF, s.anyOf},
+		{KEY_ONE_OF, s.oneOf},
+	}
+	for _, b := range branches {
+		for i, branch := range b.schemas {
+			if v, ok := element(b.keyword, i); ok {
+				f(branch, v, b.keyword, strconv.Itoa(i))
+			}
+		}
+	}
+}
+
+// formatCheckers returns the chain checking the format of the subschema
+func (s *subSchema) formatCheckers() *FormatCheckerChain {
+	if s.dialectFormatCheckers != nil && s.dialectFormatCheckers.Has(s.format) {
+		return s.dialectFormatCheckers
+	}
+	return &FormatCheckers
+}
+
+// sortedMapKeys returns the keys of a map with string keys, sorted
+func sortedMapKeys(m interface{}) []string {
+	var keys []string
+	for _, k := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -31,13 +31,17 @@ import (
 	"math"
 	"math/big"
 	"reflect"
+	"strconv"
+	"strings"
 )
 
 func isKind(what interface{}, kinds ...reflect.Kind) bool {
 	target := what
 	if isJsonNumber(what) {
 		// JSON Numbers are strings!
-		target = *mustBeNumber(what)
+		if numberValue := mustBeNumber(what); numberValue != nil {
+			target = *numberValue
+		}
 	}
 	targetKind := reflect.ValueOf(target).Kind()
 	for _, kind := range kinds {
@@ -106,14 +110,266 @@ func isJsonNumber(what interface{}) bool {
 	return false
 }
 
+// toJsonNumber converts the Go numeric types produced by decoders other than
+// encoding/json ( YAML, BSON, Go literals... ) to a json.Number
+func toJsonNumber(what interface{}) (json.Number, bool) {
+
+	switch n := what.(type) {
+	case json.Number:
+		return n, true
+	case Decimal128:
+		if n.IsNaN() || n.IsInf() {
+			return "", false
+		}
+		return json.Number(n.String()), true
+	case int:
+		return json.Number(strconv.FormatInt(int64(n), 10)), true
+	case int8:
+		return json.Number(strconv.FormatInt(int64(n), 10)), true
+	case int16:
+		return json.Number(strconv.FormatInt(int64(n), 10)), true
+	case int32:
+		return json.Number(strconv.FormatInt(int64(n), 10)), true
+	case int64:
+		return json.Number(strconv.FormatInt(n, 10)), true
+	case uint:
+		return json.Number(strconv.FormatUint(uint64(n), 10)), true
+	case uint8:
+		return json.Number(strconv.FormatUint(uint64(n), 10)), true
+	case uint16:
+		return json.Number(strconv.FormatUint(uint64(n), 10)), true
+	case uint32:
+		return json.Number(strconv.FormatUint(uint64(n), 10)), true
+	case uint64:
+		return json.Number(strconv.FormatUint(n, 10)), true
+	case float32:
+		if math.IsNaN(float64(n)) || math.IsInf(float64(n), 0) {
+			return "", false
+		}
+		return json.Number(strconv.FormatFloat(float64(n), 'g', -1, 32)), true
+	case float64:
+		if math.IsNaN(n) || math.IsInf(n, 0) {
+			return "", false
+		}
+		return json.Number(strconv.FormatFloat(n, 'g', -1, 64)), true
+	}
+
+	return "", false
+}
+
+// nonFiniteFloat returns the NaN and infinities of the floats of BSON doubles and Go values,
+// which have no JSON number representation
+func nonFiniteFloat(what interface{}) (float64, bool) {
+
+	var f float64
+	switch n := what.(type) {
+	case float32:
+		f = float64(n)
+	case float64:
+		f = n
+	default:
+		return 0, false
+	}
+
+	return f, math.IsNaN(f) || math.IsInf(f, 0)
+}
+
 func checkJsonInteger(what interface{}) (isInt bool) {
 
 	jsonNumber := what.(json.Number)
 
-	bigFloat, isValidNumber := new(big.Float).SetString(string(jsonNumber))
+	numberValue, isValidNumber := parseJsonNumber(jsonNumber)
+
+	return isValidNumber && numberValue.IsInt()
+
+}
+
+// jsonNumberMaxExpansion is the largest exponent expanded to the exact value of a number as a
+// big.Rat, 1e1000 taking about 3300 bits. A document can use larger exponents to make the
+// expansion take seconds, so such numbers are compared by their order of magnitude instead.
+const jsonNumberMaxExpansion = 1000
+
+// jsonNumberValue is the exact value of a JSON number, coefficient * 10^exponent, the coefficient
+// having no trailing zeros. Numbers are compared and divided in this form, so exponents like
+// 1e1000000 are never expanded.
+type jsonNumberValue struct {
+	negative    bool
+	coefficient *big.Int
+	exponent    *big.Int
+	// number of digits of the coefficient, 0 for zero
+	digits int
+}
+
+func parseJsonNumber(number json.Number) (*jsonNumberValue, bool) {
+
+	s := string(number)
+	n := &jsonNumberValue{}
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		n.negative = s[0] == '-'
+		s = s[1:]
+	}
+
+	mantissa, exponent := s, "0"
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		mantissa, exponent = s[:i], s[i+1:]
+	}
+	integer, fraction := mantissa, ""
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		integer, fraction = mantissa[:i], mantissa[i+1:]
+	}
+
+	digits := integer + fraction
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return nil, false
+	}
+	exponentValue, ok := new(big.Int).SetString(exponent, 10)
+	if !ok {
+		return nil, false
+	}
+
+	significant := strings.TrimRight(strings.TrimLeft(digits, "0"), "0")
+	if significant == "" {
+		return &jsonNumberValue{coefficient: new(big.Int), exponent: new(big.Int)}, true
+	}
+	trailingZeros := len(digits) - len(strings.TrimRight(digits, "0"))
+
+	n.coefficient, _ = new(big.Int).SetString(significant, 10)
+	n.exponent = exponentValue.Add(exponentValue, big.NewInt(int64(trailingZeros-len(fraction))))
+	n.digits = len(significant)
+	return n, true
+}
+
+func pow10(exponent int64) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(exponent), nil)
+}
+
+func (n *jsonNumberValue) Sign() int {
+	switch {
+	case n.coefficient.Sign() == 0:
+		return 0
+	case n.negative:
+		return -1
+	}
+	return 1
+}
+
+// Cmp compares two numbers like big.Rat.Cmp
+func (n *jsonNumberValue) Cmp(o *jsonNumberValue) int {
+
+	if nSign, oSign := n.Sign(), o.Sign(); nSign != oSign || nSign == 0 {
+		switch {
+		case nSign < oSign:
+			return -1
+		case nSign > oSign:
+			return 1
+		}
+		return 0
+	}
+
+	cmp := n.cmpAbs(o)
+	if n.negative {
+		return -cmp
+	}
+	return cmp
+}
+
+// cmpAbs compares the absolute values of two numbers that are not zero
+func (n *jsonNumberValue) cmpAbs(o *jsonNumberValue) int {
+
+	// a number with more digits before the decimal point is larger
+	nOrder := new(big.Int).Add(n.exponent, big.NewInt(int64(n.digits)))
+	oOrder := new(big.Int).Add(o.exponent, big.NewInt(int64(o.digits)))
+	if cmp := nOrder.Cmp(oOrder); cmp != 0 {
+		return cmp
+	}
+
+	// the exponents differ by the difference of the number of digits, the coefficients are aligned
+	a, b := n.coefficient, o.coefficient
+	if shift := int64(o.digits - n.digits); shift > 0 {
+		a = new(big.Int).Mul(a, pow10(shift))
+	} else if shift < 0 {
+		b = new(big.Int).Mul(b, pow10(-shift))
+	}
+	return a.Cmp(b)
+}
+
+func (n *jsonNumberValue) IsInt() bool {
+	return n.coefficient.Sign() == 0 || n.exponent.Sign() >= 0
+}
 
-	return isValidNumber && bigFloat.IsInt()
+// IsMultipleOf reports whether the number divided by m, which is not zero, is an integer
+func (n *jsonNumberValue) IsMultipleOf(m *jsonNumberValue) bool {
 
+	if n.coefficient.Sign() == 0 {
+		return true
+	}
+
+	// n / m = a / b * 10^d, a having no factor 10, so a / b * 10^d is not an integer when d < 0
+	d := new(big.Int).Sub(n.exponent, m.exponent)
+	if d.Sign() < 0 {
+		return false
+	}
+
+	// b / gcd(a, b) must divide 10^d, being made of max(twos, fives) <= d factors 2 and 5
+	a, b := n.coefficient, m.coefficient
+	rest := new(big.Int).Quo(b, new(big.Int).GCD(nil, nil, a, b))
+	twos := int64(rest.TrailingZeroBits())
+	rest.Rsh(rest, uint(twos))
+	fives := int64(0)
+	five, remainder := big.NewInt(5), new(big.Int)
+	for {
+		quotient, r := new(big.Int).QuoRem(rest, five, remainder)
+		if r.Sign() != 0 {
+			break
+		}
+		rest = quotient
+		fives++
+	}
+	if rest.Cmp(big.NewInt(1)) != 0 {
+		return false
+	}
+
+	if fives > twos {
+		twos = fives
+	}
+	return d.Cmp(big.NewInt(twos)) >= 0
+}
+
+// Rat returns the exact value of the number, false when its exponent is too large to expand
+func (n *jsonNumberValue) Rat() (*big.Rat, bool) {
+
+	if !n.exponent.IsInt64() {
+		return nil, false
+	}
+	exponent := n.exponent.Int64()
+	if exponent > jsonNumberMaxExpansion || exponent < -jsonNumberMaxExpansion {
+		return nil, false
+	}
+
+	r := new(big.Rat).SetInt(n.coefficient)
+	if exponent >= 0 {
+		r.Mul(r, new(big.Rat).SetInt(pow10(exponent)))
+	} else {
+		r.Quo(r, new(big.Rat).SetInt(pow10(-exponent)))
+	}
+	if n.negative {
+		r.Neg(r)
+	}
+	return r, true
+}
+
+// Number formats the number in decimal notation, or with an exponent when it is too large to expand
+func (n *jsonNumberValue) Number() json.Number {
+
+	if r, ok := n.Rat(); ok {
+		return formatRat(r)
+	}
+
+	sign := ""
+	if n.negative {
+		sign = "-"
+	}
+	return json.Number(sign + n.coefficient.String() + "e" + n.exponent.String())
 }
 
 // same as ECMA Number.MAX_SAFE_INTEGER and Number.MIN_SAFE_INTEGER
@@ -158,13 +414,14 @@ func mustBeInteger(what interface{}) *int {
 	return nil
 }
 
-func mustBeNumber(what interface{}) *big.Float {
+// mustBeNumber returns the exact value of a JSON number, so decimals like 0.01 are not rounded
+func mustBeNumber(what interface{}) *jsonNumberValue {
 
 	if isJsonNumber(what) {
 		number := what.(json.Number)
-		float64Value, success := new(big.Float).SetString(string(number))
+		numberValue, success := parseJsonNumber(number)
 		if success {
-			return float64Value
+			return numberValue
 		} else {
 			return nil
 		}
@@ -175,6 +432,34 @@ func mustBeNumber(what interface{}) *big.Float {
 
 }
 
+// formatRat formats an exact decimal number back to its decimal notation
+func formatRat(r *big.Rat) json.Number {
+
+	if r.IsInt() {
+		return json.Number(r.Num().String())
+	}
+
+	// the denominator of a decimal is of the form 2^a * 5^b, max(a, b) digits are enough
+	denominator := new(big.Int).Set(r.Denom())
+	twos := int(denominator.TrailingZeroBits())
+	denominator.Rsh(denominator, uint(twos))
+	fives := 0
+	five := big.NewInt(5)
+	for quotient, remainder := new(big.Int), new(big.Int); ; fives++ {
+		quotient.QuoRem(denominator, five, remainder)
+		if remainder.Sign() != 0 {
+			break
+		}
+		denominator.Set(quotient)
+	}
+
+	digits := twos
+	if fives > digits {
+		digits = fives
+	}
+	return json.Number(r.FloatString(digits))
+}
+
 // formats a number so that it is displayed as the smallest string possible
 func resultErrorFormatJsonNumber(n json.Number) string {
 
@@ -222,5 +507,30 @@ func convertDocumentNode(val interface{}) interface{} {
 
 	}
 
+	if number, ok := toJsonNumber(val); ok {
+		return number
+	}
+
+	// other Go slices and maps with string keys, i.e. []string or map[string]int
+	rValue := reflect.ValueOf(val)
+
+	switch rValue.Kind() {
+	case reflect.Slice:
+		res := []interface{}{}
+		for i := 0; i < rValue.Len(); i++ {
+			res = append(res, convertDocumentNode(rValue.Index(i).Interface()))
+		}
+		return res
+
+	case reflect.Map:
+		if rValue.Type().Key().Kind() == reflect.String {
+			res := map[string]interface{}{}
+			for _, k := range rValue.MapKeys() {
+				res[k.String()] = convertDocumentNode(rValue.MapIndex(k).Interface())
+			}
+			return res
+		}
+	}
+
 	return val
 }
@@ -75,6 +75,8 @@ func TestCheckJsonNumber(t *testing.T) {
 		{true, "9223372036854775807"},
 		{true, "-9223372036854775808"},
 		{true, "1.0e+2"},
+		{true, "12345678910111213141516171819202122232425262728293031"},
+		{false, "1.0000000000000000000001"},
 		{true, "1.0e+10"},
 		{true, "-1.0e+2"},
 		{true, "-1.0e+10"},
@@ -89,3 +91,72 @@ func TestCheckJsonNumber(t *testing.T) {
 	}
 
 }
+
+func TestFormatRat(t *testing.T) {
+	for _, number := range []json.Number{"5", "-5", "0", "0.01", "19.99", "0.125", "-0.5", "123456789012345678901234567890.000000001"} {
+		assert.Equal(t, number, mustBeNumber(number).Number())
+	}
+	assert.Equal(t, json.Number("1000"), mustBeNumber(json.Number("1e3")).Number())
+	assert.Equal(t, json.Number("0.0012"), mustBeNumber(json.Number("1.20e-3")).Number())
+	assert.Equal(t, json.Number("0"), mustBeNumber(json.Number("-0.000e5")).Number())
+
+	// huge exponents are not expanded
+	assert.Equal(t, json.Number("1e1000002"), mustBeNumber(json.Number("1e1000002")).Number())
+	assert.Equal(t, json.Number("-25e-2000"), mustBeNumber(json.Number("-2.50e-1999")).Number())
+}
+
+func TestJsonNumberValue(t *testing.T) {
+	cmpCases := []struct {
+		a, b json.Number
+		cmp  int
+	}{
+		{"1", "1.0", 0},
+		{"0", "-0", 0},
+		{"-1", "0", -1},
+		{"0.1", "0.09999", 1},
+		{"1e1000001", "1e1000000", 1},
+		{"2e1000000", "19e999999", 1},
+		{"1e1000000", "10e999999", 0},
+		{"-1e1000000", "1", -1},
+		{"-1e1000001", "-1e1000000", -1},
+		{"1e-1000000", "0", 1},
+		{"1e-1000000", "1e-999999", -1},
+		{"12345e99999999999999999999", "1.2345e100000000000000000003", 0},
+	}
+	for _, c := range cmpCases {
+		a, _ := parseJsonNumber(c.a)
+		b, _ := parseJsonNumber(c.b)
+		assert.Equal(t, c.cmp, a.Cmp(b), "%s %s", c.a, c.b)
+		assert.Equal(t, -c.cmp, b.Cmp(a), "%s %s", c.b, c.a)
+	}
+
+	multipleCases := []struct {
+		n, m     json.Number
+		multiple bool
+	}{
+		{"10", "2", true},
+		{"0", "7", true},
+		{"7", "2", false},
+		{"0.3", "0.1", true},
+		{"0.35", "0.1", false},
+		{"19.99", "0.01", true},
+		{"1e1000001", "3", false},
+		{"3e1000001", "3", true},
+		{"1e1000001", "2", true},
+		{"1e1000001", "0.125", true},
+		{"1e1000001", "1e1000002", false},
+		{"1e-1000000", "1e-1000000", true},
+		{"1e-1000000", "2", false},
+		{"6e1000000", "1.5", true},
+	}
+	for _, c := range multipleCases {
+		n, _ := parseJsonNumber(c.n)
+		m, _ := parseJsonNumber(c.m)
+		assert.Equal(t, c.multiple, n.IsMultipleOf(m), "%s %s", c.n, c.m)
+	}
+
+	for _, invalid := range []json.Number{"", "-", "1e", "e5", "1.2.3", "0x10", "1/3", "Infinity"} {
+		_, ok := parseJsonNumber(invalid)
+		assert.False(t, ok, string(invalid))
+	}
+}
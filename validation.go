@@ -27,9 +27,10 @@ package gojsonschema
 
 import (
 	"encoding/json"
+	"errors"
+	"math"
 	"math/big"
 	"reflect"
-	"regexp"
 	"strconv"
 	"strings"
 	"unicode/utf8"
@@ -64,6 +65,78 @@ func (v *Schema) Validate(l JSONLoader) (*Result, error) {
 	return v.validateDocument(root), nil
 }
 
+// ValidateWithLocale validates a document like Validate, its errors being described in a locale
+// instead of the global Locale
+func (v *Schema) ValidateWithLocale(l JSONLoader, locale LocaleMessages) (*Result, error) {
+	result, err := v.Validate(l)
+	if err != nil {
+		return nil, err
+	}
+	return result.Localize(locale), nil
+}
+
+// ValidateOptions are the options of ValidateWithOptions
+type ValidateOptions struct {
+	// Coerce converts the values of the document to the type of their schema before validating
+	// them, like the strings of forms, query strings and environment variables : see Coercion
+	Coerce bool
+	// InPlace also coerces the values of the document of a NewGoLoader, made of
+	// map[string]interface{} and []interface{}, instead of only the copy of Result.Document.
+	// A document that is itself coerced, like the string "42" of {"type": "integer"}, cannot be
+	// replaced : its coerced value is only the one of Result.Document
+	InPlace bool
+	// RemoveAdditional removes the additional properties of the objects from the validated document
+	// instead of rejecting them, Result.Document being the pruned copy. The document of the loader
+	// is left untouched
+	RemoveAdditional RemoveAdditional
+	// Locale describes the errors, the global Locale when nil
+	Locale LocaleMessages
+}
+
+// ValidateWithOptions validates a document like Validate, the result holding the validated document
+func (v *Schema) ValidateWithOptions(l JSONLoader, options ValidateOptions) (*Result, error) {
+
+	root, err := l.LoadJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	locale := options.Locale
+	if locale == nil {
+		locale = Locale
+	}
+
+	var coercions []Coercion
+	if options.Coerce {
+		root, coercions = coerceValue(v.rootSchema, root, "")
+		if options.InPlace {
+			goLoader, ok := l.(*jsonGoLoader)
+			if !ok {
+				return nil, errors.New(formatErrorDescription(locale.CoercionNoGoDocument(), ErrorDetails{}))
+			}
+			if err := applyCoercions(goLoader.JsonSource(), coercions, locale); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var removed []string
+	if options.RemoveAdditional != RemoveAdditionalNone {
+		p := &pruner{mode: options.RemoveAdditional}
+		root, removed = p.pruneValue([]*subSchema{v.rootSchema}, root, "")
+	}
+
+	result := v.validateDocument(root)
+	result.document = root
+	result.coercions = coercions
+	result.removed = removed
+
+	if options.Locale != nil {
+		result = result.Localize(options.Locale)
+	}
+	return result, nil
+}
+
 func (v *Schema) validateDocument(root interface{}) *Result {
 	// begin validation
 
@@ -94,9 +167,35 @@ func (v *subSchema) validateRecursive(currentSubSchema *subSchema, currentNode i
 		return
 	}
 
+	// Replace the errors by the messages of errorMessage once the subschema is validated
+	if currentSubSchema.errorMessage != nil {
+		start := len(result.errors)
+		defer func() {
+			errs := currentSubSchema.errorMessage.apply(result.errors[start:], context, currentNode)
+			result.errors = append(result.errors[:start], errs...)
+		}()
+	}
+
+	// Check the BSON type before the value is handled as its JSON equivalent
+	if currentSubSchema.bsonTypes.IsTyped() {
+		givenType := bsonValueType(currentNode)
+		if !currentSubSchema.bsonTypes.Matches(givenType) {
+			result.addInternalError(
+				new(InvalidBSONTypeError),
+				context,
+				currentNode,
+				ErrorDetails{
+					"expected": currentSubSchema.bsonTypes.String(),
+					"given":    givenType,
+				},
+			)
+			return
+		}
+	}
+
 	// Check for null value
 	if currentNode == nil {
-		if currentSubSchema.types.IsTyped() && !currentSubSchema.types.Contains(TYPE_NULL) {
+		if currentSubSchema.types.IsTyped() && !currentSubSchema.types.Contains(TYPE_NULL) && !currentSubSchema.nullable {
 			result.addInternalError(
 				new(InvalidTypeError),
 				context,
@@ -114,6 +213,11 @@ func (v *subSchema) validateRecursive(currentSubSchema *subSchema, currentNode i
 
 	} else { // Not a null value
 
+		// Numbers not decoded by encoding/json ( YAML, BSON, Go types... )
+		if number, ok := toJsonNumber(currentNode); ok {
+			currentNode = number
+		}
+
 		if isJsonNumber(currentNode) {
 
 			value := currentNode.(json.Number)
@@ -146,6 +250,44 @@ func (v *subSchema) validateRecursive(currentSubSchema *subSchema, currentNode i
 			v.validateCommon(currentSubSchema, value, result, context)
 			v.validateString(currentSubSchema, value, result, context)
 
+		} else if value, ok := nonFiniteFloat(currentNode); ok {
+
+			if currentSubSchema.types.IsTyped() && !currentSubSchema.types.Contains(TYPE_NUMBER) {
+				result.addInternalError(
+					new(InvalidTypeError),
+					context,
+					currentNode,
+					ErrorDetails{
+						"expected": currentSubSchema.types.String(),
+						"given":    TYPE_NUMBER,
+					},
+				)
+				return
+			}
+
+			currentSubSchema.validateSchema(currentSubSchema, currentNode, result, context)
+			v.validateNonFiniteNumber(currentSubSchema, value, result, context)
+			v.validateCommon(currentSubSchema, currentNode, result, context)
+
+		} else if givenType, ok := bsonScalarType(currentNode); ok {
+
+			// BSON values without JSON equivalent ( ObjectId, dates... ) never match a JSON type
+			if currentSubSchema.types.IsTyped() {
+				result.addInternalError(
+					new(InvalidTypeError),
+					context,
+					currentNode,
+					ErrorDetails{
+						"expected": currentSubSchema.types.String(),
+						"given":    givenType,
+					},
+				)
+				return
+			}
+
+			currentSubSchema.validateSchema(currentSubSchema, currentNode, result, context)
+			v.validateCommon(currentSubSchema, currentNode, result, context)
+
 		} else {
 
 			rValue := reflect.ValueOf(currentNode)
@@ -170,7 +312,10 @@ func (v *subSchema) validateRecursive(currentSubSchema *subSchema, currentNode i
 					return
 				}
 
-				castCurrentNode := currentNode.([]interface{})
+				castCurrentNode, ok := currentNode.([]interface{})
+				if !ok {
+					castCurrentNode = convertDocumentNode(currentNode).([]interface{})
+				}
 
 				currentSubSchema.validateSchema(currentSubSchema, castCurrentNode, result, context)
 
@@ -274,15 +419,20 @@ func (v *subSchema) validateSchema(currentSubSchema *subSchema, currentNode inte
 		internalLog(" %v", currentNode)
 	}
 
-	if len(currentSubSchema.anyOf) > 0 {
+	// the discriminator of OpenAPI selects the branch of anyOf and oneOf
+	discriminated := currentSubSchema.discriminator != nil && v.validateDiscriminator(currentSubSchema, currentNode, result, context)
+
+	if len(currentSubSchema.anyOf) > 0 && !discriminated {
 
 		validatedAnyOf := false
 		var bestValidationResult *Result
+		var branches []ErrorBranch
 
-		for _, anyOfSchema := range currentSubSchema.anyOf {
+		for i, anyOfSchema := range currentSubSchema.anyOf {
 			if !validatedAnyOf {
 				validationResult := anyOfSchema.subValidateWithContext(currentNode, context)
 				validatedAnyOf = validationResult.Valid()
+				branches = append(branches, validationResult.branch(i))
 
 				if !validatedAnyOf && (bestValidationResult == nil || validationResult.score > bestValidationResult.score) {
 					bestValidationResult = validationResult
@@ -291,7 +441,9 @@ func (v *subSchema) validateSchema(currentSubSchema *subSchema, currentNode inte
 		}
 		if !validatedAnyOf {
 
-			result.addInternalError(new(NumberAnyOfError), context, currentNode, ErrorDetails{})
+			err := new(NumberAnyOfError)
+			result.addInternalError(err, context, currentNode, ErrorDetails{})
+			err.SetBranches(branches)
 
 			if bestValidationResult != nil {
 				// add error messages of closest matching subSchema as
@@ -301,13 +453,19 @@ func (v *subSchema) validateSchema(currentSubSchema *subSchema, currentNode inte
 		}
 	}
 
-	if len(currentSubSchema.oneOf) > 0 {
+	if len(currentSubSchema.oneOf) > 0 && !discriminated {
 
 		nbValidated := 0
 		var bestValidationResult *Result
+		var branches []ErrorBranch
+		titles := make([]string, len(currentSubSchema.oneOf))
 
-		for _, oneOfSchema := range currentSubSchema.oneOf {
+		for i, oneOfSchema := range currentSubSchema.oneOf {
+			if oneOfSchema.title != nil {
+				titles[i] = *oneOfSchema.title
+			}
 			validationResult := oneOfSchema.subValidateWithContext(currentNode, context)
+			branches = append(branches, validationResult.branch(i))
 			if validationResult.Valid() {
 				nbValidated++
 			} else if nbValidated == 0 && (bestValidationResult == nil || validationResult.score > bestValidationResult.score) {
@@ -317,7 +475,9 @@ func (v *subSchema) validateSchema(currentSubSchema *subSchema, currentNode inte
 
 		if nbValidated != 1 {
 
-			result.addInternalError(new(NumberOneOfError), context, currentNode, ErrorDetails{})
+			err := &NumberOneOfError{titles: titles}
+			result.addInternalError(err, context, currentNode, oneOfDetails(branches, titles))
+			err.SetBranches(branches)
 
 			if nbValidated == 0 {
 				// add error messages of closest matching subSchema as
@@ -330,24 +490,30 @@ func (v *subSchema) validateSchema(currentSubSchema *subSchema, currentNode inte
 
 	if len(currentSubSchema.allOf) > 0 {
 		nbValidated := 0
+		var branches []ErrorBranch
 
-		for _, allOfSchema := range currentSubSchema.allOf {
+		for i, allOfSchema := range currentSubSchema.allOf {
 			validationResult := allOfSchema.subValidateWithContext(currentNode, context)
 			if validationResult.Valid() {
 				nbValidated++
 			}
+			branches = append(branches, validationResult.branch(i))
 			result.mergeErrors(validationResult)
 		}
 
 		if nbValidated != len(currentSubSchema.allOf) {
-			result.addInternalError(new(NumberAllOfError), context, currentNode, ErrorDetails{})
+			err := new(NumberAllOfError)
+			result.addInternalError(err, context, currentNode, ErrorDetails{})
+			err.SetBranches(branches)
 		}
 	}
 
 	if currentSubSchema.not != nil {
 		validationResult := currentSubSchema.not.subValidateWithContext(currentNode, context)
 		if validationResult.Valid() {
-			result.addInternalError(new(NumberNotError), context, currentNode, ErrorDetails{})
+			err := new(NumberNotError)
+			result.addInternalError(err, context, currentNode, ErrorDetails{})
+			err.SetBranches([]ErrorBranch{validationResult.branch(0)})
 		}
 	}
 
@@ -382,14 +548,18 @@ func (v *subSchema) validateSchema(currentSubSchema *subSchema, currentNode inte
 		if currentSubSchema._then != nil && validationResultIf.Valid() {
 			validationResultThen := currentSubSchema._then.subValidateWithContext(currentNode, context)
 			if !validationResultThen.Valid() {
-				result.addInternalError(new(ConditionThenError), context, currentNode, ErrorDetails{})
+				err := new(ConditionThenError)
+				result.addInternalError(err, context, currentNode, ErrorDetails{})
+				err.SetBranches([]ErrorBranch{validationResultThen.branch(0)})
 				result.mergeErrors(validationResultThen)
 			}
 		}
 		if currentSubSchema._else != nil && !validationResultIf.Valid() {
 			validationResultElse := currentSubSchema._else.subValidateWithContext(currentNode, context)
 			if !validationResultElse.Valid() {
-				result.addInternalError(new(ConditionElseError), context, currentNode, ErrorDetails{})
+				err := new(ConditionElseError)
+				result.addInternalError(err, context, currentNode, ErrorDetails{})
+				err.SetBranches([]ErrorBranch{validationResultElse.branch(0)})
 				result.mergeErrors(validationResultElse)
 			}
 		}
@@ -398,6 +568,36 @@ func (v *subSchema) validateSchema(currentSubSchema *subSchema, currentNode inte
 	result.incrementScore()
 }
 
+// oneOfDetails returns the details of a oneOf error : the schemas validating when several do,
+// or the errors of each schema when none does
+func oneOfDetails(branches []ErrorBranch, titles []string) ErrorDetails {
+
+	var matched, failures []string
+	var indices []int
+
+	for _, branch := range branches {
+		label := strconv.Itoa(branch.Index)
+		if titles[branch.Index] != "" {
+			label += " " + strconv.Quote(titles[branch.Index])
+		}
+		if branch.Valid {
+			matched = append(matched, label)
+			indices = append(indices, branch.Index)
+			continue
+		}
+		var descriptions []string
+		for _, err := range branch.Errors {
+			descriptions = append(descriptions, err.String())
+		}
+		failures = append(failures, label+" ("+strings.Join(descriptions, ", ")+")")
+	}
+
+	if len(matched) > 0 {
+		return ErrorDetails{"matched": strings.Join(matched, ", "), "indices": indices}
+	}
+	return ErrorDetails{"failures": strings.Join(failures, "; ")}
+}
+
 func (v *subSchema) validateCommon(currentSubSchema *subSchema, value interface{}, result *Result, context *JsonContext) {
 
 	if internalLogEnabled {
@@ -407,23 +607,22 @@ func (v *subSchema) validateCommon(currentSubSchema *subSchema, value interface{
 
 	// const:
 	if currentSubSchema._const != nil {
-		vString, err := marshalWithoutNumber(value)
+		v, err := newJsonValue(value)
 		if err != nil {
 			result.addInternalError(new(InternalError), context, value, ErrorDetails{"error": err})
-		}
-		if *vString != *currentSubSchema._const {
+		} else if !currentSubSchema._const.equals(v) {
 			result.addInternalError(new(ConstError),
 				context,
 				value,
 				ErrorDetails{
-					"allowed": *currentSubSchema._const,
+					"allowed": currentSubSchema._const.text,
 				},
 			)
 		}
 	}
 
 	// enum:
-	if len(currentSubSchema.enum) > 0 {
+	if currentSubSchema.enum.len() > 0 {
 		has, err := currentSubSchema.ContainsEnum(value)
 		if err != nil {
 			result.addInternalError(new(InternalError), context, value, ErrorDetails{"error": err})
@@ -434,12 +633,14 @@ func (v *subSchema) validateCommon(currentSubSchema *subSchema, value interface{
 				context,
 				value,
 				ErrorDetails{
-					"allowed": strings.Join(currentSubSchema.enum, ", "),
+					"allowed": currentSubSchema.enum.String(),
 				},
 			)
 		}
 	}
 
+	v.validateKeywords(currentSubSchema, value, result, context)
+
 	result.incrementScore()
 }
 
@@ -516,21 +717,22 @@ func (v *subSchema) validateArray(currentSubSchema *subSchema, value []interface
 
 	// uniqueItems:
 	if currentSubSchema.uniqueItems {
-		var stringifiedItems []string
-		for _, v := range value {
-			vString, err := marshalWithoutNumber(v)
+		var items jsonValueSet
+		for j, v := range value {
+			item, err := newJsonValue(v)
 			if err != nil {
 				result.addInternalError(new(InternalError), context, value, ErrorDetails{"err": err})
+				continue
 			}
-			if isStringInSlice(stringifiedItems, *vString) {
+			item.index = j
+			if duplicate := items.add(item); duplicate != nil {
 				result.addInternalError(
 					new(ItemsMustBeUniqueError),
 					context,
 					value,
-					ErrorDetails{"type": TYPE_ARRAY},
+					ErrorDetails{"type": TYPE_ARRAY, "i": duplicate.index, "j": j},
 				)
 			}
-			stringifiedItems = append(stringifiedItems, *vString)
 		}
 	}
 
@@ -742,7 +944,18 @@ func (v *subSchema) validatePatternProperty(currentSubSchema *subSchema, key str
 	validatedkey := false
 
 	for pk, pv := range currentSubSchema.patternProperties {
-		if matches, _ := regexp.MatchString(pk, key); matches {
+		matched, aborted := regexMatch(currentSubSchema.patternMatchers[pk], key)
+		if aborted {
+			// the property is reported once, not as an additional property too
+			has, validatedkey = true, true
+			result.addInternalError(
+				new(RegexStepLimitError),
+				NewJsonContext(key, context),
+				key,
+				ErrorDetails{"pattern": pk},
+			)
+		}
+		if matched {
 			has = true
 			subContext := NewJsonContext(key, context)
 			validationResult := pv.subValidateWithContext(value, subContext)
@@ -805,7 +1018,14 @@ func (v *subSchema) validateString(currentSubSchema *subSchema, value interface{
 
 	// pattern:
 	if currentSubSchema.pattern != nil {
-		if !currentSubSchema.pattern.MatchString(stringValue) {
+		if matched, aborted := regexMatch(currentSubSchema.pattern, stringValue); aborted {
+			result.addInternalError(
+				new(RegexStepLimitError),
+				context,
+				value,
+				ErrorDetails{"pattern": currentSubSchema.pattern},
+			)
+		} else if !matched {
 			result.addInternalError(
 				new(DoesNotMatchPatternError),
 				context,
@@ -818,7 +1038,7 @@ func (v *subSchema) validateString(currentSubSchema *subSchema, value interface{
 
 	// format
 	if currentSubSchema.format != "" {
-		if !FormatCheckers.IsFormat(currentSubSchema.format, stringValue) {
+		if !currentSubSchema.formatCheckers().IsFormat(currentSubSchema.format, stringValue) {
 			result.addInternalError(
 				new(DoesNotMatchFormatError),
 				context,
@@ -831,10 +1051,50 @@ func (v *subSchema) validateString(currentSubSchema *subSchema, value interface{
 	result.incrementScore()
 }
 
+// validateNonFiniteNumber validates the NaN and infinities of BSON doubles, NaN failing all the
+// numeric keywords and infinities multipleOf and the bound on their side
+func (v *subSchema) validateNonFiniteNumber(currentSubSchema *subSchema, value float64, result *Result, context *JsonContext) {
+
+	if internalLogEnabled {
+		internalLog("validateNonFiniteNumber %s", context.String())
+		internalLog(" %v", value)
+	}
+
+	formatted := strconv.FormatFloat(value, 'g', -1, 64)
+
+	if currentSubSchema.multipleOf != nil {
+		result.addInternalError(
+			new(MultipleOfError),
+			context,
+			formatted,
+			ErrorDetails{"multiple": currentSubSchema.multipleOf.Number()},
+		)
+	}
+
+	if currentSubSchema.maximum != nil && !math.IsInf(value, -1) {
+		var err ResultError = new(NumberLTEError)
+		if currentSubSchema.exclusiveMaximum {
+			err = new(NumberLTError)
+		}
+		result.addInternalError(err, context, formatted, ErrorDetails{"max": currentSubSchema.maximum.Number()})
+	}
+
+	if currentSubSchema.minimum != nil && !math.IsInf(value, 1) {
+		var err ResultError = new(NumberGTEError)
+		if currentSubSchema.exclusiveMinimum {
+			err = new(NumberGTError)
+		}
+		result.addInternalError(err, context, formatted, ErrorDetails{"min": currentSubSchema.minimum.Number()})
+	}
+
+	result.incrementScore()
+}
+
 func (v *subSchema) validateNumber(currentSubSchema *subSchema, value interface{}, result *Result, context *JsonContext) {
 
 	// Ignore non numbers
-	if !isJsonNumber(value) {
+	number, isNumber := toJsonNumber(value)
+	if !isNumber {
 		return
 	}
 
@@ -842,19 +1102,20 @@ func (v *subSchema) validateNumber(currentSubSchema *subSchema, value interface{
 		internalLog("validateNumber %s", context.String())
 		internalLog(" %v", value)
 	}
-
-	number := value.(json.Number)
-	float64Value, _ := new(big.Float).SetString(string(number))
+	numberValue, isValidNumber := parseJsonNumber(number)
+	if !isValidNumber {
+		return
+	}
 
 	// multipleOf:
 	if currentSubSchema.multipleOf != nil {
 
-		if q := new(big.Float).Quo(float64Value, currentSubSchema.multipleOf); !q.IsInt() {
+		if !numberValue.IsMultipleOf(currentSubSchema.multipleOf) {
 			result.addInternalError(
 				new(MultipleOfError),
 				context,
 				resultErrorFormatJsonNumber(number),
-				ErrorDetails{"multiple": currentSubSchema.multipleOf},
+				ErrorDetails{"multiple": currentSubSchema.multipleOf.Number()},
 			)
 		}
 	}
@@ -862,24 +1123,24 @@ func (v *subSchema) validateNumber(currentSubSchema *subSchema, value interface{
 	//maximum & exclusiveMaximum:
 	if currentSubSchema.maximum != nil {
 		if currentSubSchema.exclusiveMaximum {
-			if float64Value.Cmp(currentSubSchema.maximum) >= 0 {
+			if numberValue.Cmp(currentSubSchema.maximum) >= 0 {
 				result.addInternalError(
 					new(NumberLTError),
 					context,
 					resultErrorFormatJsonNumber(number),
 					ErrorDetails{
-						"max": currentSubSchema.maximum,
+						"max": currentSubSchema.maximum.Number(),
 					},
 				)
 			}
 		} else {
-			if float64Value.Cmp(currentSubSchema.maximum) == 1 {
+			if numberValue.Cmp(currentSubSchema.maximum) == 1 {
 				result.addInternalError(
 					new(NumberLTEError),
 					context,
 					resultErrorFormatJsonNumber(number),
 					ErrorDetails{
-						"max": currentSubSchema.maximum,
+						"max": currentSubSchema.maximum.Number(),
 					},
 				)
 			}
@@ -889,25 +1150,24 @@ func (v *subSchema) validateNumber(currentSubSchema *subSchema, value interface{
 	//minimum & exclusiveMinimum:
 	if currentSubSchema.minimum != nil {
 		if currentSubSchema.exclusiveMinimum {
-			if float64Value.Cmp(currentSubSchema.minimum) <= 0 {
-				// if float64Value <= *currentSubSchema.minimum {
+			if numberValue.Cmp(currentSubSchema.minimum) <= 0 {
 				result.addInternalError(
 					new(NumberGTError),
 					context,
 					resultErrorFormatJsonNumber(number),
 					ErrorDetails{
-						"min": currentSubSchema.minimum,
+						"min": currentSubSchema.minimum.Number(),
 					},
 				)
 			}
 		} else {
-			if float64Value.Cmp(currentSubSchema.minimum) == -1 {
+			if numberValue.Cmp(currentSubSchema.minimum) == -1 {
 				result.addInternalError(
 					new(NumberGTEError),
 					context,
 					resultErrorFormatJsonNumber(number),
 					ErrorDetails{
-						"min": currentSubSchema.minimum,
+						"min": currentSubSchema.minimum.Number(),
 					},
 				)
 			}
@@ -916,7 +1176,8 @@ func (v *subSchema) validateNumber(currentSubSchema *subSchema, value interface{
 
 	// format
 	if currentSubSchema.format != "" {
-		if !FormatCheckers.IsFormat(currentSubSchema.format, float64Value) {
+		float64Value, _ := new(big.Float).SetString(string(number))
+		if !currentSubSchema.formatCheckers().IsFormat(currentSubSchema.format, float64Value) {
 			result.addInternalError(
 				new(DoesNotMatchFormatError),
 				context,
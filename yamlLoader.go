@@ -0,0 +1,267 @@
+package gojsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"math/big"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/xeipuuv/gojsonreference"
+	"gopkg.in/yaml.v3"
+)
+
+// YAML loaders
+// YAML is converted to the JSON data model while loading, so schemas and documents
+// written in YAML behave exactly like their JSON equivalent
+
+type yamlLoader struct {
+	source string
+}
+
+func (l *yamlLoader) JsonSource() interface{} {
+	return l.source
+}
+
+func (l *yamlLoader) JsonReference() (gojsonreference.JsonReference, error) {
+	return gojsonreference.NewJsonReference("#")
+}
+
+func (l *yamlLoader) LoaderFactory() JSONLoaderFactory {
+	return &DefaultJSONLoaderFactory{}
+}
+
+// NewYAMLLoader returns a loader for a YAML schema or document held in memory.
+func NewYAMLLoader(source string) *yamlLoader {
+	return &yamlLoader{source: source}
+}
+
+func (l *yamlLoader) LoadJSON() (interface{}, error) {
+	return decodeYAML(strings.NewReader(l.JsonSource().(string)))
+}
+
+// NewYAMLFileLoader returns a loader for a YAML file on the local OS file system.
+// The file is loaded as a reference, so relative $ref's are resolved against its location.
+func NewYAMLFileLoader(path string) *jsonReferenceLoader {
+	abs, err := filepath.Abs(path)
+	if err == nil {
+		path = abs
+	}
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		// windows drive letters
+		path = "/" + path
+	}
+	return NewReferenceLoader("file://" + path)
+}
+
+// isYAMLReference checks whether a referenced document should be decoded as YAML
+func isYAMLReference(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+func decodeYAML(r io.Reader) (interface{}, error) {
+
+	var node yaml.Node
+
+	err := yaml.NewDecoder(r).Decode(&node)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &yamlDecoder{aliases: map[*yaml.Node]bool{}}
+	return d.toJSON(&node)
+}
+
+// yamlDecoder converts a YAML node tree, counting the converted nodes to bound the expansion of aliases
+type yamlDecoder struct {
+	// aliased nodes currently being expanded, to detect recursive aliases
+	aliases map[*yaml.Node]bool
+	// converted nodes, and the ones among them converted within the expansion of an alias
+	decodeCount int
+	aliasCount  int
+}
+
+// yamlAliasRatio returns the share of the converted nodes allowed to come from aliases, like yaml.v3
+// does when decoding to Go values: almost all of small documents, 10% of the huge ones
+func yamlAliasRatio(decodeCount int) float64 {
+	switch {
+	case decodeCount <= 400000:
+		return 0.99
+	case decodeCount >= 4000000:
+		return 0.10
+	}
+	return 0.99 - 0.89*(float64(decodeCount-400000)/3600000)
+}
+
+// JSON numbers according to RFC 7159, YAML numbers in this form can be used verbatim
+var rxJSONNumber = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// toJSON converts a YAML node tree to the structures produced by decodeJsonUsingNumber
+func (d *yamlDecoder) toJSON(node *yaml.Node) (interface{}, error) {
+
+	d.decodeCount++
+	if len(d.aliases) > 0 {
+		d.aliasCount++
+		// aliases of aliases expand exponentially ( billion laughs )
+		if d.aliasCount > 100 && d.decodeCount > 1000 && float64(d.aliasCount)/float64(d.decodeCount) > yamlAliasRatio(d.decodeCount) {
+			return nil, yamlError(Locale.YAMLExcessiveAliasing(), node, ErrorDetails{})
+		}
+	}
+
+	switch node.Kind {
+
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return d.toJSON(node.Content[0])
+
+	case yaml.AliasNode:
+		if d.aliases[node.Alias] {
+			return nil, yamlError(Locale.YAMLRecursiveAlias(), node, ErrorDetails{"alias": node.Value})
+		}
+		d.aliases[node.Alias] = true
+		value, err := d.toJSON(node.Alias)
+		delete(d.aliases, node.Alias)
+		return value, err
+
+	case yaml.SequenceNode:
+		res := make([]interface{}, 0, len(node.Content))
+		for _, item := range node.Content {
+			value, err := d.toJSON(item)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, value)
+		}
+		return res, nil
+
+	case yaml.MappingNode:
+		res := map[string]interface{}{}
+		var merges []*yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyNode, valueNode := node.Content[i], node.Content[i+1]
+			if keyNode.Kind == yaml.ScalarNode && keyNode.ShortTag() == "!!merge" {
+				merges = append(merges, valueNode)
+				continue
+			}
+			if keyNode.Kind != yaml.ScalarNode || keyNode.ShortTag() != "!!str" {
+				return nil, yamlError(Locale.YAMLKeyMustBeString(), keyNode, ErrorDetails{"key": keyNode.Value})
+			}
+			value, err := d.toJSON(valueNode)
+			if err != nil {
+				return nil, err
+			}
+			res[keyNode.Value] = value
+		}
+		// merge keys ( << ) never override keys that are explicitly set
+		for _, merge := range merges {
+			if err := d.merge(res, merge); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+
+	case yaml.ScalarNode:
+		return yamlScalarToJSON(node)
+	}
+
+	return nil, yamlError(Locale.YAMLInvalidValue(), node, ErrorDetails{"value": node.Value})
+}
+
+func (d *yamlDecoder) merge(res map[string]interface{}, merge *yaml.Node) error {
+
+	value, err := d.toJSON(merge)
+	if err != nil {
+		return err
+	}
+
+	var sources []interface{}
+	if list, ok := value.([]interface{}); ok && merge.Kind == yaml.SequenceNode {
+		sources = list
+	} else {
+		sources = []interface{}{value}
+	}
+
+	for _, source := range sources {
+		m, ok := source.(map[string]interface{})
+		if !ok {
+			return yamlError(Locale.YAMLInvalidMerge(), merge, ErrorDetails{})
+		}
+		for k, v := range m {
+			if _, exists := res[k]; !exists {
+				res[k] = v
+			}
+		}
+	}
+
+	return nil
+}
+
+func yamlScalarToJSON(node *yaml.Node) (interface{}, error) {
+
+	switch node.ShortTag() {
+
+	case "!!null":
+		return nil, nil
+
+	case "!!bool":
+		var b bool
+		if err := node.Decode(&b); err != nil {
+			return nil, err
+		}
+		return b, nil
+
+	case "!!int":
+		if rxJSONNumber.MatchString(node.Value) {
+			return json.Number(node.Value), nil
+		}
+		// hexadecimal, octal, binary and underscore separated integers
+		i, ok := new(big.Int).SetString(node.Value, 0)
+		if !ok {
+			return nil, yamlError(Locale.YAMLInvalidValue(), node, ErrorDetails{"value": node.Value})
+		}
+		return json.Number(i.String()), nil
+
+	case "!!float":
+		if rxJSONNumber.MatchString(node.Value) {
+			return json.Number(node.Value), nil
+		}
+		var f float64
+		if err := node.Decode(&f); err != nil {
+			return nil, err
+		}
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return nil, yamlError(Locale.YAMLInvalidValue(), node, ErrorDetails{"value": node.Value})
+		}
+		return json.Number(strconv.FormatFloat(f, 'g', -1, 64)), nil
+	}
+
+	// strings, timestamps, binaries and custom tags are kept as strings
+	return node.Value, nil
+}
+
+func yamlError(format string, node *yaml.Node, details ErrorDetails) error {
+	details["line"] = node.Line
+	details["column"] = node.Column
+	return errors.New(formatErrorDescription(format, details))
+}
+
+// decodeReferencedDocument decodes a document loaded through a reference,
+// YAML is detected by the extension of the referenced file
+func decodeReferencedDocument(path string, body []byte) (interface{}, error) {
+	if isYAMLReference(path) {
+		return decodeYAML(bytes.NewReader(body))
+	}
+	return decodeJsonUsingNumber(bytes.NewReader(body))
+}
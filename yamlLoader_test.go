@@ -0,0 +1,174 @@
+package gojsonschema
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestYAMLLoaderScalars(t *testing.T) {
+	loader := NewYAMLLoader(`
+string: hello
+quoted: "42"
+int: 42
+hex: 0x1F
+float: 1.50
+exp: 1e3
+bool: true
+nothing: ~
+date: 2018-02-19
+list: [1, two]
+`)
+
+	doc, err := loader.LoadJSON()
+	assert.Nil(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"string":  "hello",
+		"quoted":  "42",
+		"int":     json.Number("42"),
+		"hex":     json.Number("31"),
+		"float":   json.Number("1.50"),
+		"exp":     json.Number("1e3"),
+		"bool":    true,
+		"nothing": nil,
+		"date":    "2018-02-19",
+		"list":    []interface{}{json.Number("1"), "two"},
+	}, doc)
+}
+
+func TestYAMLLoaderAnchorsAndMerge(t *testing.T) {
+	loader := NewYAMLLoader(`
+base: &base
+  a: 1
+  b: 2
+derived:
+  <<: *base
+  b: 3
+`)
+
+	doc, err := loader.LoadJSON()
+	assert.Nil(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"a": json.Number("1"),
+		"b": json.Number("3"),
+	}, doc.(map[string]interface{})["derived"])
+}
+
+func TestYAMLLoaderErrors(t *testing.T) {
+	_, err := NewYAMLLoader("a: 1\n2: b\n").LoadJSON()
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "YAML line 2, column 1: mapping key 2 must be a string", err.Error())
+	}
+
+	_, err = NewYAMLLoader("a:\n  b: .inf\n").LoadJSON()
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "YAML line 2, column 6: .inf cannot be represented in JSON", err.Error())
+	}
+
+	_, err = NewYAMLLoader("a: [1, 2\n").LoadJSON()
+	assert.NotNil(t, err)
+}
+
+func TestYAMLLoaderExcessiveAliasing(t *testing.T) {
+	// each level multiplies the size of the document by 10
+	source := "a: &a [x, x, x, x, x, x, x, x, x, x]\n"
+	for i, name := range "bcdefghi" {
+		prev := string("abcdefghi"[i])
+		source += string(name) + ": &" + string(name) + " [" + strings.Repeat("*"+prev+", ", 9) + "*" + prev + "]\n"
+	}
+
+	start := time.Now()
+	_, err := NewYAMLLoader(source).LoadJSON()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "document has excessive aliasing")
+	}
+	assert.True(t, time.Since(start) < 5*time.Second, "took %s", time.Since(start))
+
+	// aliases expanding less than the rest of the document are fine
+	_, err = NewYAMLLoader(strings.Repeat("- &a [1, 2]\n- *a\n", 1000)).LoadJSON()
+	assert.Nil(t, err)
+}
+
+func TestYAMLSchemaAndDocument(t *testing.T) {
+	schema, err := NewSchema(NewYAMLLoader(`
+type: object
+properties:
+  count:
+    type: integer
+    maximum: 10
+  ratio:
+    type: number
+    multipleOf: 0.5
+`))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	result, err := schema.Validate(NewYAMLLoader("count: 3\nratio: 1.5\n"))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	result, err = schema.Validate(NewYAMLLoader("count: 11\nratio: 0.7\n"))
+	assert.Nil(t, err)
+	assert.Len(t, result.Errors(), 2)
+}
+
+func TestYAMLFileLoaderWithReference(t *testing.T) {
+	schema, err := NewSchema(NewYAMLFileLoader(filepath.Join("testdata", "yaml", "person.yaml")))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	result, err := schema.Validate(NewYAMLLoader(`
+name: Ada
+age: 36
+address:
+  street: Main street
+  zip: "12345"
+`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	result, err = schema.Validate(NewYAMLLoader(`
+name: Ada
+address:
+  street: Main street
+  zip: 1234
+`))
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, "address.zip", result.Errors()[0].Field())
+	}
+}
+
+func TestValidateNativeGoNumbers(t *testing.T) {
+	schema, err := NewSchema(NewStringLoader(`{
+		"type": "object",
+		"properties": {
+			"a": {"type": "integer", "minimum": 5},
+			"b": {"type": "array", "items": {"type": "number"}}
+		}
+	}`))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	result, err := schema.Validate(NewRawLoader(map[interface{}]interface{}{
+		"a": 7,
+		"b": []float64{1.5, 2},
+	}))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	result, err = schema.Validate(NewRawLoader(map[string]interface{}{
+		"a": 3.5,
+	}))
+	assert.Nil(t, err)
+	assert.Len(t, result.Errors(), 1)
+}